@@ -33,15 +33,34 @@ const (
 	//PrivURLPrefix = RESTPrefix + PrivateX + APIVersion + "/"
 	XSnapshot    = "/snapshot"
 	XGenereation = "/generation"
+	XRDFGroup    = "/rdf_group"
 )
 
+// CloneSnapshotPrefix is prefixed to the name of a clone to name the snapshot CloneVolume links to it,
+// unless a snapshot name is given in the options
+const CloneSnapshotPrefix = "clone-"
+
+var (
+	// MAXSnapshotStateRetryCount is the maximum number of times the state of a snapshot
+	// is checked while waiting for it to be restored.
+	// It is a variable so that unit testing can set it lower.
+	MAXSnapshotStateRetryCount = 60
+	// SnapshotStateRetrySleepDuration is the amount of time between the checks of a snapshot state.
+	SnapshotStateRetrySleepDuration = 5 * time.Second
+)
+
+// DefaultLinkStateTimeout is how long WaitForLinkState waits for the state of a link when no timeout is given
+const DefaultLinkStateTimeout = 5 * time.Minute
+
 func (c *Client) privURLPrefix() string {
 	return RESTPrefix + PrivateX + c.version + "/"
 }
 
 // GetSnapVolumeList returns a list of all snapshot volumes on the array.
-func (c *Client) GetSnapVolumeList(symID string, queryParams types.QueryParams) (*types.SymVolumeList, error) {
+func (c *Client) GetSnapVolumeList(symID string, queryParams types.QueryParams) (_ *types.SymVolumeList, err error) {
 	defer c.TimeSpent("GetSnapVolumeList", time.Now())
+	c, endSpan := c.traceOperation("GetSnapVolumeList", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -79,8 +98,10 @@ func (c *Client) GetSnapVolumeList(symID string, queryParams types.QueryParams)
 }
 
 // GetVolumeSnapInfo returns snapVx information associated with a volume.
-func (c *Client) GetVolumeSnapInfo(symID string, volumeID string) (*types.SnapshotVolumeGeneration, error) {
+func (c *Client) GetVolumeSnapInfo(symID string, volumeID string) (_ *types.SnapshotVolumeGeneration, err error) {
 	defer c.TimeSpent("GetVolumeSnapInfo", time.Now())
+	c, endSpan := c.traceOperation("GetVolumeSnapInfo", AttributeSymmetrixID, symID, AttributeVolumeID, volumeID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -104,9 +125,27 @@ func (c *Client) GetVolumeSnapInfo(symID string, volumeID string) (*types.Snapsh
 	return snapinfo, nil
 }
 
+// GetVolumeSnapshotGenerations returns the generations of all the snapshots of a volume
+func (c *Client) GetVolumeSnapshotGenerations(symID string, volumeID string) (_ types.SnapshotGenerations, err error) {
+	defer c.TimeSpent("GetVolumeSnapshotGenerations", time.Now())
+	c, endSpan := c.traceOperation("GetVolumeSnapshotGenerations", AttributeSymmetrixID, symID, AttributeVolumeID, volumeID)
+	defer endSpan(&err)
+	snapInfo, err := c.GetVolumeSnapInfo(symID, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	generations := make(types.SnapshotGenerations, 0, len(snapInfo.VolumeSnapshotSource))
+	for i := range snapInfo.VolumeSnapshotSource {
+		generations = append(generations, *types.NewSnapshotGeneration(volumeID, &snapInfo.VolumeSnapshotSource[i]))
+	}
+	return generations, nil
+}
+
 // GetSnapshotInfo returns snapVx information of the specified snapshot
-func (c *Client) GetSnapshotInfo(symID, volumeID, snapID string) (*types.VolumeSnapshot, error) {
+func (c *Client) GetSnapshotInfo(symID, volumeID, snapID string) (_ *types.VolumeSnapshot, err error) {
 	defer c.TimeSpent("GetSnapshotInfo", time.Now())
+	c, endSpan := c.traceOperation("GetSnapshotInfo", AttributeSymmetrixID, symID, AttributeVolumeID, volumeID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -133,21 +172,47 @@ func (c *Client) GetSnapshotInfo(symID, volumeID, snapID string) (*types.VolumeS
 // Star flag is used if the source device is participating in SRDF star mode
 // Use the Force flag to automate some scenarios to succeed
 // TimeToLive value ins hour is set on the snapshot to automatically delete the snapshot after target is unlinked
-func (c *Client) CreateSnapshot(symID string, snapID string, sourceVolumeList []types.VolumeList, ttl int64) error {
+func (c *Client) CreateSnapshot(symID string, snapID string, sourceVolumeList []types.VolumeList, ttl int64) (err error) {
 	defer c.TimeSpent("CreateSnapshot", time.Now())
+	c, endSpan := c.traceOperation("CreateSnapshot", AttributeSymmetrixID, symID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
+	return c.CreateSnapshotWithOptions(symID, snapID, sourceVolumeList, &types.SnapshotOptions{TimeToLive: ttl})
+}
+
+// CreateSnapshotWithOptions creates a snapVx snapshot of the list of volumes passed as sourceVolumeList
+// with a time to live in days, or in hours if TimeInHours is set. A secure snapshot cannot be deleted
+// or have its time to live reduced before it expires, so a time to live is required to create one
+func (c *Client) CreateSnapshotWithOptions(symID string, snapID string, sourceVolumeList []types.VolumeList, options *types.SnapshotOptions) (err error) {
+	defer c.TimeSpent("CreateSnapshotWithOptions", time.Now())
+	c, endSpan := c.traceOperation("CreateSnapshotWithOptions", AttributeSymmetrixID, symID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return err
 	}
+	return c.createSnapshot(symID, snapID, sourceVolumeList, options, false)
+}
+
+func (c *Client) createSnapshot(symID string, snapID string, sourceVolumeList []types.VolumeList, options *types.SnapshotOptions, consistent bool) error {
+	if options == nil {
+		options = &types.SnapshotOptions{}
+	}
+	if options.Secure && options.TimeToLive <= 0 {
+		return fmt.Errorf("a time to live is required for the secure snapshot %s", snapID)
+	}
 	snapParam := &types.CreateVolumesSnapshot{
 		SourceVolumeList: sourceVolumeList,
 		BothSides:        false,
 		Star:             false,
 		Force:            false,
-		TimeToLive:       ttl,
+		TimeInHours:      options.TimeInHours,
+		TimeToLive:       options.TimeToLive,
+		Consistent:       consistent,
 		ExecutionOption:  types.ExecutionOptionSynchronous,
 	}
-	Debug = true
-	ifDebugLogPayload(snapParam)
+	if options.Secure {
+		snapParam.TimeToLive = 0
+		snapParam.Securettl = options.TimeToLive
+	}
 	URL := c.privURLPrefix() + ReplicationX + SymmetrixX + symID + XSnapshot + "/" + snapID
 	err := c.api.Post(context.Background(), URL, c.getDefaultHeaders(), snapParam, nil)
 	if err != nil {
@@ -164,23 +229,82 @@ func (c *Client) CreateSnapshot(symID string, snapID string, sourceVolumeList []
 // Restore, when set to true will terminate the Restore and the Snapshot as well
 // Generation is used to tell which generation of snapshot needs to be deleted and is passed as int64
 // ExecutionOption tells the Unisphere to perform the operation either in Synchronous mode or Asynchronous mode
-func (c *Client) DeleteSnapshot(symID, snapID string, sourceVolumes []types.VolumeList, generation int64) error {
+func (c *Client) DeleteSnapshot(symID, snapID string, sourceVolumes []types.VolumeList, generation int64) (err error) {
 	defer c.TimeSpent("DeleteSnapshot", time.Now())
+	c, endSpan := c.traceOperation("DeleteSnapshot", AttributeSymmetrixID, symID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return err
+	}
+	if err := c.deleteSnapshot(symID, snapID, sourceVolumes, generation, false); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Snapshot (%s) deleted successfully", snapID))
+	return nil
+}
+
+// RestoreSnapshot restores a generation of a snapshot to its source volumes, waits for
+// the volumes to reach the Restored state and then terminates the restore session.
+// The snapshot is kept and can be restored again or deleted afterwards
+func (c *Client) RestoreSnapshot(symID, snapID string, sourceVolumes []types.VolumeList, generation int64) (err error) {
+	defer c.TimeSpent("RestoreSnapshot", time.Now())
+	c, endSpan := c.traceOperation("RestoreSnapshot", AttributeSymmetrixID, symID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return err
 	}
+	err = c.ModifySnapshot(symID, sourceVolumes, nil, snapID, "Restore", "", generation)
+	if err != nil {
+		return err
+	}
+	if err = c.waitForSnapshotRestore(symID, snapID, sourceVolumes, generation); err != nil {
+		return err
+	}
+	// terminating the restore session leaves the snapshot in place
+	if err = c.deleteSnapshot(symID, snapID, sourceVolumes, generation, true); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Snapshot (%s) restored successfully", snapID))
+	return nil
+}
+
+// waitForSnapshotRestore waits for a generation of a snapshot to be restored on all of its source volumes
+func (c *Client) waitForSnapshotRestore(symID, snapID string, sourceVolumes []types.VolumeList, generation int64) error {
+	for i := 0; i < MAXSnapshotStateRetryCount; i++ {
+		if i > 0 {
+			c.notifyRetry("waitForSnapshotRestore", i+1, nil)
+		}
+		restored := true
+		for _, sourceVolume := range sourceVolumes {
+			generationInfo, err := c.GetSnapshotGenerationInfo(symID, sourceVolume.Name, snapID, generation)
+			if err != nil {
+				return err
+			}
+			if generationInfo.VolumeSnapshotSource.State != string(types.SnapshotStateRestored) {
+				restored = false
+				break
+			}
+		}
+		if restored {
+			return nil
+		}
+		time.Sleep(SnapshotStateRetrySleepDuration)
+	}
+	return fmt.Errorf("Snapshot (%s) was not restored after %d retries", snapID, MAXSnapshotStateRetryCount)
+}
+
+// deleteSnapshot deletes a generation of a snapshot, or only terminates its restore session if restore is set
+func (c *Client) deleteSnapshot(symID, snapID string, sourceVolumes []types.VolumeList, generation int64, restore bool) error {
 	deleteSnapshot := &types.DeleteVolumeSnapshot{
 		DeviceNameListSource: sourceVolumes,
 		Symforce:             false,
 		Star:                 false,
 		Force:                false,
-		Restore:              false,
+		Restore:              restore,
 		Generation:           generation,
 		ExecutionOption:      types.ExecutionOptionAsynchronous,
 	}
 	job := &types.Job{}
-	Debug = true
-	ifDebugLogPayload(deleteSnapshot)
 	URL := c.privURLPrefix() + ReplicationX + SymmetrixX + symID + XSnapshot + "/" + snapID
 	URL = strings.Replace(URL, "/90/", "/91/", 1)
 	err := c.api.DoWithHeaders(context.Background(), http.MethodDelete, URL, c.getDefaultHeaders(), deleteSnapshot, job)
@@ -192,9 +316,12 @@ func (c *Client) DeleteSnapshot(symID, snapID string, sourceVolumes []types.Volu
 		return err
 	}
 	if job.Status == types.JobStatusFailed || job.Status == types.JobStatusRunning {
-		return fmt.Errorf("Job status not successful for snapshot delete. Job status = %s and Job result = %s", job.Status, job.Result)
+		operation := "delete"
+		if restore {
+			operation = "restore terminate"
+		}
+		return fmt.Errorf("Job status not successful for snapshot %s. Job status = %s and Job result = %s", operation, job.Status, job.Result)
 	}
-	log.Info(fmt.Sprintf("Snapshot (%s) deleted successfully", snapID))
 	return nil
 }
 
@@ -214,8 +341,10 @@ func (c *Client) DeleteSnapshot(symID, snapID string, sourceVolumes []types.Volu
 // Action defined the operation which will be performed on the given snapshot
 func (c *Client) ModifySnapshot(symID string, sourceVol []types.VolumeList,
 	targetVol []types.VolumeList, snapID string, action string,
-	newSnapID string, generation int64) error {
+	newSnapID string, generation int64) (err error) {
 	defer c.TimeSpent("ModifySnapshot", time.Now())
+	c, endSpan := c.traceOperation("ModifySnapshot", AttributeSymmetrixID, symID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
 
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return err
@@ -246,9 +375,274 @@ func (c *Client) ModifySnapshot(symID string, sourceVol []types.VolumeList,
 			Action:               action,
 			ExecutionOption:      types.ExecutionOptionAsynchronous,
 		}
+	case "Restore":
+		snapParam = &types.ModifyVolumeSnapshot{
+			VolumeNameListSource: sourceVol,
+			Action:               action,
+			Generation:           generation,
+			ExecutionOption:      types.ExecutionOptionAsynchronous,
+		}
 	default:
 		return fmt.Errorf("not a supported action on Snapshots")
 	}
+	return c.modifySnapshot(symID, snapID, "ModifySnapshot", snapParam)
+}
+
+// SetSnapshotTTL sets the time to live of a generation of a snapshot, in days or in hours if TimeInHours is set.
+// When Secure is set the generation becomes a secure snapshot, the time to live of a secure snapshot can only be extended
+func (c *Client) SetSnapshotTTL(symID, snapID string, sourceVolumes []types.VolumeList, generation int64, options *types.SnapshotOptions) (err error) {
+	defer c.TimeSpent("SetSnapshotTTL", time.Now())
+	c, endSpan := c.traceOperation("SetSnapshotTTL", AttributeSymmetrixID, symID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return err
+	}
+	if options == nil || options.TimeToLive < 0 || (options.Secure && options.TimeToLive == 0) {
+		return fmt.Errorf("a valid time to live is required for the snapshot %s", snapID)
+	}
+	snapParam := &types.ModifyVolumeSnapshot{
+		VolumeNameListSource: sourceVolumes,
+		TimeInHours:          options.TimeInHours,
+		Action:               types.SnapshotActionSetTimeToLive,
+		Generation:           generation,
+		ExecutionOption:      types.ExecutionOptionAsynchronous,
+	}
+	if options.Secure {
+		snapParam.Action = types.SnapshotActionSetSecure
+		snapParam.SecureTTL = options.TimeToLive
+	} else {
+		snapParam.TTL = options.TimeToLive
+	}
+	return c.modifySnapshot(symID, snapID, "SetSnapshotTTL", snapParam)
+}
+
+// GetSnapshotRetention returns the generations of a snapshot of a volume with their retention:
+// whether they are secure or expired, their time to live and their creation time
+func (c *Client) GetSnapshotRetention(symID, volumeID, snapID string) (_ types.SnapshotGenerations, err error) {
+	defer c.TimeSpent("GetSnapshotRetention", time.Now())
+	c, endSpan := c.traceOperation("GetSnapshotRetention", AttributeSymmetrixID, symID, AttributeVolumeID, volumeID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
+	privVolume, err := c.GetPrivVolumeByID(symID, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	generations := make(types.SnapshotGenerations, 0)
+	for _, session := range privVolume.TimeFinderInfo.SnapVXSession {
+		for i := range session.SourceSnapshotGenInfo {
+			if session.SourceSnapshotGenInfo[i].SnapshotHeader.SnapshotName != snapID {
+				continue
+			}
+			generation := types.NewSnapshotGenerationFromHeader(&session.SourceSnapshotGenInfo[i])
+			generation.VolumeID = volumeID
+			generations = append(generations, *generation)
+		}
+	}
+	if len(generations) == 0 {
+		return nil, fmt.Errorf("Snapshot (%s) cannot be found on volume (%s)", snapID, volumeID)
+	}
+	return generations, nil
+}
+
+// LinkSnapshot links a generation of a snapshot to the target volumes with the given options.
+// In copy mode all the data of the snapshot is copied to the targets, which become fully independent
+// once the link reaches the Copied state, otherwise the targets only reference the snapshot data.
+// Exact pairs the source and target volumes in their ordinal positions instead of by best match.
+// Relink moves targets already linked to another snapshot or generation of the sources to this generation.
+// Remote propagates the data to the remote mirrors of SRDF protected targets
+func (c *Client) LinkSnapshot(symID, snapID string, sourceVolumes, targetVolumes []types.VolumeList, generation int64, options *types.LinkSnapshotOptions) (err error) {
+	defer c.TimeSpent("LinkSnapshot", time.Now())
+	c, endSpan := c.traceOperation("LinkSnapshot", AttributeSymmetrixID, symID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return err
+	}
+	if options == nil {
+		options = &types.LinkSnapshotOptions{}
+	}
+	action := "Link"
+	if options.Relink {
+		action = "Relink"
+	}
+	snapParam := &types.ModifyVolumeSnapshot{
+		VolumeNameListSource: sourceVolumes,
+		VolumeNameListTarget: targetVolumes,
+		Exact:                options.Exact,
+		Copy:                 options.Copy,
+		NoCopy:               !options.Copy,
+		Remote:               options.Remote,
+		Action:               action,
+		Generation:           generation,
+		ExecutionOption:      types.ExecutionOptionAsynchronous,
+	}
+	return c.modifySnapshot(symID, snapID, "LinkSnapshot", snapParam)
+}
+
+// CreateConsistentSnapshot creates a snapshot of all the volumes in sourceVolumeList at the same point in time,
+// with the retention options of CreateSnapshotWithOptions, so that the snapshot of a set of volumes, such as
+// the data and log volumes of a database, can be restored or linked as a whole. All the volumes must be distinct
+// volumes of the array. It returns the generation of the new snapshot on each volume, in the order of sourceVolumeList
+func (c *Client) CreateConsistentSnapshot(symID, snapID string, sourceVolumeList []types.VolumeList, options *types.SnapshotOptions) (_ *types.ConsistentSnapshot, err error) {
+	defer c.TimeSpent("CreateConsistentSnapshot", time.Now())
+	c, endSpan := c.traceOperation("CreateConsistentSnapshot", AttributeSymmetrixID, symID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if len(sourceVolumeList) == 0 {
+		return nil, fmt.Errorf("at least one source volume is required for the consistent snapshot %s", snapID)
+	}
+	volumeIDs := make(map[string]bool)
+	for _, sourceVolume := range sourceVolumeList {
+		if volumeIDs[sourceVolume.Name] {
+			return nil, fmt.Errorf("volume %s is given more than once for the consistent snapshot %s", sourceVolume.Name, snapID)
+		}
+		volumeIDs[sourceVolume.Name] = true
+		if _, err := c.GetVolumeByID(symID, sourceVolume.Name); err != nil {
+			return nil, fmt.Errorf("volume %s of the consistent snapshot %s cannot be found on array %s: %s", sourceVolume.Name, snapID, symID, err.Error())
+		}
+	}
+	if err := c.createSnapshot(symID, snapID, sourceVolumeList, options, true); err != nil {
+		return nil, err
+	}
+	snapshot := &types.ConsistentSnapshot{
+		SymmetrixID:  symID,
+		SnapshotName: snapID,
+	}
+	for _, sourceVolume := range sourceVolumeList {
+		generations, err := c.GetVolumeSnapshotGenerations(symID, sourceVolume.Name)
+		if err != nil {
+			return nil, err
+		}
+		latest := generations.Named(snapID).Latest()
+		if latest == nil {
+			return nil, fmt.Errorf("Snapshot (%s) cannot be found on volume (%s)", snapID, sourceVolume.Name)
+		}
+		if latest.Timestamp.After(snapshot.Timestamp) {
+			snapshot.Timestamp = latest.Timestamp
+		}
+		snapshot.Volumes = append(snapshot.Volumes, types.ConsistentSnapshotVolume{
+			VolumeID:   sourceVolume.Name,
+			Generation: latest.Generation,
+			Timestamp:  latest.Timestamp,
+		})
+	}
+	log.Info(fmt.Sprintf("Successfully created consistent Snapshot (%s) of %d volumes", snapID, len(snapshot.Volumes)))
+	return snapshot, nil
+}
+
+// LinkConsistentSnapshot links the generations of a consistent snapshot to the target volumes, pairing each
+// source volume with the target volume in the same position, with the options of LinkSnapshot. The generations
+// are looked up by the time they were taken, as their numbers change when the snapshot is taken again, and
+// it is an error if one of them no longer exists
+func (c *Client) LinkConsistentSnapshot(symID string, snapshot *types.ConsistentSnapshot, targetVolumes []types.VolumeList, options *types.LinkSnapshotOptions) (err error) {
+	defer c.TimeSpent("LinkConsistentSnapshot", time.Now())
+	c, endSpan := c.traceOperation("LinkConsistentSnapshot", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return err
+	}
+	if len(snapshot.Volumes) == 0 {
+		return fmt.Errorf("the consistent snapshot %s has no source volumes", snapshot.SnapshotName)
+	}
+	if len(snapshot.Volumes) != len(targetVolumes) {
+		return fmt.Errorf("the consistent snapshot %s has %d source volumes but %d target volumes were given",
+			snapshot.SnapshotName, len(snapshot.Volumes), len(targetVolumes))
+	}
+	linkOptions := types.LinkSnapshotOptions{}
+	if options != nil {
+		linkOptions = *options
+	}
+	linkOptions.Exact = true
+	generations := make([]int64, len(snapshot.Volumes))
+	sameGeneration := true
+	for i, volume := range snapshot.Volumes {
+		if generations[i], err = c.getConsistentSnapshotGeneration(symID, snapshot.SnapshotName, volume); err != nil {
+			return err
+		}
+		sameGeneration = sameGeneration && generations[i] == generations[0]
+	}
+	if sameGeneration {
+		return c.LinkSnapshot(symID, snapshot.SnapshotName, snapshot.SourceVolumes(), targetVolumes, generations[0], &linkOptions)
+	}
+	// The generations differ when the snapshot was taken again on some of the volumes only, link them one by one
+	for i, volume := range snapshot.Volumes {
+		sourceVolumes := []types.VolumeList{{Name: volume.VolumeID}}
+		err := c.LinkSnapshot(symID, snapshot.SnapshotName, sourceVolumes, targetVolumes[i:i+1], generations[i], &linkOptions)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getConsistentSnapshotGeneration returns the current number of the generation of a consistent snapshot on a volume
+func (c *Client) getConsistentSnapshotGeneration(symID, snapID string, volume types.ConsistentSnapshotVolume) (int64, error) {
+	generations, err := c.GetVolumeSnapshotGenerations(symID, volume.VolumeID)
+	if err != nil {
+		return 0, err
+	}
+	for _, generation := range generations.Named(snapID) {
+		if generation.Timestamp.Equal(volume.Timestamp) {
+			return generation.Generation, nil
+		}
+	}
+	return 0, fmt.Errorf("the generation of Snapshot (%s) taken at %v no longer exists on volume (%s)", snapID, volume.Timestamp, volume.VolumeID)
+}
+
+// WaitForLinkState waits for the link of a snapshot of the source volume to the target volume to be
+// defined and in the given state, Linked for a link in nocopy mode or Copied for a link in copy mode.
+// The link is checked every SnapshotStateRetrySleepDuration until the timeout has elapsed,
+// DefaultLinkStateTimeout if the timeout is not positive. It returns the link information once the state is reached
+func (c *Client) WaitForLinkState(symID, snapID, sourceVolumeID, targetVolumeID, state string, timeout time.Duration) (_ *types.LinkSnapshotGenInfo, err error) {
+	defer c.TimeSpent("WaitForLinkState", time.Now())
+	c, endSpan := c.traceOperation("WaitForLinkState", AttributeSymmetrixID, symID, AttributeSnapshotID, snapID, AttributeVolumeID, sourceVolumeID)
+	defer endSpan(&err)
+	if timeout <= 0 {
+		timeout = DefaultLinkStateTimeout
+	}
+	retries := int(timeout / SnapshotStateRetrySleepDuration)
+	for i := 0; i <= retries; i++ {
+		if i > 0 {
+			c.notifyRetry("WaitForLinkState", i+1, nil)
+		}
+		link, err := c.getSnapshotLink(symID, snapID, sourceVolumeID, targetVolumeID)
+		if err != nil {
+			return nil, err
+		}
+		if link.State == state && link.Defined {
+			return link, nil
+		}
+		log.Debug(fmt.Sprintf("Link of Snapshot (%s) to volume (%s) is in state %s, defined %t", snapID, targetVolumeID, link.State, link.Defined))
+		if i < retries {
+			time.Sleep(SnapshotStateRetrySleepDuration)
+		}
+	}
+	return nil, fmt.Errorf("Link of Snapshot (%s) to volume (%s) did not reach state %s within %v", snapID, targetVolumeID, state, timeout)
+}
+
+// getSnapshotLink returns the link of a snapshot of the source volume to the target volume
+func (c *Client) getSnapshotLink(symID, snapID, sourceVolumeID, targetVolumeID string) (*types.LinkSnapshotGenInfo, error) {
+	privVolume, err := c.GetPrivVolumeByID(symID, sourceVolumeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range privVolume.TimeFinderInfo.SnapVXSession {
+		for _, sourceInfo := range session.SourceSnapshotGenInfo {
+			if sourceInfo.SnapshotHeader.SnapshotName != snapID {
+				continue
+			}
+			for i := range sourceInfo.LinkSnapshotGenInfo {
+				if sourceInfo.LinkSnapshotGenInfo[i].TargetDevice == targetVolumeID {
+					return &sourceInfo.LinkSnapshotGenInfo[i], nil
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("Snapshot (%s) of volume (%s) is not linked to volume (%s)", snapID, sourceVolumeID, targetVolumeID)
+}
+
+func (c *Client) modifySnapshot(symID, snapID, functionName string, snapParam *types.ModifyVolumeSnapshot) error {
+	ifDebugLogPayload(snapParam)
 	URL := c.privURLPrefix() + ReplicationX + SymmetrixX + symID + XSnapshot + "/" + snapID
 	job := &types.Job{}
 	fields := map[string]interface{}{
@@ -258,7 +652,7 @@ func (c *Client) ModifySnapshot(symID string, sourceVol []types.VolumeList,
 	err := c.api.Put(
 		context.Background(), URL, c.getDefaultHeaders(), snapParam, job)
 	if err != nil {
-		log.WithFields(fields).Error("Error in ModifySnapshot: " + err.Error())
+		log.WithFields(fields).Error("Error in " + functionName + ": " + err.Error())
 		return err
 	}
 	job, err = c.WaitOnJobCompletion(symID, job.JobID)
@@ -266,15 +660,17 @@ func (c *Client) ModifySnapshot(symID string, sourceVol []types.VolumeList,
 		return err
 	}
 	if job.Status == types.JobStatusFailed || job.Status == types.JobStatusRunning {
-		return fmt.Errorf("Job status not successful for snapshot %s. Job status = %s and Job result = %s", action, job.Status, job.Result)
+		return fmt.Errorf("Job status not successful for snapshot %s. Job status = %s and Job result = %s", snapParam.Action, job.Status, job.Result)
 	}
-	log.Info(fmt.Sprintf("Action (%s) on Snapshot (%s) is successful", action, snapID))
+	log.Info(fmt.Sprintf("Action (%s) on Snapshot (%s) is successful", snapParam.Action, snapID))
 	return nil
 }
 
 // GetPrivVolumeByID returns a Volume structure given the symmetrix and volume ID
-func (c *Client) GetPrivVolumeByID(symID string, volumeID string) (*types.VolumeResultPrivate, error) {
+func (c *Client) GetPrivVolumeByID(symID string, volumeID string) (_ *types.VolumeResultPrivate, err error) {
 	defer c.TimeSpent("GetPrivVolumeByID", time.Now())
+	c, endSpan := c.traceOperation("GetPrivVolumeByID", AttributeSymmetrixID, symID, AttributeVolumeID, volumeID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -312,14 +708,16 @@ func (c *Client) GetPrivVolumeByID(symID string, volumeID string) (*types.Volume
 }
 
 // GetSnapshotGenerations returns a list of all the snapshot generation on a specific snapshot
-func (c *Client) GetSnapshotGenerations(symID, volumeID, snapID string) (*types.VolumeSnapshotGenerations, error) {
+func (c *Client) GetSnapshotGenerations(symID, volumeID, snapID string) (_ *types.VolumeSnapshotGenerations, err error) {
 	defer c.TimeSpent("GetSnapshotGenerations", time.Now())
+	c, endSpan := c.traceOperation("GetSnapshotGenerations", AttributeSymmetrixID, symID, AttributeVolumeID, volumeID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
 	URL := c.privURLPrefix() + ReplicationX + SymmetrixX + symID + XVolume + "/" + volumeID + XSnapshot + "/" + snapID + XGenereation
 	volumeSnapshotGenerations := new(types.VolumeSnapshotGenerations)
-	err := c.api.Get(context.Background(), URL, c.getDefaultHeaders(), volumeSnapshotGenerations)
+	err = c.api.Get(context.Background(), URL, c.getDefaultHeaders(), volumeSnapshotGenerations)
 	if err != nil {
 		return nil, err
 	}
@@ -327,29 +725,685 @@ func (c *Client) GetSnapshotGenerations(symID, volumeID, snapID string) (*types.
 }
 
 // GetSnapshotGenerationInfo returns the specific generation info related to a snapshot
-func (c *Client) GetSnapshotGenerationInfo(symID, volumeID, snapID string, generation int64) (*types.VolumeSnapshotGeneration, error) {
+func (c *Client) GetSnapshotGenerationInfo(symID, volumeID, snapID string, generation int64) (_ *types.VolumeSnapshotGeneration, err error) {
 	defer c.TimeSpent("GetSnapshotGenerationInfo", time.Now())
+	c, endSpan := c.traceOperation("GetSnapshotGenerationInfo", AttributeSymmetrixID, symID, AttributeVolumeID, volumeID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
 	URL := c.privURLPrefix() + ReplicationX + SymmetrixX + symID + XVolume + "/" + volumeID + XSnapshot + "/" + snapID + XGenereation + "/" + strconv.FormatInt(generation, 10)
 	volumeSnapshotGeneration := new(types.VolumeSnapshotGeneration)
-	err := c.api.Get(context.Background(), URL, c.getDefaultHeaders(), volumeSnapshotGeneration)
+	err = c.api.Get(context.Background(), URL, c.getDefaultHeaders(), volumeSnapshotGeneration)
 	if err != nil {
 		return nil, err
 	}
 	return volumeSnapshotGeneration, nil
 }
 
+// CreateStorageGroupSnapshot creates a snapVx snapshot of all the volumes of a storage group in one operation
+// and returns the new generation of the snapshot. The retention options are those of CreateSnapshotWithOptions,
+// a time to live in days, or in hours if TimeInHours is set, and a time to live is required for a secure snapshot
+func (c *Client) CreateStorageGroupSnapshot(symID, sgID, snapID string, options *types.SnapshotOptions) (_ *types.StorageGroupSnapshot, err error) {
+	defer c.TimeSpent("CreateStorageGroupSnapshot", time.Now())
+	c, endSpan := c.traceOperation("CreateStorageGroupSnapshot", AttributeSymmetrixID, symID, AttributeStorageGroupID, sgID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if options == nil {
+		options = &types.SnapshotOptions{}
+	}
+	if options.Secure && options.TimeToLive <= 0 {
+		return nil, fmt.Errorf("a time to live is required for the secure snapshot %s", snapID)
+	}
+	snapParam := &types.CreateStorageGroupSnapshot{
+		SnapshotName:    snapID,
+		TimeToLive:      options.TimeToLive,
+		TimeInHours:     options.TimeInHours,
+		ExecutionOption: types.ExecutionOptionAsynchronous,
+	}
+	if options.Secure {
+		snapParam.TimeToLive = 0
+		snapParam.Securettl = options.TimeToLive
+	}
+	ifDebugLogPayload(snapParam)
+	URL := c.urlPrefix() + ReplicationX + SymmetrixX + symID + XStorageGroup + "/" + sgID + XSnapshot
+	job := &types.Job{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err = c.api.Post(ctx, URL, c.getDefaultHeaders(), snapParam, job)
+	if err != nil {
+		log.Error("CreateStorageGroupSnapshot failed: " + err.Error())
+		return nil, err
+	}
+	if err = c.waitOnJob(symID, job, "storage group snapshot create"); err != nil {
+		return nil, err
+	}
+	log.Info(fmt.Sprintf("Snapshot (%s) of StorageGroup (%s) created successfully", snapID, sgID))
+	return c.GetStorageGroupSnapshot(symID, sgID, snapID, 0)
+}
+
+// ListStorageGroupSnapshots returns the names of the snapshots of a storage group
+func (c *Client) ListStorageGroupSnapshots(symID, sgID string) (_ *types.StorageGroupSnapshotList, err error) {
+	defer c.TimeSpent("ListStorageGroupSnapshots", time.Now())
+	c, endSpan := c.traceOperation("ListStorageGroupSnapshots", AttributeSymmetrixID, symID, AttributeStorageGroupID, sgID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	URL := c.urlPrefix() + ReplicationX + SymmetrixX + symID + XStorageGroup + "/" + sgID + XSnapshot
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	snapshotList := &types.StorageGroupSnapshotList{}
+	err = c.api.Get(ctx, URL, c.getDefaultHeaders(), snapshotList)
+	if err != nil {
+		log.Error("ListStorageGroupSnapshots failed: " + err.Error())
+		return nil, err
+	}
+	return snapshotList, nil
+}
+
+// GetStorageGroupSnapshotGenerations returns the generations of a storage group snapshot, the newest generation is 0
+func (c *Client) GetStorageGroupSnapshotGenerations(symID, sgID, snapID string) (_ *types.StorageGroupSnapshotGenerationList, err error) {
+	defer c.TimeSpent("GetStorageGroupSnapshotGenerations", time.Now())
+	c, endSpan := c.traceOperation("GetStorageGroupSnapshotGenerations", AttributeSymmetrixID, symID, AttributeStorageGroupID, sgID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	URL := c.urlPrefix() + ReplicationX + SymmetrixX + symID + XStorageGroup + "/" + sgID + XSnapshot + "/" + snapID + XGenereation
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	generationList := &types.StorageGroupSnapshotGenerationList{}
+	err = c.api.Get(ctx, URL, c.getDefaultHeaders(), generationList)
+	if err != nil {
+		log.Error("GetStorageGroupSnapshotGenerations failed: " + err.Error())
+		return nil, err
+	}
+	return generationList, nil
+}
+
+// GetStorageGroupSnapshot returns a generation of a storage group snapshot with its source volumes and links
+func (c *Client) GetStorageGroupSnapshot(symID, sgID, snapID string, generation int64) (_ *types.StorageGroupSnapshot, err error) {
+	defer c.TimeSpent("GetStorageGroupSnapshot", time.Now())
+	c, endSpan := c.traceOperation("GetStorageGroupSnapshot", AttributeSymmetrixID, symID, AttributeStorageGroupID, sgID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	URL := c.storageGroupSnapshotGenerationURL(symID, sgID, snapID, generation)
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	snapshot := &types.StorageGroupSnapshot{}
+	err = c.api.Get(ctx, URL, c.getDefaultHeaders(), snapshot)
+	if err != nil {
+		log.Error("GetStorageGroupSnapshot failed: " + err.Error())
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// LinkStorageGroupSnapshot links a generation of a storage group snapshot to the volumes of another storage group.
+// The volumes are paired by their ordinal positions in the storage groups
+func (c *Client) LinkStorageGroupSnapshot(symID, sgID, snapID string, generation int64, linkSGID string) (err error) {
+	defer c.TimeSpent("LinkStorageGroupSnapshot", time.Now())
+	c, endSpan := c.traceOperation("LinkStorageGroupSnapshot", AttributeSymmetrixID, symID, AttributeStorageGroupID, sgID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
+	modifyParam := &types.ModifyStorageGroupSnapshot{
+		Action:          types.SnapshotActionLink,
+		Link:            &types.LinkStorageGroupSnapshotParam{LinkStorageGroupName: linkSGID},
+		ExecutionOption: types.ExecutionOptionAsynchronous,
+	}
+	return c.modifyStorageGroupSnapshot(symID, sgID, snapID, generation, "LinkStorageGroupSnapshot", modifyParam)
+}
+
+// UnlinkStorageGroupSnapshot unlinks a generation of a storage group snapshot from the volumes of a linked storage group
+func (c *Client) UnlinkStorageGroupSnapshot(symID, sgID, snapID string, generation int64, linkSGID string) (err error) {
+	defer c.TimeSpent("UnlinkStorageGroupSnapshot", time.Now())
+	c, endSpan := c.traceOperation("UnlinkStorageGroupSnapshot", AttributeSymmetrixID, symID, AttributeStorageGroupID, sgID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
+	modifyParam := &types.ModifyStorageGroupSnapshot{
+		Action:          types.SnapshotActionUnlink,
+		Unlink:          &types.UnlinkStorageGroupSnapshotParam{UnlinkStorageGroupName: linkSGID},
+		ExecutionOption: types.ExecutionOptionAsynchronous,
+	}
+	return c.modifyStorageGroupSnapshot(symID, sgID, snapID, generation, "UnlinkStorageGroupSnapshot", modifyParam)
+}
+
+// RestoreStorageGroupSnapshot restores a generation of a storage group snapshot to the volumes of the storage group
+func (c *Client) RestoreStorageGroupSnapshot(symID, sgID, snapID string, generation int64) (err error) {
+	defer c.TimeSpent("RestoreStorageGroupSnapshot", time.Now())
+	c, endSpan := c.traceOperation("RestoreStorageGroupSnapshot", AttributeSymmetrixID, symID, AttributeStorageGroupID, sgID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
+	modifyParam := &types.ModifyStorageGroupSnapshot{
+		Action:          types.SnapshotActionRestore,
+		Restore:         &types.RestoreStorageGroupSnapshotParam{},
+		ExecutionOption: types.ExecutionOptionAsynchronous,
+	}
+	return c.modifyStorageGroupSnapshot(symID, sgID, snapID, generation, "RestoreStorageGroupSnapshot", modifyParam)
+}
+
+// RenameStorageGroupSnapshot renames a generation of a storage group snapshot
+func (c *Client) RenameStorageGroupSnapshot(symID, sgID, snapID string, generation int64, newSnapID string) (err error) {
+	defer c.TimeSpent("RenameStorageGroupSnapshot", time.Now())
+	c, endSpan := c.traceOperation("RenameStorageGroupSnapshot", AttributeSymmetrixID, symID, AttributeStorageGroupID, sgID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
+	modifyParam := &types.ModifyStorageGroupSnapshot{
+		Action:          types.SnapshotActionRename,
+		Rename:          &types.RenameStorageGroupSnapshotParam{NewStorageGroupSnapshotName: newSnapID},
+		ExecutionOption: types.ExecutionOptionAsynchronous,
+	}
+	return c.modifyStorageGroupSnapshot(symID, sgID, snapID, generation, "RenameStorageGroupSnapshot", modifyParam)
+}
+
+// DeleteStorageGroupSnapshot deletes a generation of a storage group snapshot.
+// The snapshot generation must not be linked to any storage group
+func (c *Client) DeleteStorageGroupSnapshot(symID, sgID, snapID string, generation int64) (err error) {
+	defer c.TimeSpent("DeleteStorageGroupSnapshot", time.Now())
+	c, endSpan := c.traceOperation("DeleteStorageGroupSnapshot", AttributeSymmetrixID, symID, AttributeStorageGroupID, sgID, AttributeSnapshotID, snapID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return err
+	}
+	deleteParam := &types.DeleteStorageGroupSnapshot{
+		ExecutionOption: types.ExecutionOptionAsynchronous,
+	}
+	ifDebugLogPayload(deleteParam)
+	URL := c.storageGroupSnapshotGenerationURL(symID, sgID, snapID, generation)
+	job := &types.Job{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err = c.api.DoWithHeaders(ctx, http.MethodDelete, URL, c.getDefaultHeaders(), deleteParam, job)
+	if err != nil {
+		log.Error("DeleteStorageGroupSnapshot failed: " + err.Error())
+		return err
+	}
+	if err = c.waitOnJob(symID, job, "storage group snapshot delete"); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Snapshot (%s) generation (%d) of StorageGroup (%s) deleted successfully", snapID, generation, sgID))
+	return nil
+}
+
+func (c *Client) modifyStorageGroupSnapshot(symID, sgID, snapID string, generation int64, functionName string, modifyParam *types.ModifyStorageGroupSnapshot) error {
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return err
+	}
+	ifDebugLogPayload(modifyParam)
+	URL := c.storageGroupSnapshotGenerationURL(symID, sgID, snapID, generation)
+	fields := map[string]interface{}{
+		http.MethodPut: URL,
+		"Action":       modifyParam.Action,
+	}
+	job := &types.Job{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err := c.api.Put(ctx, URL, c.getDefaultHeaders(), modifyParam, job)
+	if err != nil {
+		log.WithFields(fields).Error("Error in " + functionName + ": " + err.Error())
+		return err
+	}
+	if err = c.waitOnJob(symID, job, "storage group snapshot "+modifyParam.Action); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Action (%s) on Snapshot (%s) of StorageGroup (%s) is successful", modifyParam.Action, snapID, sgID))
+	return nil
+}
+
+func (c *Client) storageGroupSnapshotGenerationURL(symID, sgID, snapID string, generation int64) string {
+	return c.urlPrefix() + ReplicationX + SymmetrixX + symID + XStorageGroup + "/" + sgID + XSnapshot + "/" + snapID + XGenereation + "/" + strconv.FormatInt(generation, 10)
+}
+
 // GetReplicationCapabilities returns details about SnapVX and SRDF
 // execution capabilities on the Symmetrix array
-func (c *Client) GetReplicationCapabilities() (*types.SymReplicationCapabilities, error) {
+func (c *Client) GetReplicationCapabilities() (_ *types.SymReplicationCapabilities, err error) {
 	defer c.TimeSpent("GetReplicationCapabilities", time.Now())
+	c, endSpan := c.traceOperation("GetReplicationCapabilities")
+	defer endSpan(&err)
 	URL := c.urlPrefix() + ReplicationX + "capabilities/symmetrix"
 	symReplicationCapabilities := new(types.SymReplicationCapabilities)
-	err := c.api.Get(context.Background(), URL, c.getDefaultHeaders(), symReplicationCapabilities)
+	err = c.api.Get(context.Background(), URL, c.getDefaultHeaders(), symReplicationCapabilities)
 	if err != nil {
 		return nil, err
 	}
 	return symReplicationCapabilities, nil
 }
+
+// GetRDFGroupList returns the number and label of all the RDF groups on the Symmetrix
+func (c *Client) GetRDFGroupList(symID string) (_ *types.RDFGroupList, err error) {
+	defer c.TimeSpent("GetRDFGroupList", time.Now())
+	c, endSpan := c.traceOperation("GetRDFGroupList", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	URL := c.urlPrefix() + ReplicationX + SymmetrixX + symID + XRDFGroup
+	rdfGroupList := &types.RDFGroupList{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err = c.api.Get(ctx, URL, c.getDefaultHeaders(), rdfGroupList)
+	if err != nil {
+		log.Error("GetRDFGroupList failed: " + err.Error())
+		return nil, err
+	}
+	return rdfGroupList, nil
+}
+
+// GetRDFGroup returns the details of an RDF group, including the remote array and
+// RDF group, the SRDF modes, the local and remote director ports and the device count.
+// The state of the SRDF links is available through the LinkState method of the result.
+func (c *Client) GetRDFGroup(symID string, rdfGroupNumber int) (_ *types.RDFGroup, err error) {
+	defer c.TimeSpent("GetRDFGroup", time.Now())
+	c, endSpan := c.traceOperation("GetRDFGroup", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	URL := c.urlPrefix() + ReplicationX + SymmetrixX + symID + XRDFGroup + "/" + strconv.Itoa(rdfGroupNumber)
+	rdfGroup := &types.RDFGroup{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err = c.api.Get(ctx, URL, c.getDefaultHeaders(), rdfGroup)
+	if err != nil {
+		log.Error("GetRDFGroup failed: " + err.Error())
+		return nil, err
+	}
+	if rdfGroup.SymmetrixID == "" {
+		rdfGroup.SymmetrixID = symID
+	}
+	return rdfGroup, nil
+}
+
+// GetRDFGroupVolumes returns the ids of the local volumes in an RDF group
+func (c *Client) GetRDFGroupVolumes(symID string, rdfGroupNumber int) (_ *types.RDFGroupVolumeList, err error) {
+	defer c.TimeSpent("GetRDFGroupVolumes", time.Now())
+	c, endSpan := c.traceOperation("GetRDFGroupVolumes", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	URL := c.urlPrefix() + ReplicationX + SymmetrixX + symID + XRDFGroup + "/" + strconv.Itoa(rdfGroupNumber) + XVolume
+	volumeList := &types.RDFGroupVolumeList{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err = c.api.Get(ctx, URL, c.getDefaultHeaders(), volumeList)
+	if err != nil {
+		log.Error("GetRDFGroupVolumes failed: " + err.Error())
+		return nil, err
+	}
+	return volumeList, nil
+}
+
+// GetRDFDevicePair returns the SRDF relationship of a volume in an RDF group,
+// including the remote volume, the SRDF mode and the pair state
+func (c *Client) GetRDFDevicePair(symID string, rdfGroupNumber int, volumeID string) (_ *types.RDFDevicePair, err error) {
+	defer c.TimeSpent("GetRDFDevicePair", time.Now())
+	c, endSpan := c.traceOperation("GetRDFDevicePair", AttributeSymmetrixID, symID, AttributeVolumeID, volumeID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	URL := c.urlPrefix() + ReplicationX + SymmetrixX + symID + XRDFGroup + "/" + strconv.Itoa(rdfGroupNumber) + XVolume + "/" + volumeID
+	devicePair := &types.RDFDevicePair{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err = c.api.Get(ctx, URL, c.getDefaultHeaders(), devicePair)
+	if err != nil {
+		log.Error("GetRDFDevicePair failed: " + err.Error())
+		return nil, err
+	}
+	return devicePair, nil
+}
+
+// CreateSGReplica protects a storage group with SRDF. The remote storage group is created
+// on the remote array and each volume of the storage group is paired with a new remote volume
+// in the given RDF group, using the Synchronous, Asynchronous or Active (SRDF/Metro) mode.
+// When establish is set the pairs are established as soon as they are created.
+func (c *Client) CreateSGReplica(symID, remoteSymID, rdfMode string, rdfGroupNumber int, sgID, remoteSGID, remoteServiceLevel string, establish bool) (_ *types.SGRDFInfo, err error) {
+	defer c.TimeSpent("CreateSGReplica", time.Now())
+	c, endSpan := c.traceOperation("CreateSGReplica", AttributeSymmetrixID, symID, AttributeStorageGroupID, sgID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	switch rdfMode {
+	case types.RDFModeSynchronous, types.RDFModeAsynchronous, types.RDFModeActive:
+	default:
+		return nil, fmt.Errorf("not a supported SRDF mode: %s", rdfMode)
+	}
+	createParam := &types.CreateSGSRDF{
+		ReplicationMode:        rdfMode,
+		RemoteSymmID:           remoteSymID,
+		RemoteStorageGroupName: remoteSGID,
+		RemoteSLO:              remoteServiceLevel,
+		RDFGroupNumber:         rdfGroupNumber,
+		Establish:              establish,
+		ExecutionOption:        types.ExecutionOptionAsynchronous,
+	}
+	ifDebugLogPayload(createParam)
+	URL := c.urlPrefix() + ReplicationX + SymmetrixX + symID + XStorageGroup + "/" + sgID + XRDFGroup
+	job := &types.Job{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err = c.api.Post(ctx, URL, c.getDefaultHeaders(), createParam, job)
+	if err != nil {
+		log.Error("CreateSGReplica failed: " + err.Error())
+		return nil, err
+	}
+	if err = c.waitOnJob(symID, job, "SRDF protection of storage group "+sgID); err != nil {
+		return nil, err
+	}
+	log.Info(fmt.Sprintf("Successfully protected StorageGroup: %s with SRDF in RDF group: %d", sgID, rdfGroupNumber))
+	return c.GetStorageGroupRDFInfo(symID, sgID, rdfGroupNumber)
+}
+
+// GetStorageGroupRDFGroupList returns the numbers of the RDF groups protecting a storage group
+func (c *Client) GetStorageGroupRDFGroupList(symID, sgID string) (_ *types.SGRDFGroupList, err error) {
+	defer c.TimeSpent("GetStorageGroupRDFGroupList", time.Now())
+	c, endSpan := c.traceOperation("GetStorageGroupRDFGroupList", AttributeSymmetrixID, symID, AttributeStorageGroupID, sgID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	URL := c.urlPrefix() + ReplicationX + SymmetrixX + symID + XStorageGroup + "/" + sgID + XRDFGroup
+	sgRDFGroupList := &types.SGRDFGroupList{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err = c.api.Get(ctx, URL, c.getDefaultHeaders(), sgRDFGroupList)
+	if err != nil {
+		log.Error("GetStorageGroupRDFGroupList failed: " + err.Error())
+		return nil, err
+	}
+	return sgRDFGroupList, nil
+}
+
+// GetStorageGroupRDFInfo returns the SRDF types, modes and pair states of a storage group in an RDF group
+func (c *Client) GetStorageGroupRDFInfo(symID, sgID string, rdfGroupNumber int) (_ *types.SGRDFInfo, err error) {
+	defer c.TimeSpent("GetStorageGroupRDFInfo", time.Now())
+	c, endSpan := c.traceOperation("GetStorageGroupRDFInfo", AttributeSymmetrixID, symID, AttributeStorageGroupID, sgID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	URL := c.urlPrefix() + ReplicationX + SymmetrixX + symID + XStorageGroup + "/" + sgID + XRDFGroup + "/" + strconv.Itoa(rdfGroupNumber)
+	sgRDFInfo := &types.SGRDFInfo{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err = c.api.Get(ctx, URL, c.getDefaultHeaders(), sgRDFInfo)
+	if err != nil {
+		log.Error("GetStorageGroupRDFInfo failed: " + err.Error())
+		return nil, err
+	}
+	return sgRDFInfo, nil
+}
+
+// ExecuteSRDFAction performs an SRDF action (Establish, Split, Suspend, Resume, Failover,
+// Failback or Swap) on all the SRDF pairs of a storage group in an RDF group and waits
+// for the job to complete. Use the Force flag to perform the action in acceptable error conditions.
+func (c *Client) ExecuteSRDFAction(symID, sgID string, rdfGroupNumber int, action string, force bool) (err error) {
+	defer c.TimeSpent("ExecuteSRDFAction", time.Now())
+	c, endSpan := c.traceOperation("ExecuteSRDFAction", AttributeSymmetrixID, symID, AttributeStorageGroupID, sgID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return err
+	}
+	actionParams := &types.RDFActionParams{Force: force}
+	modifyParam := &types.ModifySGRDFGroup{
+		Action:          action,
+		ExecutionOption: types.ExecutionOptionAsynchronous,
+	}
+	switch action {
+	case types.RDFActionEstablish:
+		modifyParam.Establish = actionParams
+	case types.RDFActionSplit:
+		modifyParam.Split = actionParams
+	case types.RDFActionSuspend:
+		modifyParam.Suspend = actionParams
+	case types.RDFActionResume:
+		modifyParam.Resume = actionParams
+	case types.RDFActionFailover:
+		modifyParam.Failover = actionParams
+	case types.RDFActionFailback:
+		modifyParam.Failback = actionParams
+	case types.RDFActionSwap:
+		modifyParam.Swap = actionParams
+	default:
+		return fmt.Errorf("not a supported SRDF action: %s", action)
+	}
+	return c.modifySGRDFGroup(symID, sgID, rdfGroupNumber, "ExecuteSRDFAction", modifyParam)
+}
+
+// SetMetroBias moves the bias of the SRDF/Metro pairs of a storage group to the R1 or R2 side.
+// The bias side is the one that remains available when the SRDF links fail and no witness is in effect.
+func (c *Client) SetMetroBias(symID, sgID string, rdfGroupNumber int, biasSide string) (err error) {
+	defer c.TimeSpent("SetMetroBias", time.Now())
+	c, endSpan := c.traceOperation("SetMetroBias", AttributeSymmetrixID, symID, AttributeStorageGroupID, sgID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return err
+	}
+	if biasSide != types.MetroBiasR1 && biasSide != types.MetroBiasR2 {
+		return fmt.Errorf("not a valid SRDF/Metro bias side: %s", biasSide)
+	}
+	modifyParam := &types.ModifySGRDFGroup{
+		Action:          types.RDFActionSetBias,
+		SetBias:         &types.SetBiasParam{Bias: biasSide},
+		ExecutionOption: types.ExecutionOptionAsynchronous,
+	}
+	return c.modifySGRDFGroup(symID, sgID, rdfGroupNumber, "SetMetroBias", modifyParam)
+}
+
+// GetMetroStatus returns the SRDF/Metro state of a storage group in an SRDF/Metro RDF group:
+// the pair states (ActiveActive or ActiveBias when established), the side holding the bias
+// and whether a witness is configured and in effect.
+func (c *Client) GetMetroStatus(symID, sgID string, rdfGroupNumber int) (_ *types.MetroStatus, err error) {
+	defer c.TimeSpent("GetMetroStatus", time.Now())
+	c, endSpan := c.traceOperation("GetMetroStatus", AttributeSymmetrixID, symID, AttributeStorageGroupID, sgID)
+	defer endSpan(&err)
+	rdfGroup, err := c.GetRDFGroup(symID, rdfGroupNumber)
+	if err != nil {
+		return nil, err
+	}
+	if !rdfGroup.Metro {
+		return nil, fmt.Errorf("RDF group %d is not an SRDF/Metro group", rdfGroupNumber)
+	}
+	sgRDFInfo, err := c.GetStorageGroupRDFInfo(symID, sgID, rdfGroupNumber)
+	if err != nil {
+		return nil, err
+	}
+	metroStatus := &types.MetroStatus{
+		SymmetrixID:       symID,
+		RemoteSymmetrixID: rdfGroup.RemoteSymmetrix,
+		StorageGroupName:  sgID,
+		RDFGroupNumber:    rdfGroupNumber,
+		States:            sgRDFInfo.States,
+		LocalRDFType:      types.MetroBiasUnknown,
+		BiasConfigured:    rdfGroup.BiasConfigured,
+		BiasEffective:     rdfGroup.BiasEffective,
+		WitnessConfigured: rdfGroup.WitnessConfigured,
+		WitnessEffective:  rdfGroup.WitnessEffective,
+	}
+	// The bias is held by the R1 side, moving it swaps the R1 and R2 personalities.
+	// It cannot be told without pairs or while the pairs have different personalities
+	if len(sgRDFInfo.VolumeRDFTypes) == 1 {
+		switch sgRDFInfo.VolumeRDFTypes[0] {
+		case types.MetroBiasR1:
+			metroStatus.LocalRDFType = types.MetroBiasR1
+			metroStatus.BiasSymmetrixID = symID
+		case types.MetroBiasR2:
+			metroStatus.LocalRDFType = types.MetroBiasR2
+			metroStatus.BiasSymmetrixID = rdfGroup.RemoteSymmetrix
+		}
+	}
+	switch {
+	case rdfGroup.WitnessProtectedPhysical:
+		metroStatus.WitnessType = types.MetroWitnessPhysical
+	case rdfGroup.WitnessProtectedVirtual:
+		metroStatus.WitnessType = types.MetroWitnessVirtual
+	}
+	return metroStatus, nil
+}
+
+func (c *Client) modifySGRDFGroup(symID, sgID string, rdfGroupNumber int, functionName string, modifyParam *types.ModifySGRDFGroup) error {
+	ifDebugLogPayload(modifyParam)
+	URL := c.urlPrefix() + ReplicationX + SymmetrixX + symID + XStorageGroup + "/" + sgID + XRDFGroup + "/" + strconv.Itoa(rdfGroupNumber)
+	fields := map[string]interface{}{
+		http.MethodPut: URL,
+		"Action":       modifyParam.Action,
+	}
+	job := &types.Job{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err := c.api.Put(ctx, URL, c.getDefaultHeaders(), modifyParam, job)
+	if err != nil {
+		log.WithFields(fields).Error("Error in " + functionName + ": " + err.Error())
+		return err
+	}
+	if err = c.waitOnJob(symID, job, "SRDF "+modifyParam.Action); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Action (%s) on SRDF pairs of StorageGroup (%s) is successful", modifyParam.Action, sgID))
+	return nil
+}
+
+// DeleteSGReplica deletes the SRDF pairs of a storage group in an RDF group.
+// The pairs must be split or suspended unless the Force flag is set.
+func (c *Client) DeleteSGReplica(symID, sgID string, rdfGroupNumber int, force bool) (err error) {
+	defer c.TimeSpent("DeleteSGReplica", time.Now())
+	c, endSpan := c.traceOperation("DeleteSGReplica", AttributeSymmetrixID, symID, AttributeStorageGroupID, sgID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return err
+	}
+	deleteParam := &types.DeleteSGRDFPair{
+		Force:           force,
+		ExecutionOption: types.ExecutionOptionAsynchronous,
+	}
+	ifDebugLogPayload(deleteParam)
+	URL := c.urlPrefix() + ReplicationX + SymmetrixX + symID + XStorageGroup + "/" + sgID + XRDFGroup + "/" + strconv.Itoa(rdfGroupNumber)
+	job := &types.Job{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err = c.api.DoWithHeaders(ctx, http.MethodDelete, URL, c.getDefaultHeaders(), deleteParam, job)
+	if err != nil {
+		log.Error("DeleteSGReplica failed: " + err.Error())
+		return err
+	}
+	if err = c.waitOnJob(symID, job, "SRDF pair delete"); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("SRDF pairs of StorageGroup (%s) in RDF group (%d) deleted successfully", sgID, rdfGroupNumber))
+	return nil
+}
+
+// waitOnJob waits for a replication job to complete and returns an error if it did not succeed
+func (c *Client) waitOnJob(symID string, job *types.Job, operation string) error {
+	job, err := c.WaitOnJobCompletion(symID, job.JobID)
+	if err != nil {
+		return err
+	}
+	if job.Status == types.JobStatusFailed || job.Status == types.JobStatusRunning {
+		return fmt.Errorf("Job status not successful for %s. Job status = %s and Job result = %s", operation, job.Status, job.Result)
+	}
+	return nil
+}
+
+// CloneVolume creates a clone of the source volume, named name, in the target storage group and returns it.
+// The clone is created with the size of the source, then a temporary snapshot of the source is linked to it.
+// A linked clone is returned as soon as the link is defined and keeps referencing the snapshot, which must be
+// unlinked and deleted along with the clone. A fully copied clone waits for all the data to be copied,
+// then the snapshot is unlinked and deleted, leaving an independent volume. The clone is returned even if the
+// snapshot cannot be deleted, the snapshot being left to CollectSnapshots, as orphaned with the CloneSnapshotPrefix.
+// If any other step fails, the snapshot and the clone created so far are removed before returning the error
+func (c *Client) CloneVolume(symID, srcVolumeID, targetSG, name string, options *types.CloneVolumeOptions) (_ *types.Volume, err error) {
+	defer c.TimeSpent("CloneVolume", time.Now())
+	c, endSpan := c.traceOperation("CloneVolume", AttributeSymmetrixID, symID, AttributeVolumeID, srcVolumeID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if options == nil {
+		options = &types.CloneVolumeOptions{}
+	}
+	snapID := options.SnapshotName
+	if snapID == "" {
+		snapID = CloneSnapshotPrefix + name
+	}
+	srcVolume, err := c.GetVolumeByID(symID, srcVolumeID)
+	if err != nil {
+		return nil, err
+	}
+	clone, err := c.CreateVolumeInStorageGroup(symID, targetSG, name, srcVolume.CapacityCYL)
+	if err != nil {
+		return nil, err
+	}
+	sourceVolumes := []types.VolumeList{{Name: srcVolumeID}}
+	targetVolumes := []types.VolumeList{{Name: clone.VolumeID}}
+	cleanup := &cloneCleanup{symID: symID, storageGroupID: targetSG, cloneID: clone.VolumeID}
+
+	if err = c.CreateSnapshot(symID, snapID, sourceVolumes, 0); err != nil {
+		c.cleanupClone(cleanup)
+		return nil, err
+	}
+	cleanup.snapID, cleanup.sourceVolumes = snapID, sourceVolumes
+	if err = c.LinkSnapshot(symID, snapID, sourceVolumes, targetVolumes, 0, &types.LinkSnapshotOptions{Copy: options.Copy}); err != nil {
+		c.cleanupClone(cleanup)
+		return nil, err
+	}
+	cleanup.linked = true
+	state := types.SnapshotLinkStateLinked
+	if options.Copy {
+		state = types.SnapshotLinkStateCopied
+	}
+	if _, err = c.WaitForLinkState(symID, snapID, srcVolumeID, clone.VolumeID, state, options.Timeout); err != nil {
+		c.cleanupClone(cleanup)
+		return nil, err
+	}
+	if options.Copy {
+		if err = c.ModifySnapshot(symID, sourceVolumes, targetVolumes, snapID, "Unlink", "", 0); err != nil {
+			c.cleanupClone(cleanup)
+			return nil, err
+		}
+		// The clone no longer depends on the snapshot, which is left to be collected as an orphaned snapshot if it cannot be deleted
+		if err := c.DeleteSnapshot(symID, snapID, sourceVolumes, 0); err != nil {
+			log.Warning(fmt.Sprintf("CloneVolume failed to delete Snapshot (%s) of volume (%s) after copying it to volume (%s): %s",
+				snapID, srcVolumeID, clone.VolumeID, err.Error()))
+		}
+	}
+	log.Info(fmt.Sprintf("Successfully cloned volume (%s) to volume (%s) using Snapshot (%s)", srcVolumeID, clone.VolumeID, snapID))
+	return c.GetVolumeByID(symID, clone.VolumeID)
+}
+
+// cloneCleanup holds what has been created so far by CloneVolume
+type cloneCleanup struct {
+	symID          string
+	storageGroupID string
+	cloneID        string
+	snapID         string
+	sourceVolumes  []types.VolumeList
+	linked         bool
+}
+
+// cleanupClone unlinks and deletes the snapshot and removes and deletes the clone of a failed CloneVolume.
+// Errors are logged, as the cleanup is best effort and the error of the failed step is the one returned
+func (c *Client) cleanupClone(cleanup *cloneCleanup) {
+	targetVolumes := []types.VolumeList{{Name: cleanup.cloneID}}
+	if cleanup.linked {
+		if err := c.ModifySnapshot(cleanup.symID, cleanup.sourceVolumes, targetVolumes, cleanup.snapID, "Unlink", "", 0); err != nil {
+			log.Error(fmt.Sprintf("CloneVolume cleanup failed to unlink Snapshot (%s) from volume (%s): %s", cleanup.snapID, cleanup.cloneID, err.Error()))
+		}
+	}
+	if cleanup.snapID != "" {
+		if err := c.DeleteSnapshot(cleanup.symID, cleanup.snapID, cleanup.sourceVolumes, 0); err != nil {
+			log.Error(fmt.Sprintf("CloneVolume cleanup failed to delete Snapshot (%s): %s", cleanup.snapID, err.Error()))
+		}
+	}
+	if _, err := c.RemoveVolumesFromStorageGroup(cleanup.symID, cleanup.storageGroupID, cleanup.cloneID); err != nil {
+		log.Error(fmt.Sprintf("CloneVolume cleanup failed to remove volume (%s) from StorageGroup (%s): %s", cleanup.cloneID, cleanup.storageGroupID, err.Error()))
+		return
+	}
+	if err := c.DeleteVolume(cleanup.symID, cleanup.cloneID); err != nil {
+		log.Error(fmt.Sprintf("CloneVolume cleanup failed to delete volume (%s): %s", cleanup.cloneID, err.Error()))
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -105,14 +106,32 @@ type Client interface {
 
 	// ParseJSONError parses the JSON in r into an error object
 	ParseJSONError(r *http.Response) error
+
+	// SetRequestObserver sets the observer notified of the requests sent by the HTTP client
+	SetRequestObserver(observer RequestObserver)
+
+	// SetWireLogConfig enables the logging of the requests and responses of the HTTP client, nil disables it
+	SetWireLogConfig(config *WireLogConfig)
+}
+
+// RequestObserver is notified of the requests sent by a Client.
+// The request holds the context of the call, and the path of the request as passed to the Client
+type RequestObserver interface {
+	// RequestStart is called before the request is sent
+	RequestStart(req *http.Request, path string)
+	// RequestEnd is called once the response is received, or the request failed and res is nil
+	RequestEnd(req *http.Request, path string, res *http.Response, err error, duration time.Duration)
 }
 
 type client struct {
 	http     *http.Client
 	host     string
 	token    string
-	showHTTP bool
 	debug    bool
+	observer RequestObserver
+
+	wireLogMutex sync.RWMutex
+	wireLog      *wireLogger
 }
 
 // ClientOptions are options for the API client.
@@ -127,8 +146,12 @@ type ClientOptions struct {
 	Timeout time.Duration
 
 	// ShowHTTP is a flag that indicates whether or not HTTP requests and
-	// responses should be logged to stdout
+	// responses should be logged, with the default WireLogConfig if WireLog is nil
 	ShowHTTP bool
+
+	// WireLog configures the logging of the HTTP requests and responses, which is
+	// enabled if it is set
+	WireLog *WireLogConfig
 }
 
 // New returns a new API client.
@@ -174,8 +197,10 @@ func New(
 		}
 	}
 
-	if opts.ShowHTTP {
-		c.showHTTP = true
+	if opts.WireLog != nil {
+		c.SetWireLogConfig(opts.WireLog)
+	} else if opts.ShowHTTP {
+		c.SetWireLogConfig(&WireLogConfig{})
 	}
 
 	c.debug = debug
@@ -360,18 +385,30 @@ func (c *client) DoAndGetResponseBody(
 		req.SetBasicAuth("", c.token)
 	}
 
-	if c.showHTTP {
-		logRequest(ctx, req, c.doLog)
-	}
-
 	// send the request
 	req = req.WithContext(ctx)
-	if res, err = c.http.Do(req); err != nil {
-		return nil, err
+	if c.observer != nil {
+		c.observer.RequestStart(req, uri)
 	}
-
-	if c.showHTTP {
-		logResponse(ctx, res, c.doLog)
+	var correlationID string
+	wireLog := c.getWireLogger()
+	if wireLog != nil && wireLog.enabled() {
+		correlationID = newCorrelationID()
+		wireLog.logRequest(req, correlationID)
+	} else {
+		wireLog = nil
+	}
+	start := time.Now()
+	res, err = c.http.Do(req)
+	duration := time.Since(start)
+	if c.observer != nil {
+		c.observer.RequestEnd(req, uri, res, err, duration)
+	}
+	if wireLog != nil {
+		wireLog.logResponse(req, res, err, correlationID, duration)
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	return res, err
@@ -385,6 +422,26 @@ func (c *client) GetToken() string {
 	return c.token
 }
 
+func (c *client) SetRequestObserver(observer RequestObserver) {
+	c.observer = observer
+}
+
+func (c *client) SetWireLogConfig(config *WireLogConfig) {
+	var wireLog *wireLogger
+	if config != nil {
+		wireLog = newWireLogger(config)
+	}
+	c.wireLogMutex.Lock()
+	defer c.wireLogMutex.Unlock()
+	c.wireLog = wireLog
+}
+
+func (c *client) getWireLogger() *wireLogger {
+	c.wireLogMutex.RLock()
+	defer c.wireLogMutex.RUnlock()
+	return c.wireLog
+}
+
 func (c *client) ParseJSONError(r *http.Response) error {
 	jsonError := &types.Error{}
 	if err := json.NewDecoder(r.Body).Decode(jsonError); err != nil {
@@ -16,71 +16,194 @@ package api
 import (
 	"bufio"
 	"bytes"
-	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
-	"net/http/httputil"
+	"regexp"
+	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 )
 
-func isBinOctetBody(h http.Header) bool {
-	return h.Get(HeaderKeyContentType) == headerValContentTypeBinaryOctetStream
+// DefaultWireLogMaxBodySize is the number of bytes of a body logged when WireLogConfig.MaxBodySize is 0
+const DefaultWireLogMaxBodySize = 4096
+
+// Redacted replaces the credentials in the wire logs
+const Redacted = "[REDACTED]"
+
+// redactedHeaders are the headers whose values are always redacted
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// redactedFields are the substrings of the names of the JSON fields whose values are always redacted
+var redactedFields = []string{"password", "secret", "token", "credential"}
+
+// WireLogConfig configures the logging of the requests and responses of a Client.
+// Each request and its response are logged as two entries sharing a correlation_id field,
+// with the credentials redacted and the bodies truncated
+type WireLogConfig struct {
+	// Logger is the logger of the entries, the standard logger of logrus if nil
+	Logger *log.Logger
+	// Level is the level of the entries, logrus.DebugLevel if 0
+	Level log.Level
+	// MaxBodySize is the number of bytes of a body logged, DefaultWireLogMaxBodySize if 0.
+	// The bodies are not logged if it is negative
+	MaxBodySize int
+	// RedactHeaders are headers whose values are redacted in addition to the authorization and cookie headers
+	RedactHeaders []string
+	// RedactFields are substrings of the names of JSON fields whose values are redacted in addition
+	// to the fields containing password, secret, token or credential
+	RedactFields []string
 }
 
-func logRequest(
-	ctx context.Context,
-	req *http.Request,
-	lf func(func(args ...interface{}), string)) {
-
-	w := &bytes.Buffer{}
+// wireLogger logs the requests and responses of a Client according to a WireLogConfig
+type wireLogger struct {
+	logger      *log.Logger
+	level       log.Level
+	maxBodySize int
+	headers     map[string]bool
+	fields      *regexp.Regexp
+}
 
-	fmt.Fprintln(w)
-	fmt.Fprint(w, "    -------------------------- ")
-	fmt.Fprint(w, "POWERMAX HTTP REQUEST")
-	fmt.Fprintln(w, " -------------------------")
+func newWireLogger(config *WireLogConfig) *wireLogger {
+	l := &wireLogger{
+		logger:      config.Logger,
+		level:       config.Level,
+		maxBodySize: config.MaxBodySize,
+		headers:     make(map[string]bool),
+		fields:      redactedFieldsPattern(append(append([]string{}, redactedFields...), config.RedactFields...)),
+	}
+	if l.logger == nil {
+		l.logger = log.StandardLogger()
+	}
+	if l.level == log.PanicLevel {
+		l.level = log.DebugLevel
+	}
+	if l.maxBodySize == 0 {
+		l.maxBodySize = DefaultWireLogMaxBodySize
+	}
+	for _, header := range append(append([]string{}, redactedHeaders...), config.RedactHeaders...) {
+		l.headers[http.CanonicalHeaderKey(header)] = true
+	}
+	return l
+}
 
-	buf, err := httputil.DumpRequest(req, !isBinOctetBody(req.Header))
-	if err != nil {
-		return
+// redactedFieldsPattern matches the JSON string fields whose names contain one of the substrings,
+// the first group being the name and the separator
+func redactedFieldsPattern(substrings []string) *regexp.Regexp {
+	quoted := make([]string, 0, len(substrings))
+	for _, substring := range substrings {
+		quoted = append(quoted, regexp.QuoteMeta(substring))
 	}
+	return regexp.MustCompile(`(?i)("[^"]*(?:` + strings.Join(quoted, "|") + `)[^"]*"\s*:\s*)"(?:[^"\\]|\\.)*"?`)
+}
 
-	WriteIndented(w, buf)
-	fmt.Fprintln(w)
+var defaultRedactedFieldsPattern = redactedFieldsPattern(redactedFields)
+
+// RedactJSON returns the JSON document with the values of the fields holding credentials replaced by Redacted.
+// It works on truncated documents too
+func RedactJSON(body []byte) []byte {
+	return defaultRedactedFieldsPattern.ReplaceAll(body, []byte(`${1}"`+Redacted+`"`))
+}
 
-	lf(log.Debug, w.String())
+func (l *wireLogger) enabled() bool {
+	return l.logger.IsLevelEnabled(l.level)
 }
 
-func logResponse(
-	ctx context.Context,
-	res *http.Response,
-	lf func(func(args ...interface{}), string)) {
+// newCorrelationID returns a random ID identifying a request and its response in the logs
+func newCorrelationID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%016x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
 
+func isBinOctetBody(h http.Header) bool {
+	return h.Get(HeaderKeyContentType) == headerValContentTypeBinaryOctetStream
+}
+
+// formatHeaders returns the headers sorted by name, with the values of the redacted headers replaced
+func (l *wireLogger) formatHeaders(h http.Header) string {
 	w := &bytes.Buffer{}
+	redacted := make(http.Header, len(h))
+	for name, values := range h {
+		if l.headers[http.CanonicalHeaderKey(name)] {
+			redacted[name] = []string{Redacted}
+		} else {
+			redacted[name] = values
+		}
+	}
+	redacted.Write(w)
+	return strings.TrimSpace(strings.Replace(w.String(), "\r\n", "; ", -1))
+}
+
+// formatBody returns the body truncated to the maximum size of the logger, with the credentials redacted
+func (l *wireLogger) formatBody(body []byte, size int64) string {
+	truncated := int64(len(body)) > int64(l.maxBodySize)
+	if truncated {
+		body = body[:l.maxBodySize]
+	}
+	formatted := string(l.fields.ReplaceAll(body, []byte(`${1}"`+Redacted+`"`)))
+	if truncated || size > int64(len(body)) {
+		if size < 0 {
+			return formatted + "... (truncated)"
+		}
+		return formatted + fmt.Sprintf("... (%d bytes truncated)", size-int64(len(body)))
+	}
+	return formatted
+}
 
-	fmt.Fprintln(w)
-	fmt.Fprint(w, "    -------------------------- ")
-	fmt.Fprint(w, "POWERMAX HTTP RESPONSE")
-	fmt.Fprintln(w, " -------------------------")
+func (l *wireLogger) logRequest(req *http.Request, correlationID string) {
+	fields := log.Fields{
+		"correlation_id": correlationID,
+		"method":         req.Method,
+		"url":            req.URL.String(),
+		"headers":        l.formatHeaders(req.Header),
+	}
+	if l.maxBodySize > 0 && req.GetBody != nil && !isBinOctetBody(req.Header) {
+		if body, err := req.GetBody(); err == nil {
+			buf, _ := ioutil.ReadAll(io.LimitReader(body, int64(l.maxBodySize)+1))
+			body.Close()
+			if len(buf) > 0 {
+				fields["body"] = l.formatBody(buf, req.ContentLength)
+			}
+		}
+	}
+	l.logger.WithFields(fields).Log(l.level, "POWERMAX HTTP REQUEST")
+}
 
-	buf, err := httputil.DumpResponse(res, !isBinOctetBody(res.Header))
+func (l *wireLogger) logResponse(req *http.Request, res *http.Response, err error, correlationID string, duration time.Duration) {
+	fields := log.Fields{
+		"correlation_id": correlationID,
+		"method":         req.Method,
+		"url":            req.URL.String(),
+		"duration":       duration.String(),
+	}
 	if err != nil {
+		l.logger.WithFields(fields).WithError(err).Log(l.level, "POWERMAX HTTP REQUEST FAILED")
 		return
 	}
-
-	bw := &bytes.Buffer{}
-	WriteIndented(bw, buf)
-
-	scanner := bufio.NewScanner(bw)
-	for {
-		if !scanner.Scan() {
-			break
+	fields["status"] = res.StatusCode
+	fields["headers"] = l.formatHeaders(res.Header)
+	if l.maxBodySize > 0 && res.Body != nil && !isBinOctetBody(res.Header) {
+		// read the beginning of the body only, and give it back to the caller in front of the rest
+		buf, readErr := ioutil.ReadAll(io.LimitReader(res.Body, int64(l.maxBodySize)+1))
+		res.Body = &prefixedReadCloser{Reader: io.MultiReader(bytes.NewReader(buf), res.Body), Closer: res.Body}
+		if readErr == nil && len(buf) > 0 {
+			fields["body"] = l.formatBody(buf, res.ContentLength)
 		}
-		fmt.Fprintln(w, scanner.Text())
 	}
+	l.logger.WithFields(fields).Log(l.level, "POWERMAX HTTP RESPONSE")
+}
 
-	log.Debug(w.String())
+// prefixedReadCloser reads a body whose beginning was already read, and closes the original body
+type prefixedReadCloser struct {
+	io.Reader
+	io.Closer
 }
 
 // WriteIndentedN indents all lines n spaces.
@@ -35,6 +35,10 @@ type Client struct {
 	api           api.Client
 	allowedArrays []string
 	version       string
+	// hooks holds the observers and the tracer, shared with the clients returned by WithContext
+	hooks *clientHooks
+	// traceContext is the context of the span of the operations of the client, nil for root spans
+	traceContext context.Context
 }
 
 var (
@@ -119,6 +123,8 @@ func doLog(
 //    CSI_APPLICATION_NAME - Application name which will be used for registering the application with Unisphere REST APIs
 //    CSI_POWERMAX_INSECURE - A boolean indicating whether unvalidated certificates can be accepted. Defaults to true.
 //    CSI_POWERMAX_USECERTS - Indicates whether to use certificates at all. Defaults to true.
+// The X_CSI_POWERMAX_DEBUG environment variable enables the wire logging of all the clients,
+// use SetWireLogging to enable it for a single client instead.
 func NewClient() (client Pmax, err error) {
 	return NewClientWithArgs(
 		os.Getenv("CSI_POWERMAX_ENDPOINT"),
@@ -176,14 +182,20 @@ func NewClientWithArgs(
 		return nil, err
 	}
 
-	client = &Client{
-		api: ac,
+	pmaxClient := &Client{
 		configConnect: &ConfigConnect{
 			Version: version,
 		},
 		allowedArrays: []string{},
 		version:       version,
+		hooks:         &clientHooks{},
 	}
+	pmaxClient.api = &tracingAPI{Client: ac, hooks: pmaxClient.hooks}
+	ac.SetRequestObserver(&requestObserver{hooks: pmaxClient.hooks})
+	if logResponseTimes {
+		pmaxClient.AddObserver(responseTimeLogger{})
+	}
+	client = pmaxClient
 
 	accHeader = api.HeaderValContentTypeJSON
 	if version != "" {
@@ -194,6 +206,12 @@ func NewClientWithArgs(
 	return client, nil
 }
 
+// SetWireLogging enables the logging of the requests and responses of the client, with the credentials
+// redacted and the bodies truncated as configured. nil disables it
+func (c *Client) SetWireLogging(config *api.WireLogConfig) {
+	c.api.SetWireLogConfig(config)
+}
+
 func (c *Client) getDefaultHeaders() map[string]string {
 	headers := make(map[string]string)
 	headers["Accept"] = accHeader
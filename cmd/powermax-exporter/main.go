@@ -0,0 +1,73 @@
+/*
+ Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Command powermax-exporter serves the capacity and health of PowerMax arrays as Prometheus metrics.
+// The connection to Unisphere is defined by the environment variables of pmax.NewClient, and
+// the credentials by CSI_POWERMAX_USER and CSI_POWERMAX_PASSWORD.
+package main
+
+import (
+	"flag"
+	"net/http"
+	"os"
+	"strings"
+
+	pmax "github.com/dell/gopowermax"
+	"github.com/dell/gopowermax/exporter"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	listenAddress := flag.String("listen-address", ":9469", "address to serve the metrics on")
+	metricsPath := flag.String("metrics-path", "/metrics", "path to serve the metrics on")
+	arrays := flag.String("arrays", "", "comma separated IDs of the arrays to export, all the arrays if empty")
+	namespace := flag.String("namespace", exporter.DefaultNamespace, "prefix of the metric names")
+	scrapeInterval := flag.Duration("scrape-interval", exporter.DefaultScrapeInterval, "interval at which the metrics are collected")
+	cacheTTL := flag.Duration("cache-ttl", 0, "how long the collected metrics are served, the scrape interval if zero")
+	sgWorkers := flag.Int("storage-group-workers", exporter.DefaultStorageGroupWorkers, "number of storage groups fetched concurrently")
+	maxSGs := flag.Int("max-storage-groups", 0, "maximum number of storage groups of an array collected on each scrape, all of them if zero")
+	flag.Parse()
+
+	client, err := pmax.NewClient()
+	if err != nil {
+		log.Fatal("Could not create the client: " + err.Error())
+	}
+	err = client.Authenticate(&pmax.ConfigConnect{
+		Endpoint: os.Getenv("CSI_POWERMAX_ENDPOINT"),
+		Username: os.Getenv("CSI_POWERMAX_USER"),
+		Password: os.Getenv("CSI_POWERMAX_PASSWORD"),
+	})
+	if err != nil {
+		log.Fatal("Could not authenticate: " + err.Error())
+	}
+
+	config := exporter.Config{
+		Namespace:           *namespace,
+		ScrapeInterval:      *scrapeInterval,
+		CacheTTL:            *cacheTTL,
+		StorageGroupWorkers: *sgWorkers,
+		MaxStorageGroups:    *maxSGs,
+	}
+	if *arrays != "" {
+		for _, symID := range strings.Split(*arrays, ",") {
+			config.SymmetrixIDs = append(config.SymmetrixIDs, strings.TrimSpace(symID))
+		}
+	}
+	e := exporter.New(client, config)
+	go e.Run(make(chan struct{}))
+
+	http.Handle(*metricsPath, e)
+	log.Info("Serving the metrics on " + *listenAddress + *metricsPath)
+	log.Fatal(http.ListenAndServe(*listenAddress, nil))
+}
@@ -0,0 +1,361 @@
+/*
+ Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package exporter exposes the capacity and health of PowerMax arrays as Prometheus metrics:
+// the capacity and efficiency of the storage resource pools, the capacity and service level
+// compliance of the storage groups and the status of the front end ports.
+package exporter
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	pmax "github.com/dell/gopowermax"
+	types "github.com/dell/gopowermax/types/v90"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultNamespace prefixes the names of the metrics if the Config has no namespace
+	DefaultNamespace = "powermax"
+	// DefaultScrapeInterval is the interval at which Run collects the metrics if the Config has no scrape interval
+	DefaultScrapeInterval = time.Minute
+	// DefaultStorageGroupWorkers is the number of storage groups fetched concurrently if the Config has no number of workers
+	DefaultStorageGroupWorkers = 8
+)
+
+// sloComplianceStates are the service level compliance states of a storage group reported by Unisphere
+var sloComplianceStates = []string{"STABLE", "MARGINAL", "CRITICAL", "NONE"}
+
+// The capacities reported by Unisphere are in binary units, the metrics are in bytes
+const (
+	bytesPerGB = float64(1 << 30)
+	bytesPerTB = float64(1 << 40)
+)
+
+// Config holds the configuration of an Exporter
+type Config struct {
+	// SymmetrixIDs are the arrays to export, all the arrays the client is allowed to access if empty
+	SymmetrixIDs []string
+	// Namespace prefixes the names of the metrics, DefaultNamespace if empty
+	Namespace string
+	// ScrapeInterval is the interval at which Run collects the metrics, DefaultScrapeInterval if zero
+	ScrapeInterval time.Duration
+	// CacheTTL is how long the collected metrics are served before being collected again, the scrape interval if zero
+	CacheTTL time.Duration
+	// StorageGroupWorkers is the number of storage groups fetched concurrently, DefaultStorageGroupWorkers if zero
+	StorageGroupWorkers int
+	// MaxStorageGroups is the maximum number of storage groups of an array collected on each scrape, all of them if zero
+	MaxStorageGroups int
+}
+
+// Exporter collects the metrics of the arrays with a client and serves them over HTTP
+type Exporter struct {
+	client pmax.Pmax
+	config Config
+
+	// collectMutex serializes the collections so concurrent scrapes share a single collection
+	collectMutex sync.Mutex
+	// cacheMutex guards the cached metrics
+	cacheMutex sync.RWMutex
+	metrics    []*Metric
+	collected  time.Time
+}
+
+// New returns an Exporter collecting the metrics of the arrays with the client
+func New(client pmax.Pmax, config Config) *Exporter {
+	if config.Namespace == "" {
+		config.Namespace = DefaultNamespace
+	}
+	if config.ScrapeInterval <= 0 {
+		config.ScrapeInterval = DefaultScrapeInterval
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = config.ScrapeInterval
+	}
+	if config.StorageGroupWorkers <= 0 {
+		config.StorageGroupWorkers = DefaultStorageGroupWorkers
+	}
+	return &Exporter{
+		client: client,
+		config: config,
+	}
+}
+
+// Metric descriptions, the names are prefixed with the namespace of the Config
+var (
+	upDesc                   = description{"up", "Whether the metrics of the array were collected (1) or not (0)"}
+	scrapeDurationDesc       = description{"scrape_duration_seconds", "Time taken to collect the metrics of the array"}
+	scrapeErrorsDesc         = description{"scrape_errors", "Number of storage resource pools and storage groups of the array whose metrics were not collected"}
+	arrayInfoDesc            = description{"array_info", "Model and microcode of the array"}
+	arrayDevicesDesc         = description{"array_devices", "Number of devices of the array"}
+	srpUsableDesc            = description{"srp_usable_capacity_bytes", "Usable capacity of the storage resource pool"}
+	srpUsableUsedDesc        = description{"srp_usable_used_capacity_bytes", "Used usable capacity of the storage resource pool"}
+	srpSubscribedDesc        = description{"srp_subscribed_capacity_bytes", "Subscribed capacity of the storage resource pool"}
+	srpSubscribedAllocDesc   = description{"srp_subscribed_allocated_capacity_bytes", "Allocated subscribed capacity of the storage resource pool"}
+	srpSnapshotDesc          = description{"srp_snapshot_capacity_bytes", "Snapshot capacity of the storage resource pool"}
+	srpSnapshotModifiedDesc  = description{"srp_snapshot_modified_capacity_bytes", "Modified snapshot capacity of the storage resource pool"}
+	srpEfficiencyDesc        = description{"srp_efficiency_ratio", "Overall efficiency ratio to one of the storage resource pool"}
+	srpDataReductionDesc     = description{"srp_data_reduction_ratio", "Data reduction ratio to one of the storage resource pool"}
+	srpDataReductionOnDesc   = description{"srp_data_reduction_enabled_percent", "Percentage of the storage resource pool with data reduction enabled"}
+	srpVirtualProvSavingDesc = description{"srp_virtual_provisioning_savings_ratio", "Virtual provisioning savings ratio to one of the storage resource pool"}
+	srpSnapshotSavingDesc    = description{"srp_snapshot_savings_ratio", "Snapshot savings ratio to one of the storage resource pool"}
+	sgCapacityDesc           = description{"storage_group_capacity_bytes", "Capacity of the storage group"}
+	sgVolumesDesc            = description{"storage_group_volumes", "Number of volumes of the storage group"}
+	sgComplianceDesc         = description{"storage_group_compliance", "Service level compliance of the storage group, 1 for the current compliance and 0 for the others"}
+	portUpDesc               = description{"port_up", "Whether the front end port and its director are online (1) or not (0)"}
+)
+
+// Run collects the metrics every scrape interval until stop is closed
+func (e *Exporter) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(e.config.ScrapeInterval)
+	defer ticker.Stop()
+	e.Collect()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			e.Collect()
+		}
+	}
+}
+
+// Metrics returns the cached metrics, collecting them again if they are older than the cache TTL
+func (e *Exporter) Metrics() []*Metric {
+	if metrics, ok := e.cachedMetrics(); ok {
+		return metrics
+	}
+	e.collectMutex.Lock()
+	defer e.collectMutex.Unlock()
+	// another scrape may have collected the metrics while this one waited
+	if metrics, ok := e.cachedMetrics(); ok {
+		return metrics
+	}
+	return e.collect()
+}
+
+func (e *Exporter) cachedMetrics() ([]*Metric, bool) {
+	e.cacheMutex.RLock()
+	defer e.cacheMutex.RUnlock()
+	if e.metrics == nil || time.Since(e.collected) >= e.config.CacheTTL {
+		return nil, false
+	}
+	return e.metrics, true
+}
+
+// Collect collects the metrics of the arrays and caches them
+func (e *Exporter) Collect() []*Metric {
+	e.collectMutex.Lock()
+	defer e.collectMutex.Unlock()
+	return e.collect()
+}
+
+func (e *Exporter) collect() []*Metric {
+	set := newMetricSet(e.config.Namespace)
+	for _, symID := range e.symmetrixIDs() {
+		start := time.Now()
+		arraySet := newMetricSet(e.config.Namespace)
+		up := 1.0
+		scrapeErrors, err := e.collectArray(arraySet, symID)
+		if err != nil {
+			log.Error(fmt.Sprintf("Could not collect the metrics of array %s: %s", symID, err.Error()))
+			up = 0
+		}
+		labels := map[string]string{"symmetrix_id": symID}
+		set.gauge(upDesc, up, labels)
+		set.gauge(scrapeDurationDesc, time.Since(start).Seconds(), labels)
+		if up == 1 {
+			set.gauge(scrapeErrorsDesc, float64(scrapeErrors), labels)
+			set.merge(arraySet)
+		}
+	}
+	e.cacheMutex.Lock()
+	e.metrics = set.metrics
+	e.collected = time.Now()
+	e.cacheMutex.Unlock()
+	return set.metrics
+}
+
+// symmetrixIDs returns the arrays of the Config, or all the arrays the client is allowed to access
+func (e *Exporter) symmetrixIDs() []string {
+	if len(e.config.SymmetrixIDs) != 0 {
+		return e.config.SymmetrixIDs
+	}
+	symIDList, err := e.client.GetSymmetrixIDList()
+	if err != nil {
+		log.Error("Could not list the arrays to collect metrics from: " + err.Error())
+		return []string{}
+	}
+	return symIDList.SymmetrixIDs
+}
+
+// collectArray collects the metrics of the array, its storage resource pools, storage groups and front end ports.
+// The storage resource pools and storage groups which cannot be fetched are logged and skipped, collectArray
+// returns their number, and an error if the array, the lists of its resources or its ports cannot be fetched
+func (e *Exporter) collectArray(set *metricSet, symID string) (int, error) {
+	symmetrix, err := e.client.GetSymmetrixByID(symID)
+	if err != nil {
+		return 0, err
+	}
+	set.gauge(arrayInfoDesc, 1, map[string]string{"symmetrix_id": symID, "model": symmetrix.Model, "ucode": symmetrix.Ucode})
+	set.gauge(arrayDevicesDesc, float64(symmetrix.DeviceCount), map[string]string{"symmetrix_id": symID})
+
+	scrapeErrors := 0
+	srpList, err := e.client.GetStoragePoolList(symID)
+	if err != nil {
+		return 0, err
+	}
+	for _, srpID := range srpList.StoragePoolIDs {
+		srp, err := e.client.GetStoragePool(symID, srpID)
+		if err != nil {
+			log.Error(fmt.Sprintf("Could not collect the metrics of storage resource pool %s of array %s: %s", srpID, symID, err.Error()))
+			scrapeErrors++
+			continue
+		}
+		collectStoragePool(set, symID, srp)
+	}
+
+	sgList, err := e.client.GetStorageGroupIDList(symID)
+	if err != nil {
+		return 0, err
+	}
+	sgIDs := sgList.StorageGroupIDs
+	if e.config.MaxStorageGroups > 0 && len(sgIDs) > e.config.MaxStorageGroups {
+		log.Warn(fmt.Sprintf("Collecting the metrics of %d of the %d storage groups of array %s", e.config.MaxStorageGroups, len(sgIDs), symID))
+		sgIDs = sgIDs[:e.config.MaxStorageGroups]
+	}
+	for i, sg := range e.getStorageGroups(symID, sgIDs) {
+		if sg.err != nil {
+			log.Error(fmt.Sprintf("Could not collect the metrics of storage group %s of array %s: %s", sgIDs[i], symID, sg.err.Error()))
+			scrapeErrors++
+			continue
+		}
+		collectStorageGroup(set, symID, sg.storageGroup)
+	}
+
+	ports, err := e.client.GetFrontEndPorts(symID, nil)
+	if err != nil {
+		return 0, err
+	}
+	for _, port := range ports {
+		key := port.SymmetrixPort.SymmetrixPortKey
+		up := 0.0
+		if port.SymmetrixPort.IsOnline() {
+			up = 1
+		}
+		set.gauge(portUpDesc, up, map[string]string{
+			"symmetrix_id": symID,
+			"director_id":  key.DirectorID,
+			"port_id":      key.PortID,
+			"type":         port.SymmetrixPort.Type,
+		})
+	}
+	return scrapeErrors, nil
+}
+
+type storageGroupResult struct {
+	storageGroup *types.StorageGroup
+	err          error
+}
+
+// getStorageGroups fetches the storage groups with at most StorageGroupWorkers concurrent requests
+// and returns them in the order of their IDs
+func (e *Exporter) getStorageGroups(symID string, sgIDs []string) []storageGroupResult {
+	results := make([]storageGroupResult, len(sgIDs))
+	workers := make(chan struct{}, e.config.StorageGroupWorkers)
+	var wg sync.WaitGroup
+	for i, sgID := range sgIDs {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(i int, sgID string) {
+			defer wg.Done()
+			defer func() { <-workers }()
+			results[i].storageGroup, results[i].err = e.client.GetStorageGroup(symID, sgID)
+		}(i, sgID)
+	}
+	wg.Wait()
+	return results
+}
+
+func collectStoragePool(set *metricSet, symID string, srp *types.StoragePool) {
+	labels := map[string]string{"symmetrix_id": symID, "srp_id": srp.StoragePoolID}
+	if srp.SrpCap != nil {
+		set.gauge(srpUsableDesc, srp.SrpCap.UsableTotInTB*bytesPerTB, labels)
+		set.gauge(srpUsableUsedDesc, srp.SrpCap.UsableUsedInTB*bytesPerTB, labels)
+		set.gauge(srpSubscribedDesc, srp.SrpCap.SubTotInTB*bytesPerTB, labels)
+		set.gauge(srpSubscribedAllocDesc, srp.SrpCap.SubAllocCapInTB*bytesPerTB, labels)
+		set.gauge(srpSnapshotDesc, srp.SrpCap.SnapTotInTB*bytesPerTB, labels)
+		set.gauge(srpSnapshotModifiedDesc, srp.SrpCap.SnapModInTB*bytesPerTB, labels)
+	}
+	if srp.SrpEfficiency != nil {
+		set.gauge(srpEfficiencyDesc, float64(srp.SrpEfficiency.EfficiencyRatioToOne), labels)
+		set.gauge(srpDataReductionDesc, float64(srp.SrpEfficiency.DataReductionRatioToOne), labels)
+		set.gauge(srpDataReductionOnDesc, float64(srp.SrpEfficiency.DataReductionEnabledPerc), labels)
+		set.gauge(srpVirtualProvSavingDesc, float64(srp.SrpEfficiency.VirtProvSavingRatioToOne), labels)
+		set.gauge(srpSnapshotSavingDesc, float64(srp.SrpEfficiency.SanpSavingRatioToOne), labels)
+	}
+}
+
+func collectStorageGroup(set *metricSet, symID string, sg *types.StorageGroup) {
+	labels := map[string]string{
+		"symmetrix_id":     symID,
+		"storage_group_id": sg.StorageGroupID,
+		"srp_id":           sg.SRP,
+		"service_level":    sg.SLO,
+	}
+	set.gauge(sgCapacityDesc, sg.CapacityGB*bytesPerGB, labels)
+	set.gauge(sgVolumesDesc, float64(sg.NumOfVolumes), labels)
+	if sg.SLOCompliance == "" {
+		return
+	}
+	states := sloComplianceStates
+	if !stringInSlice(sg.SLOCompliance, states) {
+		states = append(states[:len(states):len(states)], sg.SLOCompliance)
+	}
+	for _, state := range states {
+		current := 0.0
+		if state == sg.SLOCompliance {
+			current = 1
+		}
+		set.gauge(sgComplianceDesc, current, map[string]string{
+			"symmetrix_id":     symID,
+			"storage_group_id": sg.StorageGroupID,
+			"compliance":       state,
+		})
+	}
+}
+
+func stringInSlice(value string, values []string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Invalid Method", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	if err := WriteText(w, e.Metrics()); err != nil {
+		log.Error("Could not write the metrics: " + err.Error())
+	}
+}
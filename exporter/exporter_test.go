@@ -0,0 +1,262 @@
+/*
+ Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+package exporter
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pmax "github.com/dell/gopowermax"
+	"github.com/dell/gopowermax/mock"
+	types "github.com/dell/gopowermax/types/v90"
+)
+
+const symID = "000197900046"
+
+// newMockClient returns a client of a mock Unisphere server and a function stopping the server
+func newMockClient(t *testing.T) (pmax.Pmax, func()) {
+	mock.Reset()
+	// the mock reads its JSON files relative to the directory of the tests
+	mock.Data.JSONDir = "../mock"
+	server := httptest.NewServer(mock.GetHandler())
+	client, err := pmax.NewClientWithArgs(server.URL, "", "", true, false)
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+	err = client.Authenticate(&pmax.ConfigConnect{Username: "username", Password: "password"})
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+	return client, server.Close
+}
+
+func findMetric(metrics []*Metric, name string) *Metric {
+	for _, metric := range metrics {
+		if metric.Name == name {
+			return metric
+		}
+	}
+	return nil
+}
+
+func expectValue(t *testing.T, metrics []*Metric, name string, labels map[string]string, expected float64) {
+	t.Helper()
+	metric := findMetric(metrics, name)
+	if metric == nil {
+		t.Fatalf("Expected metric %s", name)
+	}
+	value, ok := metric.Value(labels)
+	if !ok {
+		t.Fatalf("Expected metric %s with labels %v but got %v", name, labels, metric.Samples)
+	}
+	if value != expected {
+		t.Errorf("Expected metric %s with labels %v to be %v but got %v", name, labels, expected, value)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	client, stop := newMockClient(t)
+	defer stop()
+	metrics := New(client, Config{SymmetrixIDs: []string{symID}}).Collect()
+
+	array := map[string]string{"symmetrix_id": symID}
+	expectValue(t, metrics, "powermax_up", array, 1)
+	expectValue(t, metrics, "powermax_array_info", map[string]string{"symmetrix_id": symID, "model": "PowerMax_2000"}, 1)
+	expectValue(t, metrics, "powermax_array_devices", array, 1045)
+
+	srp := map[string]string{"symmetrix_id": symID, "srp_id": "SRP_1"}
+	expectValue(t, metrics, "powermax_srp_usable_capacity_bytes", srp, 3.42*bytesPerTB)
+	expectValue(t, metrics, "powermax_srp_usable_used_capacity_bytes", srp, 1.39*bytesPerTB)
+	expectValue(t, metrics, "powermax_srp_subscribed_capacity_bytes", srp, 0.4*bytesPerTB)
+	expectValue(t, metrics, "powermax_srp_efficiency_ratio", srp, float64(float32(2.2)))
+
+	sg := map[string]string{"symmetrix_id": symID, "storage_group_id": mock.DefaultStorageGroup}
+	expectValue(t, metrics, "powermax_storage_group_capacity_bytes", sg, 234.5*bytesPerGB)
+	expectValue(t, metrics, "powermax_storage_group_compliance", map[string]string{
+		"symmetrix_id":     symID,
+		"storage_group_id": mock.DefaultStorageGroup,
+		"compliance":       "STABLE",
+	}, 1)
+	for _, compliance := range []string{"MARGINAL", "CRITICAL", "NONE"} {
+		expectValue(t, metrics, "powermax_storage_group_compliance", map[string]string{
+			"symmetrix_id":     symID,
+			"storage_group_id": mock.DefaultStorageGroup,
+			"compliance":       compliance,
+		}, 0)
+	}
+	expectValue(t, metrics, "powermax_scrape_errors", array, 0)
+
+	expectValue(t, metrics, "powermax_port_up", map[string]string{"symmetrix_id": symID, "director_id": "FA-1D"}, 1)
+	if metric := findMetric(metrics, "powermax_port_up"); metric == nil || len(metric.Samples) == 0 {
+		t.Errorf("Expected the status of the front end ports")
+	}
+}
+
+func TestCollectArrayError(t *testing.T) {
+	client, stop := newMockClient(t)
+	defer stop()
+	mock.InducedErrors.GetStoragePoolListError = true
+	metrics := New(client, Config{SymmetrixIDs: []string{symID}, Namespace: "pmax"}).Collect()
+
+	expectValue(t, metrics, "pmax_up", map[string]string{"symmetrix_id": symID}, 0)
+	if findMetric(metrics, "pmax_scrape_duration_seconds") == nil {
+		t.Errorf("Expected the scrape duration of the array")
+	}
+	if metric := findMetric(metrics, "pmax_array_info"); metric != nil {
+		t.Errorf("Expected no metrics of an array which could not be collected but got %v", metric)
+	}
+}
+
+// failingClient fails to fetch the storage resource pools and one of the storage groups
+type failingClient struct {
+	pmax.Pmax
+	failedStorageGroupID string
+}
+
+func (c *failingClient) GetStoragePool(symID, storagePoolID string) (*types.StoragePool, error) {
+	return nil, errors.New("induced error")
+}
+
+func (c *failingClient) GetStorageGroup(symID, storageGroupID string) (*types.StorageGroup, error) {
+	if storageGroupID == c.failedStorageGroupID {
+		return nil, errors.New("induced error")
+	}
+	return c.Pmax.GetStorageGroup(symID, storageGroupID)
+}
+
+func TestCollectSkipsResourceErrors(t *testing.T) {
+	client, stop := newMockClient(t)
+	defer stop()
+	sgList, err := client.GetStorageGroupIDList(symID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failing := &failingClient{Pmax: client, failedStorageGroupID: mock.DefaultStorageGroup}
+	metrics := New(failing, Config{SymmetrixIDs: []string{symID}}).Collect()
+
+	array := map[string]string{"symmetrix_id": symID}
+	expectValue(t, metrics, "powermax_up", array, 1)
+	expectValue(t, metrics, "powermax_scrape_errors", array, 2)
+	expectValue(t, metrics, "powermax_array_devices", array, 1045)
+	if metric := findMetric(metrics, "powermax_srp_usable_capacity_bytes"); metric != nil {
+		t.Errorf("Expected no metrics of the storage resource pools which could not be fetched but got %v", metric)
+	}
+	metric := findMetric(metrics, "powermax_storage_group_capacity_bytes")
+	if metric == nil || len(metric.Samples) != len(sgList.StorageGroupIDs)-1 {
+		t.Fatalf("Expected the capacity of all the storage groups but %s, got %v", mock.DefaultStorageGroup, metric)
+	}
+	if _, ok := metric.Value(map[string]string{"storage_group_id": mock.DefaultStorageGroup}); ok {
+		t.Errorf("Expected no metrics of the storage group which could not be fetched")
+	}
+	if metric := findMetric(metrics, "powermax_port_up"); metric == nil || len(metric.Samples) == 0 {
+		t.Errorf("Expected the status of the front end ports")
+	}
+}
+
+func TestCollectMaxStorageGroups(t *testing.T) {
+	client, stop := newMockClient(t)
+	defer stop()
+	for _, sgID := range []string{"CSI-Exporter-SG-1", "CSI-Exporter-SG-2", "CSI-Exporter-SG-3"} {
+		if _, err := mock.AddStorageGroup(sgID, "SRP_1", "Diamond"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sgList, err := client.GetStorageGroupIDList(symID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, max := range []int{0, 2} {
+		metrics := New(client, Config{SymmetrixIDs: []string{symID}, StorageGroupWorkers: 2, MaxStorageGroups: max}).Collect()
+		expected := len(sgList.StorageGroupIDs)
+		if max > 0 {
+			expected = max
+		}
+		if metric := findMetric(metrics, "powermax_storage_group_capacity_bytes"); metric == nil || len(metric.Samples) != expected {
+			t.Errorf("Expected the capacity of %d storage groups but got %v", expected, metric)
+		}
+	}
+}
+
+func TestServeHTTPCachesMetrics(t *testing.T) {
+	client, stop := newMockClient(t)
+	defer stop()
+	e := New(client, Config{SymmetrixIDs: []string{symID}, CacheTTL: time.Hour})
+
+	scrape := func() string {
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected status %d but got %d", http.StatusOK, recorder.Code)
+		}
+		if contentType := recorder.Header().Get("Content-Type"); contentType != ContentType {
+			t.Errorf("Expected content type %s but got %s", ContentType, contentType)
+		}
+		body, _ := ioutil.ReadAll(recorder.Body)
+		return string(body)
+	}
+
+	first := scrape()
+	if !strings.Contains(first, "# TYPE powermax_up gauge\n") {
+		t.Fatalf("Expected the metrics in the text exposition format but got %s", first)
+	}
+	if _, err := mock.AddStorageGroup("CSI-Exporter-SG", "SRP_1", "Diamond"); err != nil {
+		t.Fatal(err)
+	}
+	if second := scrape(); strings.Contains(second, "CSI-Exporter-SG") {
+		t.Errorf("Expected the cached metrics without the new storage group")
+	}
+	e.Collect()
+	if third := scrape(); !strings.Contains(third, `storage_group_id="CSI-Exporter-SG"`) {
+		t.Errorf("Expected the collected metrics with the new storage group")
+	}
+
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status %d but got %d", http.StatusMethodNotAllowed, recorder.Code)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	metrics := []*Metric{
+		{
+			Name: "test_gauge",
+			Help: "A gauge\nwith a \\ in its help",
+			Type: MetricTypeGauge,
+			Samples: []Sample{
+				{Labels: map[string]string{"b": "2", "a": `quoted "value"`}, Value: 1.5},
+				{Value: 1e12},
+			},
+		},
+	}
+	var buffer bytes.Buffer
+	if err := WriteText(&buffer, metrics); err != nil {
+		t.Fatal(err)
+	}
+	expected := "# HELP test_gauge A gauge\\nwith a \\\\ in its help\n" +
+		"# TYPE test_gauge gauge\n" +
+		"test_gauge{a=\"quoted \\\"value\\\"\",b=\"2\"} 1.5\n" +
+		"test_gauge 1e+12\n"
+	if buffer.String() != expected {
+		t.Errorf("Expected\n%s\nbut got\n%s", expected, buffer.String())
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+package exporter
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the content type of the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// MetricTypeGauge is the type of a metric whose value can go up and down
+const MetricTypeGauge = "gauge"
+
+// Metric is a family of samples sharing a name, help text and type
+type Metric struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+// Sample is the value of a metric for a set of labels
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// Value returns the value of the first sample of the metric matching the labels, and false if there is none
+func (m *Metric) Value(labels map[string]string) (float64, bool) {
+	for _, sample := range m.Samples {
+		matches := true
+		for name, value := range labels {
+			if sample.Labels[name] != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return sample.Value, true
+		}
+	}
+	return 0, false
+}
+
+// WriteText writes the metrics in the Prometheus text exposition format
+func WriteText(w io.Writer, metrics []*Metric) error {
+	writer := bufio.NewWriter(w)
+	for _, metric := range metrics {
+		writer.WriteString("# HELP " + metric.Name + " " + escapeHelp(metric.Help) + "\n")
+		writer.WriteString("# TYPE " + metric.Name + " " + metric.Type + "\n")
+		for _, sample := range metric.Samples {
+			writer.WriteString(metric.Name + formatLabels(sample.Labels) + " " + formatValue(sample.Value) + "\n")
+		}
+	}
+	return writer.Flush()
+}
+
+// formatLabels returns the labels sorted by name, in braces, or an empty string if there are no labels
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+"=\""+escapeLabelValue(labels[name])+"\"")
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	helpEscaper       = strings.NewReplacer("\\", "\\\\", "\n", "\\n")
+	labelValueEscaper = strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\"", "\\\"")
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+// metricSet groups the samples of a collection into metrics, in the order the metrics are first set
+type metricSet struct {
+	namespace string
+	metrics   []*Metric
+	byName    map[string]*Metric
+}
+
+func newMetricSet(namespace string) *metricSet {
+	return &metricSet{
+		namespace: namespace,
+		metrics:   make([]*Metric, 0),
+		byName:    make(map[string]*Metric),
+	}
+}
+
+// description is the name, without namespace, and the help text of a metric
+type description struct {
+	name string
+	help string
+}
+
+// gauge adds a sample to the gauge of the description
+func (s *metricSet) gauge(desc description, value float64, labels map[string]string) {
+	name := desc.name
+	if s.namespace != "" {
+		name = s.namespace + "_" + name
+	}
+	metric, ok := s.byName[name]
+	if !ok {
+		metric = &Metric{Name: name, Help: desc.help, Type: MetricTypeGauge}
+		s.byName[name] = metric
+		s.metrics = append(s.metrics, metric)
+	}
+	metric.Samples = append(metric.Samples, Sample{Labels: labels, Value: value})
+}
+
+// merge adds the samples of the other set to the set
+func (s *metricSet) merge(other *metricSet) {
+	for _, metric := range other.metrics {
+		existing, ok := s.byName[metric.Name]
+		if !ok {
+			existing = &Metric{Name: metric.Name, Help: metric.Help, Type: metric.Type}
+			s.byName[metric.Name] = existing
+			s.metrics = append(s.metrics, existing)
+		}
+		existing.Samples = append(existing.Samples, metric.Samples...)
+	}
+}
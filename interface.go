@@ -13,7 +13,13 @@
 */
 package pmax
 
-import types "github.com/dell/gopowermax/types/v90"
+import (
+	"context"
+	"time"
+
+	"github.com/dell/gopowermax/api"
+	types "github.com/dell/gopowermax/types/v90"
+)
 
 // Debug is a boolean, when enabled, that enables logging of send payloads, and other debug information. Default to false.
 // It is set true by unit testing.
@@ -34,6 +40,7 @@ const (
 	DefaultAPIVersion = "90"
 	APIVersion90      = "90"
 	APIVersion91      = "91"
+	APIVersion92      = "92"
 )
 
 // Pmax interface has all the externally available functions provided by the pmax client library for the Powermax accessed through Unisphere.
@@ -88,8 +95,13 @@ type Pmax interface {
 
 	// CreateVolumeInStorageGroup takes simplified input arguments to create a volume of a give name and size in a particular storage group.
 	// This method creates a job and waits on the job to complete.
+	// The remote mirror of a volume created in an SRDF protected storage group is created in the remote storage group of the same name.
 	CreateVolumeInStorageGroup(symID string, storageGroupID string, volumeName string, sizeInCylinders int) (*types.Volume, error)
 
+	// CreateRDFVolumeInStorageGroup creates a volume in an SRDF protected storage group along with its remote mirror
+	// in the given remote storage group. The returned RDFDevicePair holds the local and the remote volume ids.
+	CreateRDFVolumeInStorageGroup(symID string, storageGroupID string, remoteStorageGroupID string, volumeName string, sizeInCylinders int) (*types.RDFDevicePair, error)
+
 	// DeleteStorageGroup deletes a storage group given a storage group id
 	DeleteStorageGroup(symID string, storageGroupID string) error
 
@@ -128,6 +140,19 @@ type Pmax interface {
 	// host id and the port id and returns the masking view object
 	CreateMaskingView(symID string, maskingViewID string, storageGroupID string, hostOrhostGroupID string, isHost bool, portGroupID string) (*types.MaskingView, error)
 
+	// RenameMaskingView renames a masking view given the Masking view id and the new name
+	RenameMaskingView(symID string, maskingViewID string, newName string) (*types.MaskingView, error)
+
+	// GetMaskingViewsForHost returns the names of the masking views containing the host (or host group).
+	// Unisphere does the filtering, so the full list of masking views is never fetched.
+	GetMaskingViewsForHost(symID string, hostOrHostGroupID string) (*types.MaskingViewList, error)
+
+	// GetMaskingViewsForStorageGroup returns the names of the masking views containing the storage group.
+	GetMaskingViewsForStorageGroup(symID string, storageGroupID string) (*types.MaskingViewList, error)
+
+	// GetMaskingViewsForPortGroup returns the names of the masking views containing the port group.
+	GetMaskingViewsForPortGroup(symID string, portGroupID string) (*types.MaskingViewList, error)
+
 	// CreatePortGroup creates a port group given the Port Group id and a list of dir/port ids
 	CreatePortGroup(symID string, portGroupID string, dirPorts []types.PortKey) (*types.PortGroup, error)
 
@@ -152,6 +177,13 @@ type Pmax interface {
 	GetInitiatorList(symID string, initiatorHBA string, isISCSI bool, inHost bool) (*types.InitiatorList, error)
 	// GetInitiatorByID returns an Initiator given the Initiator id.
 	GetInitiatorByID(symID string, initID string) (*types.Initiator, error)
+	// GetInitiatorListWithFilter returns a list of the Initiator ids matching the filter, which can select
+	// initiators by HBA, login and fabric status, director (port) and host group.
+	GetInitiatorListWithFilter(symID string, filter *types.InitiatorFilter) (*types.InitiatorList, error)
+	// SetInitiatorAlias sets the alias (node_name/port_name) of an Initiator
+	SetInitiatorAlias(symID string, initiatorID string, nodeName string, portName string) (*types.Initiator, error)
+	// GetInitiatorsForHost returns the full Initiator records of all the initiators of a Host
+	GetInitiatorsForHost(symID string, hostID string) ([]*types.Initiator, error)
 
 	// GetHostList returns a list of all the Host ids.
 	GetHostList(symID string) (*types.HostList, error)
@@ -167,18 +199,43 @@ type Pmax interface {
 	UpdateHostInitiators(symID string, host *types.Host, initiatorIDs []string) (*types.Host, error)
 	// GetDirectorIDList returns a list of directors
 	GetDirectorIDList(symID string) (*types.DirectorIDList, error)
+	// GetDirector returns the details (type, slot, availability, number of ports) of a director
+	GetDirector(symID string, directorID string) (*types.Director, error)
+	// ListDirectors returns the details of the directors matching the filter, e.g. the online front end directors
+	ListDirectors(symID string, filter *types.DirectorFilter) ([]*types.Director, error)
 	// GetPortList returns a list of all the ports on a specified director/array.
 	GetPortList(symID string, directorID string, query string) (*types.PortList, error)
 	// GetPort returns port details.
 	GetPort(symID string, directorID string, portID string) (*types.Port, error)
 	// GetListOfTargetAddresses returns an array of all IP addresses which expose iscsi targets.
 	GetListOfTargetAddresses(symID string) ([]string, error)
+	// GetISCSITargets returns the iSCSI targets (IQN, IP interfaces, network ID and director port)
+	// exposed by the array. The directors are scanned concurrently.
+	GetISCSITargets(symID string) ([]types.ISCSITarget, error)
+	// GetISCSITargetsForPortGroup returns the iSCSI targets exposed by the ports of a port group.
+	GetISCSITargetsForPortGroup(symID string, portGroupID string) ([]types.ISCSITarget, error)
+	// CreateISCSIPortGroup creates a port group from a list of iSCSI target IQNs
+	CreateISCSIPortGroup(symID string, portGroupID string, targetIQNs []string) (*types.PortGroup, error)
+	// GetFrontEndPorts returns the details (WWN or IQN, speeds, status and load) of the front end ports
+	// matching the filter. A nil filter returns all the front end ports.
+	GetFrontEndPorts(symID string, filter *types.PortFilter) ([]*types.Port, error)
+	// SelectLeastLoadedFCPorts returns up to count online Fibre Channel ports, least loaded first,
+	// spread across as many directors as possible.
+	SelectLeastLoadedFCPorts(symID string, count int) ([]types.PortKey, error)
 
 	// SetAllowedArrays sets the list of arrays which can be manipulated
 	// an empty list will allow all arrays to be accessed
 	SetAllowedArrays(arrays []string) error
 	// GetAllowedArrays returns a slice of arrays that can be manipulated
 	GetAllowedArrays() []string
+	// AddObserver adds an observer notified of the API requests, retries, job waits and function calls of the client
+	AddObserver(observer Observer)
+	// SetTracer sets the tracer of the spans of the functions and API requests of the client
+	SetTracer(tracer Tracer)
+	// WithContext returns a copy of the client whose spans are children of the span of the context
+	WithContext(ctx context.Context) Pmax
+	// SetWireLogging enables the logging of the requests and responses of the client, nil disables it
+	SetWireLogging(config *api.WireLogConfig)
 	// IsAllowedArray checks to see if we can manipulate the specified array
 	IsAllowedArray(array string) (bool, error)
 
@@ -194,22 +251,141 @@ type Pmax interface {
 	ModifySnapshot(symID string, sourceVol []types.VolumeList,
 		targetVol []types.VolumeList, SnapID string, action string,
 		newSnapID string, generation int64) error
+	// CreateSnapshotWithOptions creates a snapVx snapshot of the volumes with a time to live, optionally secure
+	CreateSnapshotWithOptions(symID string, SnapID string, sourceVolumeList []types.VolumeList, options *types.SnapshotOptions) error
+	// SetSnapshotTTL sets the time to live of a snapshot generation, optionally making it secure
+	SetSnapshotTTL(symID, SnapID string, sourceVolumes []types.VolumeList, generation int64, options *types.SnapshotOptions) error
+	// GetSnapshotRetention returns the generations of a snapshot of a volume, whether they are secure or expired and their time to live
+	GetSnapshotRetention(symID, volumeID, SnapID string) (types.SnapshotGenerations, error)
+	// CloneVolume creates a linked or fully copied clone of a volume in a storage group, cleaning up on failure
+	CloneVolume(symID, srcVolumeID, targetSG, name string, options *types.CloneVolumeOptions) (*types.Volume, error)
+	// CollectSnapshots deletes, or reports in dry run mode, the expired, old, orphaned or deleted volume snapshot generations matching a policy
+	CollectSnapshots(symID string, policy *types.SnapshotGCPolicy) (*types.SnapshotGCReport, error)
+	// GetVolumeSnapshotGenerations returns the generations of all the snapshots of a volume with parsed timestamps
+	GetVolumeSnapshotGenerations(symID string, volumeID string) (types.SnapshotGenerations, error)
+	// CreateConsistentSnapshot creates a snapshot of a set of volumes of the array at the same point in time
+	CreateConsistentSnapshot(symID, snapID string, sourceVolumeList []types.VolumeList, options *types.SnapshotOptions) (*types.ConsistentSnapshot, error)
+	// LinkConsistentSnapshot links the generations of a consistent snapshot to a set of target volumes
+	LinkConsistentSnapshot(symID string, snapshot *types.ConsistentSnapshot, targetVolumes []types.VolumeList, options *types.LinkSnapshotOptions) error
+	// LinkSnapshot links a snapshot generation to target volumes in copy or nocopy mode, or relinks the targets
+	LinkSnapshot(symID, SnapID string, sourceVolumes, targetVolumes []types.VolumeList, generation int64, options *types.LinkSnapshotOptions) error
+	// WaitForLinkState waits up to the timeout for the link of a snapshot to a target volume to reach the Linked or Copied state
+	WaitForLinkState(symID, SnapID, sourceVolumeID, targetVolumeID, state string, timeout time.Duration) (*types.LinkSnapshotGenInfo, error)
 	// DeleteSnapshot deletes a snapshot from a volume
 	DeleteSnapshot(symID, SnapID string, sourceVolumes []types.VolumeList, generation int64) error
+	// RestoreSnapshot restores a snapshot generation to its source volumes and terminates the restore session
+	RestoreSnapshot(symID, SnapID string, sourceVolumes []types.VolumeList, generation int64) error
 	// GetSnapshotGenerations returns a list of all the snapshot generation on a specific snapshot
 	GetSnapshotGenerations(symID, volume, SnapID string) (*types.VolumeSnapshotGenerations, error)
 	// GetSnapshotGenerationInfo returns the specific generation info related to a snapshot
 	GetSnapshotGenerationInfo(symID, volume, SnapID string, generation int64) (*types.VolumeSnapshotGeneration, error)
+	// CreateStorageGroupSnapshot creates a snapVx snapshot of all the volumes of a storage group
+	CreateStorageGroupSnapshot(symID, sgID, snapID string, options *types.SnapshotOptions) (*types.StorageGroupSnapshot, error)
+	// ListStorageGroupSnapshots returns the names of the snapshots of a storage group
+	ListStorageGroupSnapshots(symID, sgID string) (*types.StorageGroupSnapshotList, error)
+	// GetStorageGroupSnapshotGenerations returns the generations of a storage group snapshot
+	GetStorageGroupSnapshotGenerations(symID, sgID, snapID string) (*types.StorageGroupSnapshotGenerationList, error)
+	// GetStorageGroupSnapshot returns a generation of a storage group snapshot
+	GetStorageGroupSnapshot(symID, sgID, snapID string, generation int64) (*types.StorageGroupSnapshot, error)
+	// LinkStorageGroupSnapshot links a generation of a storage group snapshot to the volumes of another storage group
+	LinkStorageGroupSnapshot(symID, sgID, snapID string, generation int64, linkSGID string) error
+	// UnlinkStorageGroupSnapshot unlinks a generation of a storage group snapshot from a linked storage group
+	UnlinkStorageGroupSnapshot(symID, sgID, snapID string, generation int64, linkSGID string) error
+	// RestoreStorageGroupSnapshot restores a generation of a storage group snapshot to the volumes of the storage group
+	RestoreStorageGroupSnapshot(symID, sgID, snapID string, generation int64) error
+	// RenameStorageGroupSnapshot renames a generation of a storage group snapshot
+	RenameStorageGroupSnapshot(symID, sgID, snapID string, generation int64, newSnapID string) error
+	// DeleteStorageGroupSnapshot deletes a generation of a storage group snapshot
+	DeleteStorageGroupSnapshot(symID, sgID, snapID string, generation int64) error
+	// GetSnapshotPolicyList returns the names of the snapshot policies of the array
+	GetSnapshotPolicyList(symID string) (*types.SnapshotPolicyList, error)
+	// GetSnapshotPolicy returns a snapshot policy
+	GetSnapshotPolicy(symID, snapshotPolicyID string) (*types.SnapshotPolicy, error)
+	// CreateSnapshotPolicy creates a snapshot policy
+	CreateSnapshotPolicy(symID string, createParam *types.CreateSnapshotPolicyParam) (*types.SnapshotPolicy, error)
+	// ModifySnapshotPolicy changes the settings of a snapshot policy
+	ModifySnapshotPolicy(symID, snapshotPolicyID string, modifyParam *types.ModifySnapshotPolicyParam) (*types.SnapshotPolicy, error)
+	// SuspendSnapshotPolicy stops a snapshot policy from taking snapshots
+	SuspendSnapshotPolicy(symID, snapshotPolicyID string) (*types.SnapshotPolicy, error)
+	// ResumeSnapshotPolicy resumes taking snapshots with a suspended snapshot policy
+	ResumeSnapshotPolicy(symID, snapshotPolicyID string) (*types.SnapshotPolicy, error)
+	// AssociateSnapshotPolicy associates storage groups to a snapshot policy
+	AssociateSnapshotPolicy(symID, snapshotPolicyID string, storageGroupIDs ...string) (*types.SnapshotPolicy, error)
+	// DisassociateSnapshotPolicy disassociates storage groups from a snapshot policy
+	DisassociateSnapshotPolicy(symID, snapshotPolicyID string, storageGroupIDs ...string) (*types.SnapshotPolicy, error)
+	// DeleteSnapshotPolicy deletes a snapshot policy
+	DeleteSnapshotPolicy(symID, snapshotPolicyID string) error
+	// GetSnapshotPolicyStorageGroups returns the storage groups associated to a snapshot policy
+	GetSnapshotPolicyStorageGroups(symID, snapshotPolicyID string) (*types.SnapshotPolicyStorageGroupList, error)
+	// GetStorageGroupSnapshotCompliance returns the compliance of a storage group with its snapshot policies
+	GetStorageGroupSnapshotCompliance(symID, sgID string) (*types.StorageGroupSnapshotCompliance, error)
 	// GetReplicationCapabilities returns details about SnapVX and SRDF execution capabilities on the Symmetrix array
 	GetReplicationCapabilities() (*types.SymReplicationCapabilities, error)
+	// GetRDFGroupList returns the number and label of all the RDF groups on the Symmetrix
+	GetRDFGroupList(symID string) (*types.RDFGroupList, error)
+	// GetRDFGroup returns the details of an RDF group: remote array, SRDF modes, director ports, device count and link state
+	GetRDFGroup(symID string, rdfGroupNumber int) (*types.RDFGroup, error)
+	// GetRDFGroupVolumes returns the ids of the local volumes in an RDF group
+	GetRDFGroupVolumes(symID string, rdfGroupNumber int) (*types.RDFGroupVolumeList, error)
+	// GetRDFDevicePair returns the SRDF relationship of a volume in an RDF group
+	GetRDFDevicePair(symID string, rdfGroupNumber int, volumeID string) (*types.RDFDevicePair, error)
+	// CreateSGReplica protects a storage group with SRDF in the given mode, creating the remote storage group and volumes
+	CreateSGReplica(symID, remoteSymID, rdfMode string, rdfGroupNumber int, sgID, remoteSGID, remoteServiceLevel string, establish bool) (*types.SGRDFInfo, error)
+	// GetStorageGroupRDFGroupList returns the numbers of the RDF groups protecting a storage group
+	GetStorageGroupRDFGroupList(symID, sgID string) (*types.SGRDFGroupList, error)
+	// GetStorageGroupRDFInfo returns the SRDF types, modes and pair states of a storage group in an RDF group
+	GetStorageGroupRDFInfo(symID, sgID string, rdfGroupNumber int) (*types.SGRDFInfo, error)
+	// ExecuteSRDFAction performs an SRDF action (Establish, Split, Suspend, Resume, Failover, Failback or Swap) on the SRDF pairs of a storage group
+	ExecuteSRDFAction(symID, sgID string, rdfGroupNumber int, action string, force bool) error
+	// SetMetroBias moves the bias of the SRDF/Metro pairs of a storage group to the R1 or R2 side
+	SetMetroBias(symID, sgID string, rdfGroupNumber int, biasSide string) error
+	// GetMetroStatus returns the SRDF/Metro pair states, bias side and witness state of a storage group
+	GetMetroStatus(symID, sgID string, rdfGroupNumber int) (*types.MetroStatus, error)
+	// DeleteSGReplica deletes the SRDF pairs of a storage group in an RDF group
+	DeleteSGReplica(symID, sgID string, rdfGroupNumber int, force bool) error
 	// GetPrivVolumeByID returns a Volume structure given the symmetrix and volume ID (volume ID is in WWN format)
 	GetPrivVolumeByID(symID string, volumeID string) (*types.VolumeResultPrivate, error)
+	// GetReplicationGraph returns the snapshots, linked targets, clone and mirror sessions related to the volumes
+	GetReplicationGraph(symID string, volumeIDs ...string) (*types.ReplicationGraph, error)
 
 	// Delete PortGroup
 	DeletePortGroup(symID string, portGroupID string) error
 	// Update PortGroup
 	UpdatePortGroup(symID string, portGroupId string, ports []types.PortKey) (*types.PortGroup, error)
+	// RenamePortGroup renames a PortGroup
+	RenamePortGroup(symID string, portGroupID string, newName string) (*types.PortGroup, error)
+	// AddPortsToPortGroup adds ports to a PortGroup without replacing the existing ones
+	AddPortsToPortGroup(symID string, portGroupID string, ports []types.PortKey) (*types.PortGroup, error)
+	// RemovePortsFromPortGroup removes ports from a PortGroup
+	RemovePortsFromPortGroup(symID string, portGroupID string, ports []types.PortKey) (*types.PortGroup, error)
+	// GetPortGroupDetails returns a PortGroup along with the details of each of its ports
+	GetPortGroupDetails(symID string, portGroupID string) (*types.PortGroupDetails, error)
 
 	// Expand the size of an existing volume
+	// SRDF protected volumes paired in a single RDF group are expanded on both sides
 	ExpandVolume(symID string, volumeID string, newSizeGB int) (*types.Volume, error)
+	// ExpandRDFVolume expands an SRDF protected volume and its remote mirror in an RDF group, or in its only RDF group if rdfGroupNumber is 0,
+	// returning the local and the remote volume ids
+	ExpandRDFVolume(symID string, volumeID string, rdfGroupNumber int, newSizeGB int) (*types.RDFDevicePair, error)
+	// GetVolumeRDFPair returns the SRDF relationship of a volume paired in a single RDF group, including its remote volume
+	GetVolumeRDFPair(symID string, volumeID string) (*types.RDFDevicePair, error)
+
+	// GetArrayPerformanceKeys returns the time range of the performance data of the array
+	GetArrayPerformanceKeys(symID string) ([]types.PerformanceKey, error)
+	// GetStorageGroupPerformanceKeys returns the storage groups with performance data
+	GetStorageGroupPerformanceKeys(symID string) ([]types.PerformanceKey, error)
+	// GetFEDirectorPerformanceKeys returns the front-end directors with performance data
+	GetFEDirectorPerformanceKeys(symID string) ([]types.PerformanceKey, error)
+	// GetFEPortPerformanceKeys returns the ports of a front-end director with performance data
+	GetFEPortPerformanceKeys(symID, directorID string) ([]types.PerformanceKey, error)
+	// GetArrayPerformance returns the IOPS, MB/s, response time and cache hit samples of the array
+	GetArrayPerformance(symID string, query *types.PerformanceQuery) (*types.PerformanceResult, error)
+	// GetStorageGroupPerformance returns the IOPS, MB/s, response time and cache hit samples of a storage group
+	GetStorageGroupPerformance(symID, sgID string, query *types.PerformanceQuery) (*types.PerformanceResult, error)
+	// GetVolumePerformance returns the IOPS, MB/s, response time and cache hit samples of a volume
+	GetVolumePerformance(symID, volumeID string, query *types.PerformanceQuery) (*types.PerformanceResult, error)
+	// GetFEDirectorPerformance returns the IOPS, MB/s and utilization samples of a front-end director
+	GetFEDirectorPerformance(symID, directorID string, query *types.PerformanceQuery) (*types.PerformanceResult, error)
+	// GetFEPortPerformance returns the IOPS, MB/s and utilization samples of a port of a front-end director
+	GetFEPortPerformance(symID, directorID, portID string, query *types.PerformanceQuery) (*types.PerformanceResult, error)
 }
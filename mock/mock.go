@@ -20,7 +20,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -43,6 +45,7 @@ const (
 	DefaultStorageGroup1    = "CSI-Test-SG-2"
 	DefaultSymmetrixID      = "000197900046"
 	PostELMSRSymmetrixID    = "000197900047"
+	DefaultRemoteSymID      = "000000000013"
 	DefaultStoragePool      = "SRP_1"
 	DefaultServiceLevel     = "Optimized"
 	DefaultFcStoragePortWWN = "5000000000000001"
@@ -77,6 +80,7 @@ var Data struct {
 	HostIDToHost                  map[string]*types.Host
 	PortGroupIDToPortGroup        map[string]*types.PortGroup
 	PortIDToSymmetrixPortType     map[string]*types.SymmetrixPortType
+	DirectorIDToDirector          map[string]*types.Director
 	VolumeIDToVolume              map[string]*types.Volume
 	JSONDir                       string
 	InitiatorHost                 string
@@ -84,6 +88,34 @@ var Data struct {
 	//Snapshots
 	VolIDToSnapshots  map[string]map[string]*types.Snapshot
 	SnapIDToLinkedVol map[string]map[string]*types.LinkedVolumes
+	// LinkCopyPolls is the number of times the links in copy mode are reported in CopyInProgress before they are Copied
+	LinkCopyPolls int
+	// SnapIDToRetention maps SnapID:volID to the time to live (in hours) and security of the snapshot
+	SnapIDToRetention map[string]*types.SnapshotHeader
+	// VolumeIDToCloneSessions and VolumeIDToMirrorSessions map the volumes to the sessions they are the source or target of
+	VolumeIDToCloneSessions  map[string][]types.CloneSession
+	VolumeIDToMirrorSessions map[string][]types.MirrorSession
+	// StorageGroupIDToSnapshots maps the snapshot names of a storage group to their generations, newest first
+	StorageGroupIDToSnapshots map[string]map[string][]*types.StorageGroupSnapshot
+
+	//Snapshot policies
+	SnapshotPolicyIDToSnapshotPolicy map[string]*types.SnapshotPolicy
+	// SnapshotPolicyIDToStorageGroups maps the snapshot policies to the storage groups associated to them
+	SnapshotPolicyIDToStorageGroups map[string][]string
+
+	//SRDF
+	RDFGroupIDToRDFGroup    map[string]*types.RDFGroup
+	RDFGroupIDToDevicePairs map[string]map[string]*types.RDFDevicePair
+	// StorageGroupIDToRDFGroups maps the RDF groups protecting a storage group to the remote storage group
+	StorageGroupIDToRDFGroups map[string]map[string]string
+	RemoteVolumeCounter       int
+	RemoteVolumeIDToSize      map[string]float64
+
+	// IteratorIDToPerformanceResults maps the iterators of the performance metrics with more than one page to their results
+	IteratorIDToPerformanceResults map[string][]map[string]float64
+
+	// TraceParents are the traceparent headers of the requests received, in order
+	TraceParents []string
 }
 
 // InducedErrors constants
@@ -114,6 +146,7 @@ var InducedErrors struct {
 	GetDirectorError               bool
 	GetInitiatorError              bool
 	GetInitiatorByIDError          bool
+	UpdateInitiatorError           bool
 	GetHostError                   bool
 	CreateHostError                bool
 	DeleteHostError                bool
@@ -122,6 +155,7 @@ var InducedErrors struct {
 	CreateMaskingViewError         bool
 	MaskingViewAlreadyExists       bool
 	DeleteMaskingViewError         bool
+	RenameMaskingViewError         bool
 	PortGroupNotFoundError         bool
 	InitiatorGroupNotFoundError    bool
 	StorageGroupNotFoundError      bool
@@ -130,6 +164,7 @@ var InducedErrors struct {
 	ResetAfterFirstError           bool
 	CreateSnapshotError            bool
 	LinkSnapshotError              bool
+	DeleteSnapshotError            bool
 	GetSymVolumeError              bool
 	GetVolSnapsError               bool
 	GetSnapshotError               bool
@@ -139,11 +174,30 @@ var InducedErrors struct {
 	UnisphereMismatchError         bool
 	TargetNotDefinedError          bool
 	SnapshotExpired                bool
+	SetSnapshotTTLError            bool
 	InvalidSnapshotName            bool
 	GetPrivVolumeByIDError         bool
 	CreatePortGroupError           bool
 	UpdatePortGroupError           bool
 	DeletePortGroupError           bool
+	GetRDFGroupError               bool
+	CreateSGReplicaError           bool
+	ModifySGReplicaError           bool
+	DeleteSGReplicaError           bool
+	CreateSGSnapshotError          bool
+	GetSGSnapshotError             bool
+	ModifySGSnapshotError          bool
+	DeleteSGSnapshotError          bool
+	RestoreSnapshotError           bool
+	RestoreInProgressError         bool
+	SnapshotCopyInProgressError    bool
+	GetSnapshotPolicyError         bool
+	CreateSnapshotPolicyError      bool
+	ModifySnapshotPolicyError      bool
+	DeleteSnapshotPolicyError      bool
+	GetPerformanceKeysError        bool
+	GetPerformanceMetricsError     bool
+	GetPerformancePageError        bool
 }
 
 // hasError checks to see if the specified error (via pointer)
@@ -189,6 +243,7 @@ func Reset() {
 	InducedErrors.GetDirectorError = false
 	InducedErrors.GetInitiatorError = false
 	InducedErrors.GetInitiatorByIDError = false
+	InducedErrors.UpdateInitiatorError = false
 	InducedErrors.GetHostError = false
 	InducedErrors.CreateHostError = false
 	InducedErrors.DeleteHostError = false
@@ -197,6 +252,7 @@ func Reset() {
 	InducedErrors.CreateMaskingViewError = false
 	InducedErrors.MaskingViewAlreadyExists = false
 	InducedErrors.DeleteMaskingViewError = false
+	InducedErrors.RenameMaskingViewError = false
 	InducedErrors.PortGroupNotFoundError = false
 	InducedErrors.InitiatorGroupNotFoundError = false
 	InducedErrors.StorageGroupNotFoundError = false
@@ -205,6 +261,7 @@ func Reset() {
 	InducedErrors.ResetAfterFirstError = false
 	InducedErrors.CreateSnapshotError = false
 	InducedErrors.LinkSnapshotError = false
+	InducedErrors.DeleteSnapshotError = false
 	InducedErrors.GetSymVolumeError = false
 	InducedErrors.GetVolSnapsError = false
 	InducedErrors.GetSnapshotError = false
@@ -214,12 +271,34 @@ func Reset() {
 	InducedErrors.UnisphereMismatchError = false
 	InducedErrors.TargetNotDefinedError = false
 	InducedErrors.SnapshotExpired = false
+	InducedErrors.SetSnapshotTTLError = false
 	InducedErrors.InvalidSnapshotName = false
 	InducedErrors.GetPrivVolumeByIDError = false
 	InducedErrors.CreatePortGroupError = false
 	InducedErrors.UpdatePortGroupError = false
 	InducedErrors.DeletePortGroupError = false
+	InducedErrors.GetRDFGroupError = false
+	InducedErrors.CreateSGReplicaError = false
+	InducedErrors.ModifySGReplicaError = false
+	InducedErrors.DeleteSGReplicaError = false
+	InducedErrors.CreateSGSnapshotError = false
+	InducedErrors.GetSGSnapshotError = false
+	InducedErrors.ModifySGSnapshotError = false
+	InducedErrors.DeleteSGSnapshotError = false
+	InducedErrors.RestoreSnapshotError = false
+	InducedErrors.RestoreInProgressError = false
+	InducedErrors.SnapshotCopyInProgressError = false
+	InducedErrors.GetSnapshotPolicyError = false
+	InducedErrors.CreateSnapshotPolicyError = false
+	InducedErrors.ModifySnapshotPolicyError = false
+	InducedErrors.DeleteSnapshotPolicyError = false
+	InducedErrors.GetPerformanceKeysError = false
+	InducedErrors.GetPerformanceMetricsError = false
+	InducedErrors.GetPerformancePageError = false
 	Data.JSONDir = "mock"
+	Data.TraceParents = make([]string, 0)
+	Data.LinkCopyPolls = 0
+	Data.IteratorIDToPerformanceResults = make(map[string][]map[string]float64)
 	Data.VolumeIDToIdentifier = make(map[string]string)
 	Data.VolumeIDToSize = make(map[string]int)
 	Data.VolumeIDIteratorList = make([]string, 0)
@@ -238,10 +317,22 @@ func Reset() {
 	Data.HostIDToHost = make(map[string]*types.Host)
 	Data.PortGroupIDToPortGroup = make(map[string]*types.PortGroup)
 	Data.PortIDToSymmetrixPortType = make(map[string]*types.SymmetrixPortType)
+	Data.DirectorIDToDirector = make(map[string]*types.Director)
 	Data.VolumeIDToVolume = make(map[string]*types.Volume)
 	Data.StorageGroupIDToVolumes = make(map[string][]string)
 	Data.VolIDToSnapshots = make(map[string]map[string]*types.Snapshot)
 	Data.SnapIDToLinkedVol = make(map[string]map[string]*types.LinkedVolumes)
+	Data.SnapIDToRetention = make(map[string]*types.SnapshotHeader)
+	Data.VolumeIDToCloneSessions = make(map[string][]types.CloneSession)
+	Data.VolumeIDToMirrorSessions = make(map[string][]types.MirrorSession)
+	Data.StorageGroupIDToSnapshots = make(map[string]map[string][]*types.StorageGroupSnapshot)
+	Data.SnapshotPolicyIDToSnapshotPolicy = make(map[string]*types.SnapshotPolicy)
+	Data.SnapshotPolicyIDToStorageGroups = make(map[string][]string)
+	Data.RDFGroupIDToRDFGroup = make(map[string]*types.RDFGroup)
+	Data.RDFGroupIDToDevicePairs = make(map[string]map[string]*types.RDFDevicePair)
+	Data.StorageGroupIDToRDFGroups = make(map[string]map[string]string)
+	Data.RemoteVolumeCounter = 0
+	Data.RemoteVolumeIDToSize = make(map[string]float64)
 	initMockCache()
 }
 
@@ -299,6 +390,10 @@ func initMockCache() {
 	initNode3List = append(initNode3List, hba1Node3)
 	initNode3List = append(initNode3List, hba2Node3)
 	AddHost("CSI-Test-Node-3-FC", "Fibre", initNode3List)
+	// Initialize RDF groups
+	AddRDFGroup(10, "csi-sync", DefaultRemoteSymID, 10, types.RDFModeSynchronous)
+	AddRDFGroup(11, "csi-async", DefaultRemoteSymID, 11, types.RDFModeAsynchronous)
+	AddRDFGroup(12, "csi-metro", DefaultRemoteSymID, 12, types.RDFModeActive)
 	AddTempSnapshots()
 }
 
@@ -311,6 +406,9 @@ func GetHandler() http.Handler {
 			if Debug {
 				log.Printf("handler called: %s %s", r.Method, r.URL)
 			}
+			if traceParent := r.Header.Get("traceparent"); traceParent != "" {
+				Data.TraceParents = append(Data.TraceParents, traceParent)
+			}
 			if InducedErrors.InvalidJSON {
 				w.Write([]byte(`this is not json`))
 			} else if InducedErrors.NoConnection {
@@ -344,6 +442,8 @@ func getRouter() http.Handler {
 	router.HandleFunc(PREFIX+"/sloprovisioning/symmetrix/{symid}/srp/{id}", handleStorageResourcePool)
 	router.HandleFunc(PREFIX+"/sloprovisioning/symmetrix/{symid}/srp", handleStorageResourcePool)
 	router.HandleFunc(PREFIXNOVERSION+"/common/Iterator/{iterId}/page", handleIterator)
+	router.HandleFunc(PREFIXNOVERSION+"/performance/{category}/keys", handlePerformanceKeys)
+	router.HandleFunc(PREFIXNOVERSION+"/performance/{category}/metrics", handlePerformanceMetrics)
 	router.HandleFunc(PREFIX+"/sloprovisioning/symmetrix/{symid}/volume/{volID}", handleVolume)
 	router.HandleFunc(PREFIX+"/sloprovisioning/symmetrix/{symid}/volume", handleVolume)
 	router.HandleFunc(PRIVATEPREFIX+"/sloprovisioning/symmetrix/{symid}/volume", handlePrivVolume)
@@ -367,6 +467,19 @@ func getRouter() http.Handler {
 	router.HandleFunc(PRIVATEPREFIX+"/replication/symmetrix/{symid}/volume/{volID}/snapshot/{SnapID}/generation", handleGenerations)
 	router.HandleFunc(PRIVATEPREFIX+"/replication/symmetrix/{symid}/volume/{volID}/snapshot/{SnapID}/generation/{genID}", handleGenerations)
 	router.HandleFunc(PREFIX+"/replication/capabilities/symmetrix", handleCapabilities)
+	router.HandleFunc(PREFIX+"/replication/symmetrix/{symid}/rdf_group", handleRDFGroup)
+	router.HandleFunc(PREFIX+"/replication/symmetrix/{symid}/rdf_group/{rdfgNo}", handleRDFGroup)
+	router.HandleFunc(PREFIX+"/replication/symmetrix/{symid}/rdf_group/{rdfgNo}/volume", handleRDFGroupVolume)
+	router.HandleFunc(PREFIX+"/replication/symmetrix/{symid}/rdf_group/{rdfgNo}/volume/{volID}", handleRDFGroupVolume)
+	router.HandleFunc(PREFIX+"/replication/symmetrix/{symid}/storagegroup/{id}/rdf_group", handleSGRDFGroup)
+	router.HandleFunc(PREFIX+"/replication/symmetrix/{symid}/storagegroup/{id}/rdf_group/{rdfgNo}", handleSGRDFGroup)
+	router.HandleFunc(PREFIX+"/replication/symmetrix/{symid}/storagegroup/{id}/snapshot", handleSGSnapshot)
+	router.HandleFunc(PREFIX+"/replication/symmetrix/{symid}/storagegroup/{id}/compliance/snapshot", handleSGSnapshotCompliance)
+	router.HandleFunc(PREFIX+"/replication/symmetrix/{symid}/snapshot_policy", handleSnapshotPolicy)
+	router.HandleFunc(PREFIX+"/replication/symmetrix/{symid}/snapshot_policy/{id}", handleSnapshotPolicy)
+	router.HandleFunc(PREFIX+"/replication/symmetrix/{symid}/snapshot_policy/{id}/storagegroup", handleSnapshotPolicyStorageGroups)
+	router.HandleFunc(PREFIX+"/replication/symmetrix/{symid}/storagegroup/{id}/snapshot/{SnapID}/generation", handleSGSnapshotGeneration)
+	router.HandleFunc(PREFIX+"/replication/symmetrix/{symid}/storagegroup/{id}/snapshot/{SnapID}/generation/{genID}", handleSGSnapshotGeneration)
 
 	mockRouter = router
 	return router
@@ -398,6 +511,8 @@ func handleVersion(w http.ResponseWriter, r *http.Request) {
 		break
 	case "91":
 		break
+	case "92":
+		break
 	default:
 		writeError(w, "Unsupport API version: "+apiversion, http.StatusServiceUnavailable)
 	}
@@ -630,12 +745,27 @@ func expandVolume(w http.ResponseWriter, param *types.ExpandVolumeParam, volID s
 	case "GB":
 	}
 
-	if err == nil {
-		Data.VolumeIDToVolume[volID].CapacityGB = newSize
-	} else {
+	if err != nil {
 		writeError(w, fmt.Sprintf("Could not convert expand size parameter in request (%s)", param.VolumeAttribute.VolumeSize), http.StatusBadRequest)
 		return
 	}
+	// An SRDF protected volume can only be expanded along with its remote mirror
+	var devicePair *types.RDFDevicePair
+	if vol, ok := Data.VolumeIDToVolume[volID]; ok && len(vol.RDFGroupIDList) > 0 {
+		if param.RDFGroupNumber == 0 {
+			writeError(w, "Cannot expand an SRDF protected volume without an RDF group number", http.StatusBadRequest)
+			return
+		}
+		devicePair, ok = Data.RDFGroupIDToDevicePairs[strconv.Itoa(param.RDFGroupNumber)][volID]
+		if !ok {
+			writeError(w, fmt.Sprintf("Volume %s is not paired in RDF group %d", volID, param.RDFGroupNumber), http.StatusBadRequest)
+			return
+		}
+	}
+	Data.VolumeIDToVolume[volID].CapacityGB = newSize
+	if devicePair != nil {
+		Data.RemoteVolumeIDToSize[devicePair.RemoteVolumeName] = newSize
+	}
 	returnVolume(w, volID)
 }
 
@@ -716,6 +846,10 @@ func returnJobByID(w http.ResponseWriter, jobID string) {
 // /unixvmax/restapi/common/Iterator/{iterID]/page}
 func handleIterator(w http.ResponseWriter, r *http.Request) {
 	var err error
+	if _, ok := Data.IteratorIDToPerformanceResults[mux.Vars(r)["iterId"]]; ok {
+		handlePerformanceIterator(w, r)
+		return
+	}
 	switch r.Method {
 	case http.MethodGet:
 		vars := mux.Vars(r)
@@ -750,6 +884,39 @@ func handleIterator(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handlePerformanceIterator returns a page of the results of a performance metrics iterator, or deletes the iterator
+func handlePerformanceIterator(w http.ResponseWriter, r *http.Request) {
+	iterID := mux.Vars(r)["iterId"]
+	results := Data.IteratorIDToPerformanceResults[iterID]
+	switch r.Method {
+	case http.MethodGet:
+		if InducedErrors.GetPerformancePageError {
+			writeError(w, "Error getting the performance metrics page: induced error", http.StatusRequestTimeout)
+			return
+		}
+		from, err := strconv.Atoi(r.URL.Query().Get("from"))
+		if err != nil || from < 1 || from > len(results) {
+			writeError(w, "bad from query parameter", http.StatusBadRequest)
+			return
+		}
+		to, err := strconv.Atoi(r.URL.Query().Get("to"))
+		if err != nil || to < from || to-from+1 > performanceMaxPageSize {
+			writeError(w, "bad to query parameter", http.StatusBadRequest)
+			return
+		}
+		if to > len(results) {
+			to = len(results)
+		}
+		writeJSON(w, &types.PerformanceMetricsResultList{
+			Result: results[from-1 : to],
+			From:   from,
+			To:     to,
+		})
+	case http.MethodDelete:
+		delete(Data.IteratorIDToPerformanceResults, iterID)
+	}
+}
+
 // /univmax/restapi/90/sloprovisioning/symmetrix/{symid}/storagegroup/{id}
 // /univmax/restapi/90/sloprovisioning/symmetrix/{symid}/storagegroup
 func handleStorageGroup(w http.ResponseWriter, r *http.Request) {
@@ -859,8 +1026,36 @@ func handleMaskingView(w http.ResponseWriter, r *http.Request) {
 			writeError(w, "Error retrieving Masking View(s): induced error", http.StatusRequestTimeout)
 			return
 		}
+		if mvID == "" {
+			returnMaskingViewListWithFilters(w, r.URL.Query())
+			return
+		}
 		returnMaskingView(w, mvID)
 
+	case http.MethodPut:
+		if InducedErrors.RenameMaskingViewError {
+			writeError(w, "Error renaming Masking view: induced error", http.StatusRequestTimeout)
+			return
+		}
+		decoder := json.NewDecoder(r.Body)
+		updateMVPayload := &types.EditMaskingViewParam{}
+		err := decoder.Decode(updateMVPayload)
+		if err != nil {
+			writeError(w, "problem decoding PUT Masking View payload: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if updateMVPayload.EditMaskingViewActionParam == nil ||
+			updateMVPayload.EditMaskingViewActionParam.RenameMaskingViewParam == nil {
+			writeError(w, "Unsupported masking view action", http.StatusBadRequest)
+			return
+		}
+		newMVID := updateMVPayload.EditMaskingViewActionParam.RenameMaskingViewParam.NewMaskingViewName
+		if err := renameMaskingView(mvID, newMVID); err != nil {
+			writeError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		returnMaskingView(w, newMVID)
+
 	case http.MethodPost:
 		if InducedErrors.CreateMaskingViewError {
 			writeError(w, "Failed to create masking view: induced error", http.StatusRequestTimeout)
@@ -1027,6 +1222,35 @@ func AddMaskingView(maskingViewID string, storageGroupID string, hostID string,
 	return Data.MaskingViewIDToMaskingView[maskingViewID], nil
 }
 
+// renameMaskingView - Renames a masking view in the mock data cache
+func renameMaskingView(maskingViewID string, newMaskingViewID string) error {
+	mv, ok := Data.MaskingViewIDToMaskingView[maskingViewID]
+	if !ok {
+		return errors.New("Masking View " + maskingViewID + " doesn't exist")
+	}
+	if _, ok := Data.MaskingViewIDToMaskingView[newMaskingViewID]; ok {
+		return errors.New("Masking View " + newMaskingViewID + " already exists")
+	}
+	mv.MaskingViewID = newMaskingViewID
+	delete(Data.MaskingViewIDToMaskingView, maskingViewID)
+	Data.MaskingViewIDToMaskingView[newMaskingViewID] = mv
+	if sg, ok := Data.StorageGroupIDToStorageGroup[mv.StorageGroupID]; ok {
+		for i, mvID := range sg.MaskingView {
+			if mvID == maskingViewID {
+				sg.MaskingView[i] = newMaskingViewID
+			}
+		}
+	}
+	if host, ok := Data.HostIDToHost[mv.HostID]; ok {
+		for i, mvID := range host.MaskingviewIDs {
+			if mvID == maskingViewID {
+				host.MaskingviewIDs[i] = newMaskingViewID
+			}
+		}
+	}
+	return nil
+}
+
 // RemoveMaskingView - Removes a masking view from the mock data cache
 func RemoveMaskingView(w http.ResponseWriter, maskingViewID string) {
 	mv, ok := Data.MaskingViewIDToMaskingView[maskingViewID]
@@ -1091,6 +1315,25 @@ func RemoveMaskingView(w http.ResponseWriter, maskingViewID string) {
 	delete(Data.StorageGroupIDToStorageGroup, maskingViewID)
 }
 
+// volumeListNames - returns the names of the volumes of a volume list
+func volumeListNames(volumeList []types.VolumeList) []string {
+	names := make([]string, 0, len(volumeList))
+	for _, volume := range volumeList {
+		names = append(names, volume.Name)
+	}
+	return names
+}
+
+// stringInSlice - returns true if the string is in the slice
+func stringInSlice(a string, list []string) bool {
+	for _, b := range list {
+		if b == a {
+			return true
+		}
+	}
+	return false
+}
+
 // compareAndCheck - compares two string slices and returns true if the slices are equal or false if they aren't
 func compareAndCheck(slice1 []string, slice2 []string) bool {
 	for _, item := range slice1 {
@@ -1204,7 +1447,62 @@ func AddInitiator(initiatorID string, initiatorName string, initiatorType string
 	return Data.InitiatorIDToInitiator[initiatorID], nil
 }
 
-func returnInitiator(w http.ResponseWriter, initiatorID string) {
+// SetInitiatorLoginStatus - Sets the login and fabric status of an initiator in the mock data cache
+func SetInitiatorLoginStatus(initiatorID string, loggedIn bool, onFabric bool) error {
+	initiator, ok := Data.InitiatorIDToInitiator[initiatorID]
+	if !ok {
+		return errors.New("Error! Initiator doesn't exist")
+	}
+	initiator.LoggedIn = loggedIn
+	initiator.OnFabric = onFabric
+	return nil
+}
+
+// initiatorMatchesFilters returns true if the initiator satisfies all the query filters
+func initiatorMatchesFilters(initiator *types.Initiator, queryParams url.Values) bool {
+	if hba := queryParams.Get("initiator_hba"); hba != "" && initiator.InitiatorID != hba {
+		return false
+	}
+	if queryParams.Get("iscsi") == "true" && initiator.InitiatorType != "GigE" {
+		return false
+	}
+	if queryParams.Get("in_a_host") == "true" && initiator.HostID == "" {
+		return false
+	}
+	if loggedIn := queryParams.Get("logged_in"); loggedIn != "" && strconv.FormatBool(initiator.LoggedIn) != loggedIn {
+		return false
+	}
+	if onFabric := queryParams.Get("on_fabric"); onFabric != "" && strconv.FormatBool(initiator.OnFabric) != onFabric {
+		return false
+	}
+	if dirPort := queryParams.Get("dir_port"); dirPort != "" {
+		found := false
+		for _, portKey := range initiator.SymmetrixPortKey {
+			if portKey.DirectorID == dirPort || portKey.PortID == dirPort {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if hostGroupID := queryParams.Get("host_group_id"); hostGroupID != "" {
+		found := false
+		for _, id := range initiator.HostGroupIDs {
+			if id == hostGroupID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func returnInitiator(w http.ResponseWriter, initiatorID string, queryParams url.Values) {
 	if initiatorID != "" {
 		if init, ok := Data.InitiatorIDToInitiator[initiatorID]; ok {
 			writeJSON(w, init)
@@ -1213,8 +1511,10 @@ func returnInitiator(w http.ResponseWriter, initiatorID string) {
 		w.WriteHeader(http.StatusNotFound)
 	} else {
 		initIDs := make([]string, 0)
-		for k := range Data.InitiatorIDToInitiator {
-			initIDs = append(initIDs, k)
+		for k, v := range Data.InitiatorIDToInitiator {
+			if initiatorMatchesFilters(v, queryParams) {
+				initIDs = append(initIDs, k)
+			}
 		}
 		initiatorIDList := &types.InitiatorList{
 			InitiatorIDs: initIDs,
@@ -1273,7 +1573,6 @@ func AddHost(hostID string, hostType string, initiatorIDs []string) (*types.Host
 		for k, v := range Data.InitiatorIDToInitiator {
 			if v.InitiatorID == initID {
 				Data.InitiatorIDToInitiator[k].HostID = hostID
-				break
 			}
 		}
 	}
@@ -1343,6 +1642,22 @@ func updatePortGroup(portGroupID string, editPayload *types.EditPortGroupActionP
 
 	// Update the PortGroup mapping with the update PortGroup
 	Data.PortGroupIDToPortGroup[portGroupID] = pg
+
+	// Rename the PortGroup (if requested)
+	if editPayload.RenamePortGroupParam != nil {
+		newPortGroupID := editPayload.RenamePortGroupParam.NewPortGroupName
+		if _, ok := Data.PortGroupIDToPortGroup[newPortGroupID]; ok {
+			return nil, fmt.Errorf("Error! PortGroup %s already exists", newPortGroupID)
+		}
+		pg.PortGroupID = newPortGroupID
+		delete(Data.PortGroupIDToPortGroup, portGroupID)
+		Data.PortGroupIDToPortGroup[newPortGroupID] = pg
+		for _, mv := range Data.MaskingViewIDToMaskingView {
+			if mv.PortGroupID == portGroupID {
+				mv.PortGroupID = newPortGroupID
+			}
+		}
+	}
 	return pg, nil
 }
 
@@ -1384,8 +1699,8 @@ func removePortKey(slice []types.PortKey, keyToRemove types.PortKey) []types.Por
 }
 
 // UpdatePortGroupFromParams - Updates PortGroup given an EditPortGroup payload
-func UpdatePortGroupFromParams(portGroupID string, updateParams *types.EditPortGroup) {
-	updatePortGroup(portGroupID, updateParams.EditPortGroupActionParam)
+func UpdatePortGroupFromParams(portGroupID string, updateParams *types.EditPortGroup) (*types.PortGroup, error) {
+	return updatePortGroup(portGroupID, updateParams.EditPortGroupActionParam)
 }
 
 // DeletePortGroup - Remove PortGroup by ID 'portGroupID'
@@ -1487,6 +1802,31 @@ func returnMaskingView(w http.ResponseWriter, mvID string) {
 	}
 }
 
+// returnMaskingViewListWithFilters returns the masking view ids matching the
+// host_or_host_group_name, storage_group_name and port_group_name filters
+func returnMaskingViewListWithFilters(w http.ResponseWriter, queryParams url.Values) {
+	hostFilter := queryParams.Get("host_or_host_group_name")
+	sgFilter := queryParams.Get("storage_group_name")
+	pgFilter := queryParams.Get("port_group_name")
+	maskingViewIDs := make([]string, 0)
+	for k, mv := range Data.MaskingViewIDToMaskingView {
+		if hostFilter != "" && mv.HostID != hostFilter && mv.HostGroupID != hostFilter {
+			continue
+		}
+		if sgFilter != "" && mv.StorageGroupID != sgFilter {
+			continue
+		}
+		if pgFilter != "" && mv.PortGroupID != pgFilter {
+			continue
+		}
+		maskingViewIDs = append(maskingViewIDs, k)
+	}
+	maskingViewIDList := &types.MaskingViewList{
+		MaskingViewIDs: maskingViewIDs,
+	}
+	writeJSON(w, maskingViewIDList)
+}
+
 func writeJSON(w http.ResponseWriter, val interface{}) {
 	if InducedErrors.InvalidResponse {
 		fmt.Println("Inducing error")
@@ -1561,8 +1901,30 @@ func addVolumeToStorageGroupTest(w http.ResponseWriter, addVolumeParam *types.Ad
 	if err != nil {
 		writeError(w, "unable to convert size string to integer", http.StatusBadRequest)
 	}
+	// New volumes of an SRDF protected storage group are paired with new remote volumes
+	rdfGroupIDs := make([]string, 0)
+	for rdfgID := range Data.StorageGroupIDToRDFGroups[sgID] {
+		rdfGroupIDs = append(rdfGroupIDs, rdfgID)
+	}
+	if len(rdfGroupIDs) > 0 {
+		remoteSGInfo := addVolumeParam.RemoteSymmSGInfoParam
+		if remoteSGInfo == nil || len(remoteSGInfo.RemoteSymmetrix1SGs) == 0 {
+			writeError(w, "The remote storage group is required to add volumes to an SRDF protected storage group", http.StatusBadRequest)
+			return
+		}
+		for _, rdfgID := range rdfGroupIDs {
+			if Data.RDFGroupIDToRDFGroup[rdfgID].RemoteSymmetrix != remoteSGInfo.RemoteSymmetrix1ID {
+				writeError(w, "The storage group is not SRDF protected on remote Symmetrix "+remoteSGInfo.RemoteSymmetrix1ID, http.StatusBadRequest)
+				return
+			}
+		}
+	}
 	if InducedErrors.VolumeNotCreatedError == false {
 		AddOneVolumeToStorageGroup(id, name, sgID, sizeInt)
+		for _, rdfgID := range rdfGroupIDs {
+			rdfGroupNumber, _ := strconv.Atoi(rdfgID)
+			AddRDFDevicePair(rdfGroupNumber, id, newRemoteVolumeID(), rdfEstablishedState(Data.RDFGroupIDToRDFGroup[rdfgID]))
+		}
 	}
 	// Make a job to return
 	resourceLink := fmt.Sprintf("sloprovisioning/system/%s/storagegroup/%s", DefaultSymmetrixID, sgID)
@@ -1693,8 +2055,12 @@ func handlePortGroup(w http.ResponseWriter, r *http.Request) {
 			writeError(w, "InvalidJson", http.StatusBadRequest)
 			return
 		}
-		UpdatePortGroupFromParams(pgID, updatePortGroupParams)
-		returnPortGroup(w, pgID)
+		pg, err := UpdatePortGroupFromParams(pgID, updatePortGroupParams)
+		if err != nil {
+			writeError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		returnPortGroup(w, pg.PortGroupID)
 	case http.MethodDelete:
 		if InducedErrors.DeletePortGroupError {
 			writeError(w, "Error deleting Port Group: induced error", http.StatusRequestTimeout)
@@ -1731,12 +2097,22 @@ func handlePort(w http.ResponseWriter, r *http.Request) {
 					symPort := &types.Port{
 						SymmetrixPort: *port,
 					}
+					symPort.SymmetrixPort.SymmetrixPortKey = types.PortKey{
+						DirectorID: dID,
+						PortID:     pID,
+					}
 					encoder := json.NewEncoder(w)
 					encoder.Encode(symPort)
 				}
 				return
 			}
 			returnPort(w, dID, pID)
+			return
+		}
+		// Fibre Channel directors have no iSCSI targets
+		if isFCDirector(dID) && r.URL.Query().Get("iscsi_target") == "true" {
+			writeJSON(w, &types.PortList{SymmetrixPortKey: []types.PortKey{}})
+			return
 		}
 		// return a list of Ports
 		returnPortIDList(w, dID)
@@ -1755,10 +2131,35 @@ func AddPort(id, identifier, portType string) {
 	Data.PortIDToSymmetrixPortType[id] = port
 }
 
+// AddFCPort adds a Fibre Channel port entry with the given status and load, overriding the template
+func AddFCPort(id, wwn, portStatus string, numOfMappedVols, numOfMaskingViews int64) {
+	port := &types.SymmetrixPortType{
+		Type:              types.PortTypeFibreChannel,
+		Identifier:        wwn,
+		PortStatus:        portStatus,
+		DirectorStatus:    types.DirectorStatusOnline,
+		NegotiatedSpeed:   "16",
+		MaxSpeed:          "32",
+		NumOfMappedVols:   numOfMappedVols,
+		NumOfMaskingViews: numOfMaskingViews,
+	}
+	Data.PortIDToSymmetrixPortType[id] = port
+}
+
+func isFCDirector(dID string) bool {
+	return strings.HasPrefix(dID, "FA-")
+}
+
 func returnPort(w http.ResponseWriter, dID, pID string) {
 	replacements := make(map[string]string)
 	replacements["__PORT_ID__"] = pID
 	replacements["__DIRECTOR_ID__"] = dID
+	if isFCDirector(dID) {
+		// derive a unique WWN from the director number and port number
+		replacements["__WWN__"] = fmt.Sprintf("50000973%x%04s", strings.TrimPrefix(dID, "FA-"), pID)
+		returnJSONFile(Data.JSONDir, "fc_port_template.json", w, replacements)
+		return
+	}
 	returnJSONFile(Data.JSONDir, "port_template.json", w, replacements)
 }
 
@@ -1783,6 +2184,7 @@ func handleDirector(w http.ResponseWriter, r *http.Request) {
 		// if we asked for a specific Director, return those details
 		if dID != "" {
 			returnDirector(w, dID)
+			return
 		}
 		// return a list of Directors
 		returnDirectorIDList(w)
@@ -1792,7 +2194,23 @@ func handleDirector(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// AddDirector adds a director entry with the given availability, overriding the template
+func AddDirector(id string, availability string, numOfPorts int64) {
+	Data.DirectorIDToDirector[id] = &types.Director{
+		DirectorID:         id,
+		DirectorNumber:     1,
+		DirectorSlotNumber: 1,
+		Availability:       availability,
+		NumberOfPorts:      numOfPorts,
+		NumberOfCores:      4,
+	}
+}
+
 func returnDirector(w http.ResponseWriter, dID string) {
+	if director, ok := Data.DirectorIDToDirector[dID]; ok {
+		writeJSON(w, director)
+		return
+	}
 	replacements := make(map[string]string)
 	replacements["__DIRECTOR_ID__"] = dID
 	returnJSONFile(Data.JSONDir, "director_template.json", w, replacements)
@@ -1821,7 +2239,30 @@ func handleInitiator(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 		}
-		returnInitiator(w, initID)
+		returnInitiator(w, initID, r.URL.Query())
+
+	case http.MethodPut:
+		if InducedErrors.UpdateInitiatorError {
+			writeError(w, "Error updating Initiator: induced error", http.StatusRequestTimeout)
+			return
+		}
+		initiator, ok := Data.InitiatorIDToInitiator[initID]
+		if !ok {
+			writeError(w, "Initiator not found", http.StatusNotFound)
+			return
+		}
+		decoder := json.NewDecoder(r.Body)
+		editParam := &types.EditInitiatorParam{}
+		err := decoder.Decode(editParam)
+		if err != nil {
+			writeError(w, "InvalidJson", http.StatusBadRequest)
+			return
+		}
+		if editParam.EditInitiatorActionParam != nil && editParam.EditInitiatorActionParam.RenameAliasParam != nil {
+			alias := editParam.EditInitiatorActionParam.RenameAliasParam
+			initiator.Alias = alias.NodeName + "/" + alias.PortName
+		}
+		writeJSON(w, initiator)
 
 	default:
 		writeError(w, "Invalid Method", http.StatusBadRequest)
@@ -2025,7 +2466,12 @@ func handleSnapshot(w http.ResponseWriter, r *http.Request) {
 			writeError(w, "problem decoding POST Snapshot payload: "+err.Error(), http.StatusBadRequest)
 			return
 		}
-		createSnapshot(w, r, vars["SnapID"], createSnapParam.ExecutionOption, createSnapParam.SourceVolumeList)
+		retention := &types.SnapshotHeader{TimeToLive: snapshotTTLInHours(createSnapParam.TimeToLive, createSnapParam.TimeInHours)}
+		if createSnapParam.Securettl > 0 {
+			retention.Secured = true
+			retention.TimeToLive = snapshotTTLInHours(createSnapParam.Securettl, createSnapParam.TimeInHours)
+		}
+		createSnapshot(w, r, vars["SnapID"], createSnapParam.ExecutionOption, createSnapParam.SourceVolumeList, retention, createSnapParam.Consistent)
 		return
 	case http.MethodPut:
 		if SnapID == "" {
@@ -2051,7 +2497,15 @@ func handleSnapshot(w http.ResponseWriter, r *http.Request) {
 				writeError(w, "error linking the snapshot: induced error", http.StatusBadRequest)
 				return
 			}
-			linkSnapshot(w, r, updateSnapParam.VolumeNameListSource, updateSnapParam.VolumeNameListTarget, executionOption, SnapID)
+			linkSnapshot(w, r, updateSnapParam.VolumeNameListSource, updateSnapParam.VolumeNameListTarget, executionOption, SnapID, updateSnapParam.Generation, updateSnapParam.Copy)
+			return
+		}
+		if updateSnapParam.Action == "Relink" {
+			if InducedErrors.LinkSnapshotError {
+				writeError(w, "error relinking the snapshot: induced error", http.StatusBadRequest)
+				return
+			}
+			relinkSnapshot(w, r, updateSnapParam.VolumeNameListSource, updateSnapParam.VolumeNameListTarget, executionOption, SnapID, updateSnapParam.Copy)
 			return
 		}
 		if updateSnapParam.Action == "Unlink" {
@@ -2063,8 +2517,20 @@ func handleSnapshot(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if updateSnapParam.Action == "Restore" {
-			// restoreSnapshot(w, r, updateSnapParam.VolumeNameListSource, updateSnapParam.VolumeNameListTarget, executionOption, SnapID)
-			// return
+			if InducedErrors.RestoreSnapshotError {
+				writeError(w, "error restoring the snapshot: induced error", http.StatusBadRequest)
+				return
+			}
+			restoreSnapshot(w, r, updateSnapParam.VolumeNameListSource, executionOption, SnapID)
+			return
+		}
+		if updateSnapParam.Action == types.SnapshotActionSetTimeToLive || updateSnapParam.Action == types.SnapshotActionSetSecure {
+			if InducedErrors.SetSnapshotTTLError {
+				writeError(w, "error setting the time to live of the snapshot: induced error", http.StatusBadRequest)
+				return
+			}
+			setSnapshotTTL(w, r, updateSnapParam, SnapID)
+			return
 		}
 	case http.MethodDelete:
 		decoder := json.NewDecoder(r.Body)
@@ -2074,12 +2540,21 @@ func handleSnapshot(w http.ResponseWriter, r *http.Request) {
 			writeError(w, "problem decoding Delete Snapshot payload: "+err.Error(), http.StatusBadRequest)
 			return
 		}
+		if deleteSnapParam.Restore {
+			terminateSnapshotRestore(w, r, vars["SnapID"], deleteSnapParam.ExecutionOption, deleteSnapParam.DeviceNameListSource)
+			return
+		}
+		if InducedErrors.DeleteSnapshotError {
+			writeError(w, "Failed to delete snapshot: induced error", http.StatusBadRequest)
+			return
+		}
 		deleteSnapshot(w, r, vars["SnapID"], deleteSnapParam.ExecutionOption, deleteSnapParam.DeviceNameListSource, deleteSnapParam.Generation)
 		return
 	}
 }
 
-func createSnapshot(w http.ResponseWriter, r *http.Request, SnapID, executionOption string, sourceVolumeList []types.VolumeList) {
+// A consistent snapshot is taken of all the source volumes at the same point in time
+func createSnapshot(w http.ResponseWriter, r *http.Request, SnapID, executionOption string, sourceVolumeList []types.VolumeList, retention *types.SnapshotHeader, consistent bool) {
 	if strings.Contains(SnapID, ":") {
 		writeError(w, "error, invalid snapshot name", http.StatusBadRequest)
 		return
@@ -2092,6 +2567,10 @@ func createSnapshot(w http.ResponseWriter, r *http.Request, SnapID, executionOpt
 		writeError(w, "few devices not available", http.StatusBadRequest)
 		return
 	}
+	if consistent && len(uniqueElements(volumeListNames(sourceVolumeList))) != len(sourceVolumeList) {
+		writeError(w, "error, a device is given more than once for the consistent snapshot", http.StatusBadRequest)
+		return
+	}
 	// Make a job to return
 	resourceLink := fmt.Sprintf("/replication/symmetrix/%s/snapshot/%s", DefaultSymmetrixID, SnapID)
 	jobID := fmt.Sprintf("SnapID-%d", time.Now().Nanosecond())
@@ -2100,11 +2579,19 @@ func createSnapshot(w http.ResponseWriter, r *http.Request, SnapID, executionOpt
 		returnJobByID(w, jobID)
 		return
 	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
 	for i := 0; i < len(sourceVolumeList); i++ {
 		source := sourceVolumeList[i].Name
 		if !duplicateSnapshotCreationRequest(source, SnapID) {
 			//Snapshot with unique name
 			AddNewSnapshot(source, SnapID)
+			Data.SnapIDToRetention[SnapID+":"+source] = &types.SnapshotHeader{
+				Secured:    retention.Secured,
+				TimeToLive: retention.TimeToLive,
+			}
+			if consistent {
+				Data.VolIDToSnapshots[source][SnapID].Timestamp = timestamp
+			}
 		}
 		NewMockJob(jobID, types.JobStatusRunning, types.JobStatusSucceeded, resourceLink)
 	}
@@ -2113,12 +2600,11 @@ func createSnapshot(w http.ResponseWriter, r *http.Request, SnapID, executionOpt
 
 // AddNewSnapshot adds a snapshot to the mock cache
 func AddNewSnapshot(source, SnapID string) {
-	time := time.Now().Nanosecond()
 	snapshot := &types.Snapshot{
 		Name:       SnapID,
 		Generation: 0,
 		State:      "Established",
-		Timestamp:  strconv.Itoa(time),
+		Timestamp:  strconv.FormatInt(time.Now().Unix(), 10),
 	}
 	snapIDtoSnap := Data.VolIDToSnapshots[source]
 	if snapIDtoSnap == nil {
@@ -2169,8 +2655,15 @@ func deleteSnapshot(w http.ResponseWriter, r *http.Request, SnapID string, execu
 				return
 			}
 
+			//a secure snapshot cannot be deleted before it expires
+			if retention := Data.SnapIDToRetention[snapIDtoLinkedVolKey]; retention != nil && retention.Secured && !snapshotExpired(source, snapIDtoSnap[SnapID]) {
+				writeError(w, "delete cannot be attempted because the snapshot is secure and has not expired", http.StatusBadRequest)
+				return
+			}
+
 			//all checks done: volume exists, snapshot existing without links -> it can be deleted
 			delete(snapIDtoSnap, SnapID)
+			delete(Data.SnapIDToRetention, snapIDtoLinkedVolKey)
 			Data.VolumeIDToVolume[source].SnapSource = false
 			NewMockJob(jobID, types.JobStatusRunning, types.JobStatusSucceeded, resourceLink)
 		}
@@ -2178,6 +2671,70 @@ func deleteSnapshot(w http.ResponseWriter, r *http.Request, SnapID string, execu
 	returnJobByID(w, jobID)
 }
 
+// snapshotExpired returns true if the time to live of the snapshot of the volume has passed
+func snapshotExpired(volID string, snap *types.Snapshot) bool {
+	if InducedErrors.SnapshotExpired {
+		return true
+	}
+	retention := Data.SnapIDToRetention[snap.Name+":"+volID]
+	if retention == nil || retention.TimeToLive == 0 {
+		return false
+	}
+	timestamp, _ := strconv.ParseInt(snap.Timestamp, 10, 64)
+	return time.Unix(timestamp, 0).Add(time.Duration(retention.TimeToLive) * time.Hour).Before(time.Now())
+}
+
+// snapshotTTLInHours converts a time to live in days, or in hours if timeInHours is set, to hours
+func snapshotTTLInHours(ttl int64, timeInHours bool) int64 {
+	return int64(types.SnapshotTimeToLive(ttl, timeInHours) / time.Hour)
+}
+
+func setSnapshotTTL(w http.ResponseWriter, r *http.Request, param *types.ModifyVolumeSnapshot, SnapID string) {
+	if param.ExecutionOption != types.ExecutionOptionAsynchronous {
+		writeError(w, "expected ASYNCHRONOUS", http.StatusBadRequest)
+		return
+	}
+	if fewVolumeUnavalaible(param.VolumeNameListSource) {
+		writeError(w, "few devices not available", http.StatusBadRequest)
+		return
+	}
+	secure := param.Action == types.SnapshotActionSetSecure
+	ttl := snapshotTTLInHours(param.TTL, param.TimeInHours)
+	if secure {
+		ttl = snapshotTTLInHours(param.SecureTTL, param.TimeInHours)
+	}
+	resourceLink := fmt.Sprintf("/replication/symmetrix/%s/snapshot/%s", DefaultSymmetrixID, SnapID)
+	jobID := fmt.Sprintf("SnapID-%d", time.Now().Nanosecond())
+	if InducedErrors.JobFailedError {
+		NewMockJob(jobID, types.JobStatusRunning, types.JobStatusFailed, resourceLink)
+		returnJobByID(w, jobID)
+		return
+	}
+	for _, volID := range param.VolumeNameListSource {
+		if Data.VolIDToSnapshots[volID.Name][SnapID] == nil {
+			writeError(w, "no snapshot information, Snapshot cannot be found", http.StatusBadRequest)
+			return
+		}
+		retention := Data.SnapIDToRetention[SnapID+":"+volID.Name]
+		if retention == nil {
+			retention = &types.SnapshotHeader{}
+			Data.SnapIDToRetention[SnapID+":"+volID.Name] = retention
+		}
+		if retention.Secured && !secure {
+			writeError(w, "the time to live of a secure snapshot cannot be unset", http.StatusBadRequest)
+			return
+		}
+		if retention.Secured && ttl < retention.TimeToLive {
+			writeError(w, "the time to live of a secure snapshot can only be extended", http.StatusBadRequest)
+			return
+		}
+		retention.Secured = secure
+		retention.TimeToLive = ttl
+	}
+	NewMockJob(jobID, types.JobStatusRunning, types.JobStatusSucceeded, resourceLink)
+	returnJobByID(w, jobID)
+}
+
 func renameSnapshot(w http.ResponseWriter, r *http.Request, sourceVolumeList []types.VolumeList, executionOption, oldSnapID, newSnapID string) {
 	if executionOption != types.ExecutionOptionAsynchronous {
 		writeError(w, "expected ASYNCHRONOUS", http.StatusBadRequest)
@@ -2210,7 +2767,7 @@ func renameSnapshot(w http.ResponseWriter, r *http.Request, sourceVolumeList []t
 	}
 }
 
-func linkSnapshot(w http.ResponseWriter, r *http.Request, sourceVolumeList []types.VolumeList, targetVolumeList []types.VolumeList, executionOption, SnapID string) {
+func linkSnapshot(w http.ResponseWriter, r *http.Request, sourceVolumeList []types.VolumeList, targetVolumeList []types.VolumeList, executionOption, SnapID string, generation int64, copy bool) {
 	if executionOption != types.ExecutionOptionAsynchronous {
 		writeError(w, "expected ASYNCHRONOUS", http.StatusBadRequest)
 		return
@@ -2249,6 +2806,10 @@ func linkSnapshot(w http.ResponseWriter, r *http.Request, sourceVolumeList []typ
 				writeError(w, "no snapshot information, snopshot cannot be found on this device", http.StatusBadRequest)
 				return
 			}
+			if snapIDtoSnap[SnapID].Generation != generation {
+				writeError(w, fmt.Sprintf("no snapshot information, generation %d cannot be found on this device", generation), http.StatusBadRequest)
+				return
+			}
 			//all devices exist, #source=#target, snapshot exist, check if target already linked
 			snapIDtoLinkedVolKey := SnapID + ":" + volID.Name
 			volIDToLinkedVols := Data.SnapIDToLinkedVol[snapIDtoLinkedVolKey]
@@ -2264,21 +2825,7 @@ func linkSnapshot(w http.ResponseWriter, r *http.Request, sourceVolumeList []typ
 				}
 			}
 			//all devices exist, #source=#target, snapshot exist, target is not linked -> ideal for Linking
-			time := time.Now().Nanosecond()
-			linkedVolume := &types.LinkedVolumes{
-				TargetDevice: targetVolID,
-				Timestamp:    strconv.Itoa(time),
-				State:        "Linked",
-				Copy:         false,
-				Restored:     false,
-				Linked:       true,
-				Defined:      true,
-			}
-			if InducedErrors.TargetNotDefinedError {
-				linkedVolume.Defined = false
-			}
-
-			volIDToLinkedVols[targetVolID] = linkedVolume
+			volIDToLinkedVols[targetVolID] = newLinkedVolume(targetVolID, copy)
 			Data.SnapIDToLinkedVol[snapIDtoLinkedVolKey] = volIDToLinkedVols
 			Data.VolumeIDToVolume[targetVolID].SnapTarget = true
 			NewMockJob(jobID, types.JobStatusRunning, types.JobStatusSucceeded, resourceLink)
@@ -2286,6 +2833,95 @@ func linkSnapshot(w http.ResponseWriter, r *http.Request, sourceVolumeList []typ
 	}
 	returnJobByID(w, jobID)
 }
+// newLinkedVolume returns the link of a snapshot to a target volume, in copy mode the link is Copied
+// unless SnapshotCopyInProgressError is induced or LinkCopyPolls is set and in nocopy mode the link is Linked
+func newLinkedVolume(targetVolID string, copy bool) *types.LinkedVolumes {
+	linkedVolume := &types.LinkedVolumes{
+		TargetDevice: targetVolID,
+		Timestamp:    strconv.FormatInt(time.Now().Unix(), 10),
+		State:        types.SnapshotLinkStateLinked,
+		Copy:         copy,
+		Restored:     false,
+		Linked:       true,
+		Defined:      true,
+	}
+	if copy {
+		linkedVolume.State = types.SnapshotLinkStateCopied
+		linkedVolume.PercentageCopied = 100
+		if InducedErrors.SnapshotCopyInProgressError || Data.LinkCopyPolls > 0 {
+			linkedVolume.State = types.SnapshotLinkStateCopyInProgress
+			linkedVolume.PercentageCopied = 50
+		}
+	}
+	if InducedErrors.TargetNotDefinedError {
+		linkedVolume.Defined = false
+	}
+	return linkedVolume
+}
+
+// pollLinkCopy completes the copy of a link in copy mode once it has been reported LinkCopyPolls times in CopyInProgress
+func pollLinkCopy(linkedVolume *types.LinkedVolumes) {
+	if !linkedVolume.Copy || linkedVolume.State != types.SnapshotLinkStateCopyInProgress || InducedErrors.SnapshotCopyInProgressError {
+		return
+	}
+	if Data.LinkCopyPolls > 0 {
+		Data.LinkCopyPolls--
+		return
+	}
+	linkedVolume.State = types.SnapshotLinkStateCopied
+	linkedVolume.PercentageCopied = 100
+}
+
+// relinkSnapshot moves the targets linked to another snapshot of the sources to the snapshot SnapID
+func relinkSnapshot(w http.ResponseWriter, r *http.Request, sourceVolumeList []types.VolumeList, targetVolumeList []types.VolumeList, executionOption, SnapID string, copy bool) {
+	if executionOption != types.ExecutionOptionAsynchronous {
+		writeError(w, "expected ASYNCHRONOUS", http.StatusBadRequest)
+		return
+	}
+	if len(sourceVolumeList) != len(targetVolumeList) {
+		writeError(w, "cannot relink snapshot, the number of source and devices should be same", http.StatusBadRequest)
+		return
+	}
+	if fewVolumeUnavalaible(sourceVolumeList) || fewVolumeUnavalaible(targetVolumeList) {
+		writeError(w, "few devices not available", http.StatusBadRequest)
+		return
+	}
+	// find the current link of each target to a snapshot of its source
+	currentLinkKeys := make([]string, len(sourceVolumeList))
+	for key, volID := range sourceVolumeList {
+		if Data.VolIDToSnapshots[volID.Name][SnapID] == nil {
+			writeError(w, "no snapshot information, snopshot cannot be found on this device", http.StatusBadRequest)
+			return
+		}
+		for snapIDtoLinkedVolKey, volIDToLinkedVols := range Data.SnapIDToLinkedVol {
+			if strings.HasSuffix(snapIDtoLinkedVolKey, ":"+volID.Name) && volIDToLinkedVols[targetVolumeList[key].Name] != nil {
+				currentLinkKeys[key] = snapIDtoLinkedVolKey
+			}
+		}
+		if currentLinkKeys[key] == "" {
+			writeError(w, "cannot relink snapshot, target "+targetVolumeList[key].Name+" is not linked", http.StatusBadRequest)
+			return
+		}
+	}
+	resourceLink := fmt.Sprintf("/replication/symmetrix/%s/snapshot/%s", DefaultSymmetrixID, SnapID)
+	jobID := fmt.Sprintf("SnapID-%d", time.Now().Nanosecond())
+	if InducedErrors.JobFailedError {
+		NewMockJob(jobID, types.JobStatusRunning, types.JobStatusFailed, resourceLink)
+	} else {
+		for key, volID := range sourceVolumeList {
+			targetVolID := targetVolumeList[key].Name
+			delete(Data.SnapIDToLinkedVol[currentLinkKeys[key]], targetVolID)
+			snapIDtoLinkedVolKey := SnapID + ":" + volID.Name
+			if Data.SnapIDToLinkedVol[snapIDtoLinkedVolKey] == nil {
+				Data.SnapIDToLinkedVol[snapIDtoLinkedVolKey] = map[string]*types.LinkedVolumes{}
+			}
+			Data.SnapIDToLinkedVol[snapIDtoLinkedVolKey][targetVolID] = newLinkedVolume(targetVolID, copy)
+		}
+		NewMockJob(jobID, types.JobStatusRunning, types.JobStatusSucceeded, resourceLink)
+	}
+	returnJobByID(w, jobID)
+}
+
 func unlinkSnapshot(w http.ResponseWriter, r *http.Request, sourceVolumeList []types.VolumeList, targetVolumeList []types.VolumeList, executionOption, SnapID string) {
 	if executionOption != types.ExecutionOptionAsynchronous {
 		writeError(w, "expected ASYNCHRONOUS", http.StatusBadRequest)
@@ -2344,12 +2980,82 @@ func unlinkSnapshot(w http.ResponseWriter, r *http.Request, sourceVolumeList []t
 	returnJobByID(w, jobID)
 }
 
-//check if all the devices exist in the Mock VolumeIDToVolume or check if any unvailable devices
-func fewVolumeUnavalaible(sourceVolumeList []types.VolumeList) bool {
-	for _, volID := range sourceVolumeList {
-		if Data.VolumeIDToVolume[volID.Name] == nil {
-			return true
-		}
+func restoreSnapshot(w http.ResponseWriter, r *http.Request, sourceVolumeList []types.VolumeList, executionOption, SnapID string) {
+	if executionOption != types.ExecutionOptionAsynchronous {
+		writeError(w, "expected ASYNCHRONOUS", http.StatusBadRequest)
+		return
+	}
+	if len(sourceVolumeList) == 0 || sourceVolumeList[0].Name == "" {
+		writeError(w, "no source volume names given to restore the snapshot", http.StatusBadRequest)
+		return
+	}
+	if fewVolumeUnavalaible(sourceVolumeList) {
+		writeError(w, "few devices not available", http.StatusBadRequest)
+		return
+	}
+	for _, volID := range sourceVolumeList {
+		if Data.VolIDToSnapshots[volID.Name][SnapID] == nil {
+			writeError(w, "no snapshot information, snopshot cannot be found on this device", http.StatusBadRequest)
+			return
+		}
+	}
+	// Make a job to return
+	resourceLink := fmt.Sprintf("/replication/symmetrix/%s/snapshot/%s", DefaultSymmetrixID, SnapID)
+	jobID := fmt.Sprintf("SnapID-%d", time.Now().Nanosecond())
+	if InducedErrors.JobFailedError {
+		NewMockJob(jobID, types.JobStatusRunning, types.JobStatusFailed, resourceLink)
+	} else {
+		for _, volID := range sourceVolumeList {
+			snapshot := Data.VolIDToSnapshots[volID.Name][SnapID]
+			// the restore never completes if RestoreInProgressError is induced
+			snapshot.State = "Restored"
+			if InducedErrors.RestoreInProgressError {
+				snapshot.State = "RestoreInProgress"
+			}
+			snapshot.Restored = true
+		}
+		NewMockJob(jobID, types.JobStatusRunning, types.JobStatusSucceeded, resourceLink)
+	}
+	returnJobByID(w, jobID)
+}
+
+func terminateSnapshotRestore(w http.ResponseWriter, r *http.Request, SnapID string, executionOption string, deviceNameListSource []types.VolumeList) {
+	if executionOption != types.ExecutionOptionAsynchronous {
+		writeError(w, "expected ASYNCHRONOUS", http.StatusBadRequest)
+		return
+	}
+	if fewVolumeUnavalaible(deviceNameListSource) {
+		writeError(w, "few devices not available", http.StatusBadRequest)
+		return
+	}
+	for _, volID := range deviceNameListSource {
+		snapshot := Data.VolIDToSnapshots[volID.Name][SnapID]
+		if snapshot == nil || !snapshot.Restored {
+			writeError(w, "no restore session to terminate for snapshot "+SnapID, http.StatusBadRequest)
+			return
+		}
+	}
+	resourceLink := fmt.Sprintf("/replication/symmetrix/%s/snapshot/%s", DefaultSymmetrixID, SnapID)
+	jobID := fmt.Sprintf("SnapID-%d", time.Now().Nanosecond())
+	if InducedErrors.JobFailedError {
+		NewMockJob(jobID, types.JobStatusRunning, types.JobStatusFailed, resourceLink)
+	} else {
+		for _, volID := range deviceNameListSource {
+			snapshot := Data.VolIDToSnapshots[volID.Name][SnapID]
+			snapshot.State = "Established"
+			snapshot.Restored = false
+		}
+		NewMockJob(jobID, types.JobStatusRunning, types.JobStatusSucceeded, resourceLink)
+	}
+	returnJobByID(w, jobID)
+}
+
+//check if all the devices exist in the Mock VolumeIDToVolume or check if any unvailable devices
+func fewVolumeUnavalaible(sourceVolumeList []types.VolumeList) bool {
+	for _, volID := range sourceVolumeList {
+		if Data.VolumeIDToVolume[volID.Name] == nil {
+			return true
+		}
 	}
 	return false
 }
@@ -2458,10 +3164,13 @@ func returnSnapshotObjectList(volID string) ([]types.VolumeSnapshotSource, []int
 			Generation:    snap.Generation,
 			TimeStamp:     snap.Timestamp,
 			State:         snap.State,
+			IsRestored:    snap.Restored,
 			LinkedVolumes: returnLinkedVolumes(snap.Name + ":" + volID),
+			Expired:       snapshotExpired(volID, snap),
 		}
-		if InducedErrors.SnapshotExpired {
-			snapshotSrc.Expired = true
+		if retention := Data.SnapIDToRetention[snap.Name+":"+volID]; retention != nil {
+			snapshotSrc.Secured = retention.Secured
+			snapshotSrc.TTL = retention.TimeToLive
 		}
 		volumeSnapshotSrc = append(volumeSnapshotSrc, snapshotSrc)
 		generations = append(generations, snap.Generation)
@@ -2630,9 +3339,42 @@ func returnTimeFinderInfo(volID string) *types.TimeFinderInfo {
 	if timeFinder.SnapVXSrc || timeFinder.SnapVXTgt {
 		timeFinder.SnapVXSession = append(timeFinder.SnapVXSession, returnSnapVXSession(volID, timeFinder.SnapVXSrc, timeFinder.SnapVXTgt))
 	}
+	for _, session := range Data.VolumeIDToCloneSessions[volID] {
+		timeFinder.CloneSrc = timeFinder.CloneSrc || session.SourceVolume == volID
+		timeFinder.CloneTarget = timeFinder.CloneTarget || session.TargetVolume == volID
+		timeFinder.CloneSession = append(timeFinder.CloneSession, session)
+	}
+	for _, session := range Data.VolumeIDToMirrorSessions[volID] {
+		timeFinder.Mirror = true
+		timeFinder.MirrorSession = append(timeFinder.MirrorSession, session)
+	}
 	return timeFinder
 }
 
+// AddCloneSession adds a clone session from the source volume to the target volume to the mock data cache
+func AddCloneSession(sourceVolID, targetVolID string) {
+	session := types.CloneSession{
+		SourceVolume: sourceVolID,
+		TargetVolume: targetVolID,
+		Timestamp:    time.Now().Unix(),
+		State:        "Copied",
+	}
+	Data.VolumeIDToCloneSessions[sourceVolID] = append(Data.VolumeIDToCloneSessions[sourceVolID], session)
+	Data.VolumeIDToCloneSessions[targetVolID] = append(Data.VolumeIDToCloneSessions[targetVolID], session)
+}
+
+// AddMirrorSession adds a mirror session from the source volume to the target volume to the mock data cache
+func AddMirrorSession(sourceVolID, targetVolID string) {
+	session := types.MirrorSession{
+		SourceVolume: sourceVolID,
+		TargetVolume: targetVolID,
+		Timestamp:    time.Now().Unix(),
+		State:        "Synchronized",
+	}
+	Data.VolumeIDToMirrorSessions[sourceVolID] = append(Data.VolumeIDToMirrorSessions[sourceVolID], session)
+	Data.VolumeIDToMirrorSessions[targetVolID] = append(Data.VolumeIDToMirrorSessions[targetVolID], session)
+}
+
 func returnSnapVXSession(volID string, isSource, isTarget bool) types.SnapVXSession {
 	var snapVXSession types.SnapVXSession
 	if isSource {
@@ -2660,15 +3402,1209 @@ func returnSrcSnapshotGenInfo(volID string) []types.SourceSnapshotGenInfo {
 
 	for _, snapIDtoSnap := range Data.VolIDToSnapshots[volID] {
 		timestamp, _ := strconv.ParseInt(snapIDtoSnap.Timestamp, 10, 64)
+		var linkSnapGenInfo []types.LinkSnapshotGenInfo
+		for _, linkedVolume := range Data.SnapIDToLinkedVol[snapIDtoSnap.Name+":"+volID] {
+			pollLinkCopy(linkedVolume)
+			linkSnapGenInfo = append(linkSnapGenInfo, types.LinkSnapshotGenInfo{
+				TargetDevice: linkedVolume.TargetDevice,
+				State:        linkedVolume.State,
+				Restored:     linkedVolume.Restored,
+				Defined:      linkedVolume.Defined,
+			})
+		}
+		header := types.SnapshotHeader{
+			Device:       volID,
+			SnapshotName: snapIDtoSnap.Name,
+			Generation:   snapIDtoSnap.Generation,
+			Timestamp:    timestamp,
+			Expired:      snapshotExpired(volID, snapIDtoSnap),
+		}
+		if retention := Data.SnapIDToRetention[snapIDtoSnap.Name+":"+volID]; retention != nil {
+			header.Secured = retention.Secured
+			header.TimeToLive = retention.TimeToLive
+		}
 		srcSnapGenInfo = append(srcSnapGenInfo, types.SourceSnapshotGenInfo{
-			SnapshotHeader: types.SnapshotHeader{
-				Device:       volID,
-				SnapshotName: snapIDtoSnap.Name,
-				Generation:   snapIDtoSnap.Generation,
-				Timestamp:    timestamp,
-			},
+			SnapshotHeader:      header,
+			LinkSnapshotGenInfo: linkSnapGenInfo,
 		})
 	}
 
 	return srcSnapGenInfo
 }
+
+// AddRDFGroup adds an RDF group in the given SRDF mode, with two online
+// RF director ports on each side, to the mock data cache
+func AddRDFGroup(rdfGroupNumber int, label string, remoteSymID string, remoteRDFGroupNumber int, mode string) (*types.RDFGroup, error) {
+	rdfgID := strconv.Itoa(rdfGroupNumber)
+	if _, ok := Data.RDFGroupIDToRDFGroup[rdfgID]; ok {
+		return nil, errors.New("Error! RDF group already exists")
+	}
+	ports := []string{"RF-1F:0", "RF-2F:0"}
+	rdfGroup := &types.RDFGroup{
+		RDFGroupNumber:       rdfGroupNumber,
+		Label:                label,
+		RemoteRDFGroupNumber: remoteRDFGroupNumber,
+		RemoteSymmetrix:      remoteSymID,
+		LocalPorts:           ports,
+		RemotePorts:          ports,
+		LocalOnlinePorts:     ports,
+		RemoteOnlinePorts:    ports,
+		Modes:                []string{mode},
+		Type:                 "Dynamic",
+		Metro:                mode == types.RDFModeActive,
+		Async:                mode == types.RDFModeAsynchronous,
+	}
+	if rdfGroup.Metro {
+		// the bias of a new SRDF/Metro group is on the R1 side
+		rdfGroup.BiasConfigured = true
+		rdfGroup.BiasEffective = true
+	}
+	Data.RDFGroupIDToRDFGroup[rdfgID] = rdfGroup
+	Data.RDFGroupIDToDevicePairs[rdfgID] = make(map[string]*types.RDFDevicePair)
+	return rdfGroup, nil
+}
+
+// AddRDFDevicePair adds an R1 volume and its remote R2 mirror to an RDF group in the mock data cache
+func AddRDFDevicePair(rdfGroupNumber int, localVolumeID string, remoteVolumeID string, pairState string) (*types.RDFDevicePair, error) {
+	rdfgID := strconv.Itoa(rdfGroupNumber)
+	rdfGroup, ok := Data.RDFGroupIDToRDFGroup[rdfgID]
+	if !ok {
+		return nil, errors.New("Error! RDF group doesn't exist")
+	}
+	devicePair := &types.RDFDevicePair{
+		LocalSymmetrixID:     DefaultSymmetrixID,
+		RemoteSymmetrixID:    rdfGroup.RemoteSymmetrix,
+		LocalRDFGroupNumber:  rdfGroupNumber,
+		RemoteRDFGroupNumber: rdfGroup.RemoteRDFGroupNumber,
+		LocalVolumeName:      localVolumeID,
+		RemoteVolumeName:     remoteVolumeID,
+		LocalVolumeState:     "Ready",
+		RemoteVolumeState:    "Write Disabled",
+		VolumeConfig:         "RDF1+TDEV",
+		RDFMode:              rdfGroup.Modes[0],
+		RDFPairState:         pairState,
+		LargerRDFSide:        "Equal",
+	}
+	Data.RDFGroupIDToDevicePairs[rdfgID][localVolumeID] = devicePair
+	rdfGroup.NumDevices = len(Data.RDFGroupIDToDevicePairs[rdfgID])
+	if vol, ok := Data.VolumeIDToVolume[localVolumeID]; ok {
+		vol.RDFGroupIDList = append(vol.RDFGroupIDList, types.RDFGroupID{
+			RDFGroupNumber: rdfGroupNumber,
+			Label:          rdfGroup.Label,
+		})
+		Data.RemoteVolumeIDToSize[remoteVolumeID] = vol.CapacityGB
+	}
+	return devicePair, nil
+}
+
+// /univmax/restapi/APIVersion/replication/symmetrix/{symid}/rdf_group
+// /univmax/restapi/APIVersion/replication/symmetrix/{symid}/rdf_group/{rdfgNo}
+func handleRDFGroup(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	rdfgID := vars["rdfgNo"]
+	switch r.Method {
+	case http.MethodGet:
+		if InducedErrors.GetRDFGroupError {
+			writeError(w, "Error retrieving RDF group: induced error", http.StatusRequestTimeout)
+			return
+		}
+		if rdfgID != "" {
+			rdfGroup, ok := Data.RDFGroupIDToRDFGroup[rdfgID]
+			if !ok {
+				writeError(w, "RDF group cannot be found", http.StatusNotFound)
+				return
+			}
+			writeJSON(w, rdfGroup)
+			return
+		}
+		rdfGroupList := &types.RDFGroupList{
+			RDFGroupIDs: make([]types.RDFGroupIDL, 0),
+		}
+		for _, rdfGroup := range Data.RDFGroupIDToRDFGroup {
+			rdfGroupList.RDFGroupIDs = append(rdfGroupList.RDFGroupIDs, types.RDFGroupIDL{
+				RDFGroupNumber: rdfGroup.RDFGroupNumber,
+				Label:          rdfGroup.Label,
+			})
+		}
+		sort.Slice(rdfGroupList.RDFGroupIDs, func(i, j int) bool {
+			return rdfGroupList.RDFGroupIDs[i].RDFGroupNumber < rdfGroupList.RDFGroupIDs[j].RDFGroupNumber
+		})
+		rdfGroupList.RDFGroupCount = len(rdfGroupList.RDFGroupIDs)
+		writeJSON(w, rdfGroupList)
+
+	default:
+		writeError(w, "Invalid Method", http.StatusBadRequest)
+	}
+}
+
+// /univmax/restapi/APIVersion/replication/symmetrix/{symid}/rdf_group/{rdfgNo}/volume
+// /univmax/restapi/APIVersion/replication/symmetrix/{symid}/rdf_group/{rdfgNo}/volume/{volID}
+func handleRDFGroupVolume(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	rdfgID := vars["rdfgNo"]
+	volID := vars["volID"]
+	switch r.Method {
+	case http.MethodGet:
+		if InducedErrors.GetRDFGroupError {
+			writeError(w, "Error retrieving RDF group volumes: induced error", http.StatusRequestTimeout)
+			return
+		}
+		devicePairs, ok := Data.RDFGroupIDToDevicePairs[rdfgID]
+		if !ok {
+			writeError(w, "RDF group cannot be found", http.StatusNotFound)
+			return
+		}
+		if volID != "" {
+			devicePair, ok := devicePairs[volID]
+			if !ok {
+				writeError(w, "Volume cannot be found in the RDF group", http.StatusNotFound)
+				return
+			}
+			writeJSON(w, devicePair)
+			return
+		}
+		volumeList := &types.RDFGroupVolumeList{
+			VolumeIDs: make([]string, 0),
+		}
+		for id := range devicePairs {
+			volumeList.VolumeIDs = append(volumeList.VolumeIDs, id)
+		}
+		sort.Strings(volumeList.VolumeIDs)
+		writeJSON(w, volumeList)
+
+	default:
+		writeError(w, "Invalid Method", http.StatusBadRequest)
+	}
+}
+
+// rdfEstablishedState returns the state of the established SRDF pairs of an RDF group
+func rdfEstablishedState(rdfGroup *types.RDFGroup) string {
+	switch rdfGroup.Modes[0] {
+	case types.RDFModeAsynchronous:
+		return types.RDFPairStateConsistent
+	case types.RDFModeActive:
+		if rdfGroup.WitnessEffective {
+			return types.RDFPairStateActiveActive
+		}
+		return types.RDFPairStateActiveBias
+	}
+	return types.RDFPairStateSynchronized
+}
+
+// SetRDFGroupWitness sets the witness configuration of an SRDF/Metro RDF group in the mock data cache.
+// The established pairs of the group become ActiveActive when the witness is in effect and ActiveBias otherwise.
+func SetRDFGroupWitness(rdfGroupNumber int, configured bool, effective bool, witnessType string) error {
+	rdfgID := strconv.Itoa(rdfGroupNumber)
+	rdfGroup, ok := Data.RDFGroupIDToRDFGroup[rdfgID]
+	if !ok {
+		return errors.New("RDF group cannot be found")
+	}
+	if !rdfGroup.Metro {
+		return errors.New("RDF group is not an SRDF/Metro group")
+	}
+	establishedState := rdfEstablishedState(rdfGroup)
+	rdfGroup.Witness = configured
+	rdfGroup.WitnessConfigured = configured
+	rdfGroup.WitnessEffective = configured && effective
+	rdfGroup.WitnessProtectedPhysical = rdfGroup.WitnessEffective && witnessType == types.MetroWitnessPhysical
+	rdfGroup.WitnessProtectedVirtual = rdfGroup.WitnessEffective && witnessType == types.MetroWitnessVirtual
+	for _, devicePair := range Data.RDFGroupIDToDevicePairs[rdfgID] {
+		if devicePair.RDFPairState == establishedState {
+			devicePair.RDFPairState = rdfEstablishedState(rdfGroup)
+		}
+	}
+	return nil
+}
+
+// newRemoteVolumeID returns the id of a new volume on the remote array
+func newRemoteVolumeID() string {
+	Data.RemoteVolumeCounter++
+	return fmt.Sprintf("%05X", 0x1000+Data.RemoteVolumeCounter)
+}
+
+// getSGDevicePairs returns the SRDF pairs of the volumes of a storage group in an RDF group
+func getSGDevicePairs(sgID string, rdfgID string) []*types.RDFDevicePair {
+	devicePairs := make([]*types.RDFDevicePair, 0)
+	for _, volID := range Data.StorageGroupIDToVolumes[sgID] {
+		if devicePair, ok := Data.RDFGroupIDToDevicePairs[rdfgID][volID]; ok {
+			devicePairs = append(devicePairs, devicePair)
+		}
+	}
+	return devicePairs
+}
+
+// appendDistinct appends value to values if it is not already present
+func appendDistinct(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func newSGRDFInfo(sgID string, rdfgID string) *types.SGRDFInfo {
+	rdfGroupNumber, _ := strconv.Atoi(rdfgID)
+	sgRDFInfo := &types.SGRDFInfo{
+		SymmetrixID:      DefaultSymmetrixID,
+		StorageGroupName: sgID,
+		RDFGroupNumber:   rdfGroupNumber,
+		VolumeRDFTypes:   make([]string, 0),
+		States:           make([]string, 0),
+		Modes:            make([]string, 0),
+		LargerRDFSides:   make([]string, 0),
+	}
+	for _, devicePair := range getSGDevicePairs(sgID, rdfgID) {
+		rdfType := "R1"
+		if strings.HasPrefix(devicePair.VolumeConfig, "RDF2") {
+			rdfType = "R2"
+		}
+		sgRDFInfo.VolumeRDFTypes = appendDistinct(sgRDFInfo.VolumeRDFTypes, rdfType)
+		sgRDFInfo.States = appendDistinct(sgRDFInfo.States, devicePair.RDFPairState)
+		sgRDFInfo.Modes = appendDistinct(sgRDFInfo.Modes, devicePair.RDFMode)
+		sgRDFInfo.LargerRDFSides = appendDistinct(sgRDFInfo.LargerRDFSides, devicePair.LargerRDFSide)
+	}
+	return sgRDFInfo
+}
+
+// AddSGReplica protects all the volumes of a storage group with SRDF in the mock data cache.
+// The pairs are established when establish is set, and suspended otherwise.
+func AddSGReplica(sgID string, rdfGroupNumber int, remoteSGID string, establish bool) error {
+	rdfgID := strconv.Itoa(rdfGroupNumber)
+	rdfGroup, ok := Data.RDFGroupIDToRDFGroup[rdfgID]
+	if !ok {
+		return errors.New("RDF group cannot be found")
+	}
+	if _, ok := Data.StorageGroupIDToStorageGroup[sgID]; !ok {
+		return errors.New("Storage Group cannot be found")
+	}
+	if _, ok := Data.StorageGroupIDToRDFGroups[sgID][rdfgID]; ok {
+		return errors.New("Storage Group is already protected in the RDF group")
+	}
+	pairState := types.RDFPairStateSuspended
+	if establish {
+		pairState = rdfEstablishedState(rdfGroup)
+	}
+	for _, volID := range Data.StorageGroupIDToVolumes[sgID] {
+		if _, err := AddRDFDevicePair(rdfGroupNumber, volID, newRemoteVolumeID(), pairState); err != nil {
+			return err
+		}
+	}
+	if Data.StorageGroupIDToRDFGroups[sgID] == nil {
+		Data.StorageGroupIDToRDFGroups[sgID] = make(map[string]string)
+	}
+	Data.StorageGroupIDToRDFGroups[sgID][rdfgID] = remoteSGID
+	return nil
+}
+
+// rdfActionSourceStates are the pair states in which an SRDF action is allowed.
+// An empty state stands for the established state of the RDF group.
+var rdfActionSourceStates = map[string][]string{
+	types.RDFActionEstablish: {types.RDFPairStateSplit, types.RDFPairStateSuspended},
+	types.RDFActionSplit:     {""},
+	types.RDFActionSuspend:   {""},
+	types.RDFActionResume:    {types.RDFPairStateSuspended},
+	types.RDFActionFailover:  {"", types.RDFPairStateSuspended, types.RDFPairStatePartitioned},
+	types.RDFActionFailback:  {types.RDFPairStateFailedOver},
+	types.RDFActionSwap:      {types.RDFPairStateSplit, types.RDFPairStateSuspended, types.RDFPairStateFailedOver},
+	types.RDFActionSetBias:   {"", types.RDFPairStateSuspended},
+}
+
+func modifySGReplica(w http.ResponseWriter, sgID string, rdfgID string, modifyParam *types.ModifySGRDFGroup) {
+	rdfGroup := Data.RDFGroupIDToRDFGroup[rdfgID]
+	establishedState := rdfEstablishedState(rdfGroup)
+	sourceStates, ok := rdfActionSourceStates[modifyParam.Action]
+	if !ok {
+		writeError(w, "Invalid SRDF action: "+modifyParam.Action, http.StatusBadRequest)
+		return
+	}
+	if modifyParam.Action == types.RDFActionSetBias {
+		if !rdfGroup.Metro {
+			writeError(w, "Bias can only be set on SRDF/Metro pairs", http.StatusBadRequest)
+			return
+		}
+		if rdfGroup.WitnessEffective {
+			writeError(w, "Bias cannot be changed when the witness is in effect", http.StatusBadRequest)
+			return
+		}
+		if modifyParam.SetBias == nil {
+			writeError(w, "The bias side is required", http.StatusBadRequest)
+			return
+		}
+	}
+	devicePairs := getSGDevicePairs(sgID, rdfgID)
+	for _, devicePair := range devicePairs {
+		valid := false
+		for _, state := range sourceStates {
+			if state == devicePair.RDFPairState || (state == "" && devicePair.RDFPairState == establishedState) {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			writeError(w, fmt.Sprintf("SRDF action %s is not valid in state %s", modifyParam.Action, devicePair.RDFPairState), http.StatusBadRequest)
+			return
+		}
+	}
+	for _, devicePair := range devicePairs {
+		switch modifyParam.Action {
+		case types.RDFActionEstablish, types.RDFActionResume, types.RDFActionFailback:
+			devicePair.RDFPairState = establishedState
+		case types.RDFActionSplit:
+			devicePair.RDFPairState = types.RDFPairStateSplit
+		case types.RDFActionSuspend:
+			devicePair.RDFPairState = types.RDFPairStateSuspended
+		case types.RDFActionFailover:
+			devicePair.RDFPairState = types.RDFPairStateFailedOver
+		case types.RDFActionSwap:
+			if devicePair.RDFPairState == types.RDFPairStateFailedOver {
+				devicePair.RDFPairState = types.RDFPairStateSuspended
+			}
+			if strings.HasPrefix(devicePair.VolumeConfig, "RDF1") {
+				devicePair.VolumeConfig = strings.Replace(devicePair.VolumeConfig, "RDF1", "RDF2", 1)
+			} else {
+				devicePair.VolumeConfig = strings.Replace(devicePair.VolumeConfig, "RDF2", "RDF1", 1)
+			}
+		case types.RDFActionSetBias:
+			// the bias follows R1, moving it to R2 swaps the personalities of the pair
+			if modifyParam.SetBias.Bias == types.MetroBiasR2 {
+				if strings.HasPrefix(devicePair.VolumeConfig, "RDF1") {
+					devicePair.VolumeConfig = strings.Replace(devicePair.VolumeConfig, "RDF1", "RDF2", 1)
+				} else {
+					devicePair.VolumeConfig = strings.Replace(devicePair.VolumeConfig, "RDF2", "RDF1", 1)
+				}
+			}
+		}
+	}
+	resourceLink := fmt.Sprintf("/replication/symmetrix/%s/storagegroup/%s/rdf_group/%s", DefaultSymmetrixID, sgID, rdfgID)
+	returnReplicationJob(w, resourceLink)
+}
+
+func deleteSGReplica(w http.ResponseWriter, sgID string, rdfgID string, force bool) {
+	establishedState := rdfEstablishedState(Data.RDFGroupIDToRDFGroup[rdfgID])
+	devicePairs := getSGDevicePairs(sgID, rdfgID)
+	for _, devicePair := range devicePairs {
+		if !force && devicePair.RDFPairState == establishedState {
+			writeError(w, "SRDF pairs must be split or suspended before they are deleted", http.StatusBadRequest)
+			return
+		}
+	}
+	rdfGroupNumber, _ := strconv.Atoi(rdfgID)
+	for _, devicePair := range devicePairs {
+		delete(Data.RDFGroupIDToDevicePairs[rdfgID], devicePair.LocalVolumeName)
+		if vol, ok := Data.VolumeIDToVolume[devicePair.LocalVolumeName]; ok {
+			rdfGroupIDs := make([]types.RDFGroupID, 0)
+			for _, id := range vol.RDFGroupIDList {
+				if id.RDFGroupNumber != rdfGroupNumber {
+					rdfGroupIDs = append(rdfGroupIDs, id)
+				}
+			}
+			vol.RDFGroupIDList = rdfGroupIDs
+		}
+	}
+	Data.RDFGroupIDToRDFGroup[rdfgID].NumDevices = len(Data.RDFGroupIDToDevicePairs[rdfgID])
+	delete(Data.StorageGroupIDToRDFGroups[sgID], rdfgID)
+	resourceLink := fmt.Sprintf("/replication/symmetrix/%s/storagegroup/%s/rdf_group", DefaultSymmetrixID, sgID)
+	returnReplicationJob(w, resourceLink)
+}
+
+// returnReplicationJob returns a job for an SRDF or storage group snapshot operation, which fails if JobFailedError is induced
+func returnReplicationJob(w http.ResponseWriter, resourceLink string) {
+	jobID := fmt.Sprintf("Replication-%d", time.Now().Nanosecond())
+	if InducedErrors.JobFailedError {
+		NewMockJob(jobID, types.JobStatusRunning, types.JobStatusFailed, resourceLink)
+	} else {
+		NewMockJob(jobID, types.JobStatusRunning, types.JobStatusSucceeded, resourceLink)
+	}
+	returnJobByID(w, jobID)
+}
+
+// /univmax/restapi/APIVersion/replication/symmetrix/{symid}/storagegroup/{id}/rdf_group
+// /univmax/restapi/APIVersion/replication/symmetrix/{symid}/storagegroup/{id}/rdf_group/{rdfgNo}
+func handleSGRDFGroup(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sgID := vars["id"]
+	rdfgID := vars["rdfgNo"]
+	if _, ok := Data.StorageGroupIDToStorageGroup[sgID]; !ok {
+		writeError(w, "Storage Group cannot be found", http.StatusNotFound)
+		return
+	}
+	if rdfgID != "" {
+		if _, ok := Data.StorageGroupIDToRDFGroups[sgID][rdfgID]; !ok {
+			writeError(w, "Storage Group is not protected in the RDF group", http.StatusNotFound)
+			return
+		}
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if InducedErrors.GetRDFGroupError {
+			writeError(w, "Error retrieving Storage Group SRDF information: induced error", http.StatusRequestTimeout)
+			return
+		}
+		if rdfgID != "" {
+			writeJSON(w, newSGRDFInfo(sgID, rdfgID))
+			return
+		}
+		sgRDFGroupList := &types.SGRDFGroupList{
+			RDFGroupNumbers: make([]int, 0),
+		}
+		for id := range Data.StorageGroupIDToRDFGroups[sgID] {
+			rdfGroupNumber, _ := strconv.Atoi(id)
+			sgRDFGroupList.RDFGroupNumbers = append(sgRDFGroupList.RDFGroupNumbers, rdfGroupNumber)
+		}
+		sort.Ints(sgRDFGroupList.RDFGroupNumbers)
+		writeJSON(w, sgRDFGroupList)
+
+	case http.MethodPost:
+		if InducedErrors.CreateSGReplicaError {
+			writeError(w, "Failed to protect Storage Group with SRDF: induced error", http.StatusBadRequest)
+			return
+		}
+		decoder := json.NewDecoder(r.Body)
+		createParam := &types.CreateSGSRDF{}
+		err := decoder.Decode(createParam)
+		if err != nil {
+			writeError(w, "problem decoding POST SRDF payload: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		rdfGroup, ok := Data.RDFGroupIDToRDFGroup[strconv.Itoa(createParam.RDFGroupNumber)]
+		if !ok {
+			writeError(w, "RDF group cannot be found", http.StatusNotFound)
+			return
+		}
+		if rdfGroup.RemoteSymmetrix != createParam.RemoteSymmID {
+			writeError(w, "RDF group is not connected to the remote Symmetrix "+createParam.RemoteSymmID, http.StatusBadRequest)
+			return
+		}
+		if rdfGroup.Modes[0] != createParam.ReplicationMode {
+			writeError(w, "RDF group does not support the replication mode "+createParam.ReplicationMode, http.StatusBadRequest)
+			return
+		}
+		err = AddSGReplica(sgID, createParam.RDFGroupNumber, createParam.RemoteStorageGroupName, createParam.Establish)
+		if err != nil {
+			writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resourceLink := fmt.Sprintf("/replication/symmetrix/%s/storagegroup/%s/rdf_group/%d", DefaultSymmetrixID, sgID, createParam.RDFGroupNumber)
+		returnReplicationJob(w, resourceLink)
+
+	case http.MethodPut:
+		if InducedErrors.ModifySGReplicaError {
+			writeError(w, "Failed to modify SRDF pairs: induced error", http.StatusBadRequest)
+			return
+		}
+		decoder := json.NewDecoder(r.Body)
+		modifyParam := &types.ModifySGRDFGroup{}
+		err := decoder.Decode(modifyParam)
+		if err != nil {
+			writeError(w, "problem decoding PUT SRDF payload: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		modifySGReplica(w, sgID, rdfgID, modifyParam)
+
+	case http.MethodDelete:
+		if InducedErrors.DeleteSGReplicaError {
+			writeError(w, "Failed to delete SRDF pairs: induced error", http.StatusBadRequest)
+			return
+		}
+		decoder := json.NewDecoder(r.Body)
+		deleteParam := &types.DeleteSGRDFPair{}
+		err := decoder.Decode(deleteParam)
+		if err != nil {
+			writeError(w, "problem decoding DELETE SRDF payload: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		deleteSGReplica(w, sgID, rdfgID, deleteParam.Force)
+
+	default:
+		writeError(w, "Invalid Method", http.StatusBadRequest)
+	}
+}
+
+// AddStorageGroupSnapshot adds a new generation of a snapshot of all the volumes of a storage group to the mock data cache.
+// The new generation is generation 0 and the generation numbers of the older ones are incremented.
+func AddStorageGroupSnapshot(sgID string, snapID string) (*types.StorageGroupSnapshot, error) {
+	if _, ok := Data.StorageGroupIDToStorageGroup[sgID]; !ok {
+		return nil, errors.New("Storage Group cannot be found")
+	}
+	volumeIDs := Data.StorageGroupIDToVolumes[sgID]
+	if len(volumeIDs) == 0 {
+		return nil, errors.New("Storage Group has no volumes")
+	}
+	now := time.Now()
+	snapshot := &types.StorageGroupSnapshot{
+		Name:             snapID,
+		Timestamp:        now.Format("Mon Jan 02 15:04:05 2006"),
+		TimestampUTC:     now.UTC().Format("Mon Jan 02 15:04:05 2006"),
+		State:            []string{"Established"},
+		NumSourceVolumes: len(volumeIDs),
+		SourceVolumes:    make([]types.SnapshotSourceVolume, 0),
+	}
+	for _, volID := range volumeIDs {
+		capacity := 0.0
+		if vol, ok := Data.VolumeIDToVolume[volID]; ok {
+			capacity = vol.CapacityGB
+			vol.SnapSource = true
+		}
+		snapshot.SourceVolumes = append(snapshot.SourceVolumes, types.SnapshotSourceVolume{Name: volID, Capacity: capacity})
+	}
+	if Data.StorageGroupIDToSnapshots[sgID] == nil {
+		Data.StorageGroupIDToSnapshots[sgID] = make(map[string][]*types.StorageGroupSnapshot)
+	}
+	generations := append([]*types.StorageGroupSnapshot{snapshot}, Data.StorageGroupIDToSnapshots[sgID][snapID]...)
+	Data.StorageGroupIDToSnapshots[sgID][snapID] = generations
+	renumberSGSnapshotGenerations(generations)
+	return snapshot, nil
+}
+
+// renumberSGSnapshotGenerations sets the generation numbers of a snapshot from their positions, newest first
+func renumberSGSnapshotGenerations(generations []*types.StorageGroupSnapshot) {
+	for i, generation := range generations {
+		generation.Generation = int64(i)
+	}
+}
+
+// removeSGSnapshotGeneration removes a generation of a storage group snapshot from the mock data cache
+func removeSGSnapshotGeneration(sgID string, snapID string, generation int64) {
+	generations := Data.StorageGroupIDToSnapshots[sgID][snapID]
+	generations = append(generations[:generation], generations[generation+1:]...)
+	if len(generations) == 0 {
+		delete(Data.StorageGroupIDToSnapshots[sgID], snapID)
+		return
+	}
+	renumberSGSnapshotGenerations(generations)
+	Data.StorageGroupIDToSnapshots[sgID][snapID] = generations
+}
+
+// /univmax/restapi/APIVersion/replication/symmetrix/{symid}/storagegroup/{id}/snapshot
+func handleSGSnapshot(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sgID := vars["id"]
+	if _, ok := Data.StorageGroupIDToStorageGroup[sgID]; !ok {
+		writeError(w, "Storage Group cannot be found", http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if InducedErrors.GetSGSnapshotError {
+			writeError(w, "Error retrieving Storage Group snapshots: induced error", http.StatusRequestTimeout)
+			return
+		}
+		snapshotList := &types.StorageGroupSnapshotList{Name: make([]string, 0)}
+		for snapID := range Data.StorageGroupIDToSnapshots[sgID] {
+			snapshotList.Name = append(snapshotList.Name, snapID)
+		}
+		sort.Strings(snapshotList.Name)
+		writeJSON(w, snapshotList)
+	case http.MethodPost:
+		if InducedErrors.CreateSGSnapshotError {
+			writeError(w, "Failed to create Storage Group snapshot: induced error", http.StatusBadRequest)
+			return
+		}
+		decoder := json.NewDecoder(r.Body)
+		createParam := &types.CreateStorageGroupSnapshot{}
+		if err := decoder.Decode(createParam); err != nil {
+			writeError(w, "problem decoding POST Storage Group snapshot payload: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if createParam.ExecutionOption != types.ExecutionOptionAsynchronous {
+			writeError(w, "expected ASYNCHRONOUS", http.StatusBadRequest)
+			return
+		}
+		if createParam.SnapshotName == "" || strings.Contains(createParam.SnapshotName, ":") {
+			writeError(w, "error, invalid snapshot name", http.StatusBadRequest)
+			return
+		}
+		snapshot, err := AddStorageGroupSnapshot(sgID, createParam.SnapshotName)
+		if err != nil {
+			writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		snapshot.TimeToLive = snapshotTTLInHours(createParam.TimeToLive, createParam.TimeInHours)
+		if createParam.Securettl > 0 {
+			snapshot.Secured = true
+			snapshot.TimeToLive = snapshotTTLInHours(createParam.Securettl, createParam.TimeInHours)
+		}
+		resourceLink := fmt.Sprintf("/replication/symmetrix/%s/storagegroup/%s/snapshot/%s/generation/0", DefaultSymmetrixID, sgID, createParam.SnapshotName)
+		returnReplicationJob(w, resourceLink)
+	default:
+		writeError(w, "Invalid Method", http.StatusBadRequest)
+	}
+}
+
+// /univmax/restapi/APIVersion/replication/symmetrix/{symid}/storagegroup/{id}/snapshot/{SnapID}/generation
+// /univmax/restapi/APIVersion/replication/symmetrix/{symid}/storagegroup/{id}/snapshot/{SnapID}/generation/{genID}
+func handleSGSnapshotGeneration(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sgID := vars["id"]
+	snapID := vars["SnapID"]
+	if _, ok := Data.StorageGroupIDToStorageGroup[sgID]; !ok {
+		writeError(w, "Storage Group cannot be found", http.StatusNotFound)
+		return
+	}
+	generations, ok := Data.StorageGroupIDToSnapshots[sgID][snapID]
+	if !ok {
+		writeError(w, "Snapshot cannot be found", http.StatusNotFound)
+		return
+	}
+	if vars["genID"] == "" {
+		if r.Method != http.MethodGet {
+			writeError(w, "Invalid Method", http.StatusBadRequest)
+			return
+		}
+		if InducedErrors.GetSGSnapshotError {
+			writeError(w, "Error retrieving Storage Group snapshot generations: induced error", http.StatusRequestTimeout)
+			return
+		}
+		generationList := &types.StorageGroupSnapshotGenerationList{Generations: make([]int64, 0)}
+		for _, generation := range generations {
+			generationList.Generations = append(generationList.Generations, generation.Generation)
+		}
+		writeJSON(w, generationList)
+		return
+	}
+	genID, err := strconv.ParseInt(vars["genID"], 10, 64)
+	if err != nil || genID < 0 || genID >= int64(len(generations)) {
+		writeError(w, "Snapshot generation cannot be found", http.StatusNotFound)
+		return
+	}
+	snapshot := generations[genID]
+	resourceLink := fmt.Sprintf("/replication/symmetrix/%s/storagegroup/%s/snapshot/%s/generation/%d", DefaultSymmetrixID, sgID, snapID, genID)
+	switch r.Method {
+	case http.MethodGet:
+		if InducedErrors.GetSGSnapshotError {
+			writeError(w, "Error retrieving Storage Group snapshot: induced error", http.StatusRequestTimeout)
+			return
+		}
+		writeJSON(w, snapshot)
+	case http.MethodPut:
+		if InducedErrors.ModifySGSnapshotError {
+			writeError(w, "Failed to modify Storage Group snapshot: induced error", http.StatusBadRequest)
+			return
+		}
+		decoder := json.NewDecoder(r.Body)
+		modifyParam := &types.ModifyStorageGroupSnapshot{}
+		if err := decoder.Decode(modifyParam); err != nil {
+			writeError(w, "problem decoding PUT Storage Group snapshot payload: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !modifySGSnapshot(w, sgID, snapID, snapshot, modifyParam) {
+			return
+		}
+		returnReplicationJob(w, resourceLink)
+	case http.MethodDelete:
+		if InducedErrors.DeleteSGSnapshotError {
+			writeError(w, "Failed to delete Storage Group snapshot: induced error", http.StatusBadRequest)
+			return
+		}
+		if snapshot.Linked {
+			writeError(w, "delete cannot be attempted because the snapshot has a link", http.StatusBadRequest)
+			return
+		}
+		removeSGSnapshotGeneration(sgID, snapID, genID)
+		returnReplicationJob(w, resourceLink)
+	default:
+		writeError(w, "Invalid Method", http.StatusBadRequest)
+	}
+}
+
+// modifySGSnapshot performs an action on a storage group snapshot generation,
+// it writes the error and returns false if the action is not valid
+func modifySGSnapshot(w http.ResponseWriter, sgID string, snapID string, snapshot *types.StorageGroupSnapshot, modifyParam *types.ModifyStorageGroupSnapshot) bool {
+	switch {
+	case modifyParam.Action == types.SnapshotActionLink && modifyParam.Link != nil:
+		linkSGID := modifyParam.Link.LinkStorageGroupName
+		if _, ok := Data.StorageGroupIDToStorageGroup[linkSGID]; !ok {
+			writeError(w, "Link Storage Group cannot be found", http.StatusBadRequest)
+			return false
+		}
+		for _, link := range snapshot.LinkedStorageGroups {
+			if link.Name == linkSGID {
+				writeError(w, "devices already in desired state", http.StatusBadRequest)
+				return false
+			}
+		}
+		linkVolumeIDs := Data.StorageGroupIDToVolumes[linkSGID]
+		if len(linkVolumeIDs) != len(snapshot.SourceVolumes) {
+			writeError(w, "cannot link snapshot, the number of source and link devices should be same", http.StatusBadRequest)
+			return false
+		}
+		for i, sourceVolume := range snapshot.SourceVolumes {
+			snapshot.LinkedStorageGroups = append(snapshot.LinkedStorageGroups, types.LinkedStorageGroup{
+				Name:             linkSGID,
+				SourceVolumeName: sourceVolume.Name,
+				LinkedVolumeName: linkVolumeIDs[i],
+				Defined:          true,
+				Linked:           true,
+				Copy:             modifyParam.Link.Copy,
+			})
+			if vol, ok := Data.VolumeIDToVolume[linkVolumeIDs[i]]; ok {
+				vol.SnapTarget = true
+			}
+		}
+		snapshot.Linked = true
+	case modifyParam.Action == types.SnapshotActionUnlink && modifyParam.Unlink != nil:
+		links := make([]types.LinkedStorageGroup, 0)
+		for _, link := range snapshot.LinkedStorageGroups {
+			if link.Name != modifyParam.Unlink.UnlinkStorageGroupName {
+				links = append(links, link)
+			} else if vol, ok := Data.VolumeIDToVolume[link.LinkedVolumeName]; ok {
+				vol.SnapTarget = false
+			}
+		}
+		if len(links) == len(snapshot.LinkedStorageGroups) {
+			writeError(w, "devices already in desired state", http.StatusBadRequest)
+			return false
+		}
+		snapshot.LinkedStorageGroups = links
+		snapshot.Linked = len(links) > 0
+	case modifyParam.Action == types.SnapshotActionRestore:
+		snapshot.Restored = true
+		snapshot.State = []string{"Restored"}
+	case modifyParam.Action == types.SnapshotActionRename && modifyParam.Rename != nil:
+		newSnapID := modifyParam.Rename.NewStorageGroupSnapshotName
+		if newSnapID == "" || strings.Contains(newSnapID, ":") {
+			writeError(w, "error, invalid snapshot name", http.StatusBadRequest)
+			return false
+		}
+		if _, ok := Data.StorageGroupIDToSnapshots[sgID][newSnapID]; ok {
+			writeError(w, "a snapshot with the name "+newSnapID+" already exists", http.StatusBadRequest)
+			return false
+		}
+		removeSGSnapshotGeneration(sgID, snapID, snapshot.Generation)
+		snapshot.Name = newSnapID
+		snapshot.Generation = 0
+		Data.StorageGroupIDToSnapshots[sgID][newSnapID] = []*types.StorageGroupSnapshot{snapshot}
+	default:
+		writeError(w, "Invalid Storage Group snapshot action: "+modifyParam.Action, http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// snapshotPolicyIntervalMinutes holds the intervals supported by the snapshot policies, in minutes
+var snapshotPolicyIntervalMinutes = map[string]int{
+	types.SnapshotPolicyInterval10Minutes: 10,
+	types.SnapshotPolicyInterval12Minutes: 12,
+	types.SnapshotPolicyInterval15Minutes: 15,
+	types.SnapshotPolicyInterval20Minutes: 20,
+	types.SnapshotPolicyInterval30Minutes: 30,
+	types.SnapshotPolicyInterval1Hour:     60,
+	types.SnapshotPolicyInterval2Hours:    2 * 60,
+	types.SnapshotPolicyInterval3Hours:    3 * 60,
+	types.SnapshotPolicyInterval4Hours:    4 * 60,
+	types.SnapshotPolicyInterval6Hours:    6 * 60,
+	types.SnapshotPolicyInterval8Hours:    8 * 60,
+	types.SnapshotPolicyInterval12Hours:   12 * 60,
+	types.SnapshotPolicyInterval1Day:      24 * 60,
+	types.SnapshotPolicyInterval7Days:     7 * 24 * 60,
+}
+
+// AddSnapshotPolicy adds a snapshot policy to the mock data cache
+func AddSnapshotPolicy(snapshotPolicyID, interval string, snapshotCount int, secure bool) (*types.SnapshotPolicy, error) {
+	if _, ok := Data.SnapshotPolicyIDToSnapshotPolicy[snapshotPolicyID]; ok {
+		return nil, errors.New("a snapshot policy with the name " + snapshotPolicyID + " already exists")
+	}
+	intervalMinutes, ok := snapshotPolicyIntervalMinutes[interval]
+	if !ok {
+		return nil, errors.New("not a supported snapshot policy interval: " + interval)
+	}
+	if snapshotCount < 1 || snapshotCount > 1024 {
+		return nil, errors.New("the snapshot count of a snapshot policy must be between 1 and 1024")
+	}
+	snapshotPolicy := &types.SnapshotPolicy{
+		SymmetrixID:        DefaultSymmetrixID,
+		SnapshotPolicyName: snapshotPolicyID,
+		SnapshotCount:      snapshotCount,
+		IntervalMinutes:    intervalMinutes,
+		Secure:             secure,
+		Type:               "local",
+	}
+	Data.SnapshotPolicyIDToSnapshotPolicy[snapshotPolicyID] = snapshotPolicy
+	Data.SnapshotPolicyIDToStorageGroups[snapshotPolicyID] = make([]string, 0)
+	return snapshotPolicy, nil
+}
+
+// /univmax/restapi/92/replication/symmetrix/{symid}/snapshot_policy
+// /univmax/restapi/92/replication/symmetrix/{symid}/snapshot_policy/{id}
+func handleSnapshotPolicy(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	snapshotPolicyID := vars["id"]
+	if r.Method != http.MethodPost && snapshotPolicyID != "" {
+		if _, ok := Data.SnapshotPolicyIDToSnapshotPolicy[snapshotPolicyID]; !ok {
+			writeError(w, "Snapshot Policy cannot be found: "+snapshotPolicyID, http.StatusNotFound)
+			return
+		}
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if InducedErrors.GetSnapshotPolicyError {
+			writeError(w, "Error retrieving Snapshot Policy: induced error", http.StatusRequestTimeout)
+			return
+		}
+		if snapshotPolicyID != "" {
+			writeJSON(w, Data.SnapshotPolicyIDToSnapshotPolicy[snapshotPolicyID])
+			return
+		}
+		snapshotPolicyList := &types.SnapshotPolicyList{SnapshotPolicyIDs: make([]string, 0)}
+		for id := range Data.SnapshotPolicyIDToSnapshotPolicy {
+			snapshotPolicyList.SnapshotPolicyIDs = append(snapshotPolicyList.SnapshotPolicyIDs, id)
+		}
+		sort.Strings(snapshotPolicyList.SnapshotPolicyIDs)
+		writeJSON(w, snapshotPolicyList)
+	case http.MethodPost:
+		if InducedErrors.CreateSnapshotPolicyError {
+			writeError(w, "Error creating Snapshot Policy: induced error", http.StatusBadRequest)
+			return
+		}
+		createParam := &types.CreateSnapshotPolicyParam{}
+		if err := json.NewDecoder(r.Body).Decode(createParam); err != nil {
+			writeError(w, "problem decoding POST Snapshot Policy payload: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		snapshotPolicy, err := AddSnapshotPolicy(createParam.SnapshotPolicyName, createParam.Interval, createParam.SnapshotCount, createParam.Secure)
+		if err != nil {
+			writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		snapshotPolicy.OffsetMinutes = createParam.OffsetMins
+		snapshotPolicy.ComplianceCountWarning = createParam.ComplianceCountWarning
+		snapshotPolicy.ComplianceCountCritical = createParam.ComplianceCountCritical
+		writeJSON(w, snapshotPolicy)
+	case http.MethodPut:
+		if InducedErrors.ModifySnapshotPolicyError {
+			writeError(w, "Error updating Snapshot Policy: induced error", http.StatusBadRequest)
+			return
+		}
+		updateParam := &types.UpdateSnapshotPolicyParam{}
+		if err := json.NewDecoder(r.Body).Decode(updateParam); err != nil {
+			writeError(w, "problem decoding PUT Snapshot Policy payload: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		snapshotPolicy, ok := updateSnapshotPolicy(w, snapshotPolicyID, updateParam)
+		if ok {
+			writeJSON(w, snapshotPolicy)
+		}
+	case http.MethodDelete:
+		if InducedErrors.DeleteSnapshotPolicyError {
+			writeError(w, "Error deleting Snapshot Policy: induced error", http.StatusBadRequest)
+			return
+		}
+		if len(Data.SnapshotPolicyIDToStorageGroups[snapshotPolicyID]) > 0 {
+			writeError(w, "the snapshot policy "+snapshotPolicyID+" is associated with storage groups", http.StatusBadRequest)
+			return
+		}
+		delete(Data.SnapshotPolicyIDToSnapshotPolicy, snapshotPolicyID)
+		delete(Data.SnapshotPolicyIDToStorageGroups, snapshotPolicyID)
+	}
+}
+
+func updateSnapshotPolicy(w http.ResponseWriter, snapshotPolicyID string, updateParam *types.UpdateSnapshotPolicyParam) (*types.SnapshotPolicy, bool) {
+	snapshotPolicy := Data.SnapshotPolicyIDToSnapshotPolicy[snapshotPolicyID]
+	switch {
+	case updateParam.Action == types.SnapshotPolicyActionModify && updateParam.Modify != nil:
+		modify := updateParam.Modify
+		if modify.Interval != "" {
+			intervalMinutes, ok := snapshotPolicyIntervalMinutes[modify.Interval]
+			if !ok {
+				writeError(w, "not a supported snapshot policy interval: "+modify.Interval, http.StatusBadRequest)
+				return nil, false
+			}
+			snapshotPolicy.IntervalMinutes = intervalMinutes
+		}
+		if modify.SnapshotCount > 1024 {
+			writeError(w, "the snapshot count of a snapshot policy must be between 1 and 1024", http.StatusBadRequest)
+			return nil, false
+		}
+		if modify.SnapshotCount > 0 {
+			snapshotPolicy.SnapshotCount = modify.SnapshotCount
+		}
+		if modify.OffsetMins > 0 {
+			snapshotPolicy.OffsetMinutes = modify.OffsetMins
+		}
+		if modify.ComplianceCountWarning > 0 {
+			snapshotPolicy.ComplianceCountWarning = modify.ComplianceCountWarning
+		}
+		if modify.ComplianceCountCritical > 0 {
+			snapshotPolicy.ComplianceCountCritical = modify.ComplianceCountCritical
+		}
+		if newName := modify.SnapshotPolicyName; newName != "" && newName != snapshotPolicyID {
+			if _, ok := Data.SnapshotPolicyIDToSnapshotPolicy[newName]; ok {
+				writeError(w, "a snapshot policy with the name "+newName+" already exists", http.StatusBadRequest)
+				return nil, false
+			}
+			snapshotPolicy.SnapshotPolicyName = newName
+			Data.SnapshotPolicyIDToSnapshotPolicy[newName] = snapshotPolicy
+			Data.SnapshotPolicyIDToStorageGroups[newName] = Data.SnapshotPolicyIDToStorageGroups[snapshotPolicyID]
+			delete(Data.SnapshotPolicyIDToSnapshotPolicy, snapshotPolicyID)
+			delete(Data.SnapshotPolicyIDToStorageGroups, snapshotPolicyID)
+		}
+	case updateParam.Action == types.SnapshotPolicyActionSuspend:
+		if snapshotPolicy.Suspended {
+			writeError(w, "the snapshot policy "+snapshotPolicyID+" is already suspended", http.StatusBadRequest)
+			return nil, false
+		}
+		snapshotPolicy.Suspended = true
+	case updateParam.Action == types.SnapshotPolicyActionResume:
+		if !snapshotPolicy.Suspended {
+			writeError(w, "the snapshot policy "+snapshotPolicyID+" is not suspended", http.StatusBadRequest)
+			return nil, false
+		}
+		snapshotPolicy.Suspended = false
+	case updateParam.Action == types.SnapshotPolicyActionAssociate && updateParam.Associate != nil:
+		storageGroups := Data.SnapshotPolicyIDToStorageGroups[snapshotPolicyID]
+		for _, sgID := range updateParam.Associate.StorageGroupName {
+			if _, ok := Data.StorageGroupIDToStorageGroup[sgID]; !ok {
+				writeError(w, "Storage Group cannot be found: "+sgID, http.StatusNotFound)
+				return nil, false
+			}
+			if stringInSlice(sgID, storageGroups) {
+				writeError(w, "the storage group "+sgID+" is already associated with the snapshot policy", http.StatusBadRequest)
+				return nil, false
+			}
+			storageGroups = append(storageGroups, sgID)
+		}
+		Data.SnapshotPolicyIDToStorageGroups[snapshotPolicyID] = storageGroups
+		snapshotPolicy.StorageGroupCount = len(storageGroups)
+	case updateParam.Action == types.SnapshotPolicyActionDisassociate && updateParam.Disassociate != nil:
+		storageGroups := Data.SnapshotPolicyIDToStorageGroups[snapshotPolicyID]
+		for _, sgID := range updateParam.Disassociate.StorageGroupName {
+			if !stringInSlice(sgID, storageGroups) {
+				writeError(w, "the storage group "+sgID+" is not associated with the snapshot policy", http.StatusBadRequest)
+				return nil, false
+			}
+		}
+		remaining := make([]string, 0)
+		for _, sgID := range storageGroups {
+			if !stringInSlice(sgID, updateParam.Disassociate.StorageGroupName) {
+				remaining = append(remaining, sgID)
+			}
+		}
+		Data.SnapshotPolicyIDToStorageGroups[snapshotPolicyID] = remaining
+		snapshotPolicy.StorageGroupCount = len(remaining)
+	default:
+		writeError(w, "Invalid Snapshot Policy action: "+updateParam.Action, http.StatusBadRequest)
+		return nil, false
+	}
+	return snapshotPolicy, true
+}
+
+// /univmax/restapi/92/replication/symmetrix/{symid}/snapshot_policy/{id}/storagegroup
+func handleSnapshotPolicyStorageGroups(w http.ResponseWriter, r *http.Request) {
+	snapshotPolicyID := mux.Vars(r)["id"]
+	if InducedErrors.GetSnapshotPolicyError {
+		writeError(w, "Error retrieving Snapshot Policy: induced error", http.StatusRequestTimeout)
+		return
+	}
+	storageGroups, ok := Data.SnapshotPolicyIDToStorageGroups[snapshotPolicyID]
+	if !ok {
+		writeError(w, "Snapshot Policy cannot be found: "+snapshotPolicyID, http.StatusNotFound)
+		return
+	}
+	writeJSON(w, &types.SnapshotPolicyStorageGroupList{StorageGroupIDs: storageGroups})
+}
+
+// /univmax/restapi/92/replication/symmetrix/{symid}/storagegroup/{id}/compliance/snapshot
+// The snapshots of a storage group taken by a snapshot policy are named after the policy
+func handleSGSnapshotCompliance(w http.ResponseWriter, r *http.Request) {
+	sgID := mux.Vars(r)["id"]
+	if InducedErrors.GetSnapshotPolicyError {
+		writeError(w, "Error retrieving Storage Group snapshot compliance: induced error", http.StatusRequestTimeout)
+		return
+	}
+	if _, ok := Data.StorageGroupIDToStorageGroup[sgID]; !ok {
+		writeError(w, "Storage Group cannot be found: "+sgID, http.StatusNotFound)
+		return
+	}
+	compliance := &types.StorageGroupSnapshotCompliance{
+		StorageGroupName: sgID,
+		Compliance:       types.SnapshotComplianceNone,
+		PolicyCompliance: make([]types.SnapshotPolicyCompliance, 0),
+	}
+	rank := map[string]int{types.SnapshotComplianceNone: 0, types.SnapshotComplianceGreen: 1, types.SnapshotComplianceYellow: 2, types.SnapshotComplianceRed: 3}
+	policyIDs := make([]string, 0)
+	for snapshotPolicyID, storageGroups := range Data.SnapshotPolicyIDToStorageGroups {
+		if stringInSlice(sgID, storageGroups) {
+			policyIDs = append(policyIDs, snapshotPolicyID)
+		}
+	}
+	sort.Strings(policyIDs)
+	for _, snapshotPolicyID := range policyIDs {
+		snapshotPolicy := Data.SnapshotPolicyIDToSnapshotPolicy[snapshotPolicyID]
+		count := len(Data.StorageGroupIDToSnapshots[sgID][snapshotPolicyID])
+		policyCompliance := types.SnapshotPolicyCompliance{
+			SnapshotPolicyName: snapshotPolicyID,
+			SnapshotCount:      count,
+			Compliance:         types.SnapshotComplianceGreen,
+		}
+		if count <= snapshotPolicy.ComplianceCountCritical {
+			policyCompliance.Compliance = types.SnapshotComplianceRed
+		} else if count <= snapshotPolicy.ComplianceCountWarning {
+			policyCompliance.Compliance = types.SnapshotComplianceYellow
+		}
+		if rank[policyCompliance.Compliance] > rank[compliance.Compliance] {
+			compliance.Compliance = policyCompliance.Compliance
+		}
+		compliance.PolicyCompliance = append(compliance.PolicyCompliance, policyCompliance)
+	}
+	writeJSON(w, compliance)
+}
+
+// performanceDataRetention is how long the mock keeps performance data
+const performanceDataRetention = 7 * 24 * time.Hour
+
+// performanceMaxPageSize is the number of samples of a page of the performance metrics
+const performanceMaxPageSize = 10
+
+// performanceAvailability returns the first and last dates, in milliseconds since the epoch, of the performance data
+func performanceAvailability() (int64, int64) {
+	last := time.Now().Truncate(types.PerformanceSampleInterval)
+	first := last.Add(-performanceDataRetention)
+	return first.UnixNano() / int64(time.Millisecond), last.UnixNano() / int64(time.Millisecond)
+}
+
+func isFEDirector(dID string) bool {
+	return strings.HasPrefix(dID, "FA-") || strings.HasPrefix(dID, "SE-")
+}
+
+// POST /univmax/restapi/performance/{category}/keys
+func handlePerformanceKeys(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, "Invalid Method", http.StatusBadRequest)
+		return
+	}
+	if InducedErrors.GetPerformanceKeysError {
+		writeError(w, "Error getting the performance keys: induced error", http.StatusRequestTimeout)
+		return
+	}
+	keysParam := &types.PerformanceKeysParam{}
+	if err := json.NewDecoder(r.Body).Decode(keysParam); err != nil {
+		writeError(w, "problem decoding POST performance keys payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	first, last := performanceAvailability()
+	replacements := map[string]string{
+		"__FIRST_AVAILABLE_DATE__": strconv.FormatInt(first, 10),
+		"__LAST_AVAILABLE_DATE__":  strconv.FormatInt(last, 10),
+	}
+	switch mux.Vars(r)["category"] {
+	case types.PerformanceCategoryArray:
+		writeJSON(w, &types.PerformanceKeysResult{ArrayInfo: []types.PerformanceKeyInfo{{
+			SymmetrixID:        DefaultSymmetrixID,
+			FirstAvailableDate: first,
+			LastAvailableDate:  last,
+		}}})
+	case types.PerformanceCategoryStorageGroup:
+		sgIDs := make([]string, 0)
+		for sgID := range Data.StorageGroupIDToStorageGroup {
+			sgIDs = append(sgIDs, sgID)
+		}
+		sort.Strings(sgIDs)
+		keysResult := &types.PerformanceKeysResult{StorageGroupInfo: make([]types.PerformanceKeyInfo, 0)}
+		for _, sgID := range sgIDs {
+			keysResult.StorageGroupInfo = append(keysResult.StorageGroupInfo, types.PerformanceKeyInfo{
+				StorageGroupID:     sgID,
+				FirstAvailableDate: first,
+				LastAvailableDate:  last,
+			})
+		}
+		writeJSON(w, keysResult)
+	case types.PerformanceCategoryFEDirector:
+		returnJSONFile(Data.JSONDir, "performance_fedirector_keys.json", w, replacements)
+	case types.PerformanceCategoryFEPort:
+		if !isFEDirector(keysParam.DirectorID) {
+			writeError(w, "Front end director cannot be found: "+keysParam.DirectorID, http.StatusNotFound)
+			return
+		}
+		returnJSONFile(Data.JSONDir, "performance_feport_keys.json", w, replacements)
+	default:
+		writeError(w, "Performance category cannot be found: "+mux.Vars(r)["category"], http.StatusNotFound)
+	}
+}
+
+// POST /univmax/restapi/performance/{category}/metrics
+// The metrics are sampled every 5 minutes from the start date up to, but excluding, the end date.
+// The value of the nth metric of the ith sample is 100 * n + i. The samples are returned in pages of performanceMaxPageSize
+func handlePerformanceMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, "Invalid Method", http.StatusBadRequest)
+		return
+	}
+	if InducedErrors.GetPerformanceMetricsError {
+		writeError(w, "Error getting the performance metrics: induced error", http.StatusRequestTimeout)
+		return
+	}
+	metricsParam := &types.PerformanceMetricsParam{}
+	if err := json.NewDecoder(r.Body).Decode(metricsParam); err != nil {
+		writeError(w, "problem decoding POST performance metrics payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	category := mux.Vars(r)["category"]
+	switch category {
+	case types.PerformanceCategoryArray:
+	case types.PerformanceCategoryStorageGroup:
+		if _, ok := Data.StorageGroupIDToStorageGroup[metricsParam.StorageGroupID]; !ok {
+			writeError(w, "Storage Group cannot be found: "+metricsParam.StorageGroupID, http.StatusNotFound)
+			return
+		}
+	case types.PerformanceCategoryVolume:
+		if vol, ok := Data.VolumeIDToVolume[metricsParam.VolumeStartRange]; !ok || vol == nil || metricsParam.VolumeEndRange != metricsParam.VolumeStartRange {
+			writeError(w, "Volume cannot be found: "+metricsParam.VolumeStartRange, http.StatusNotFound)
+			return
+		}
+	case types.PerformanceCategoryFEDirector, types.PerformanceCategoryFEPort:
+		if !isFEDirector(metricsParam.DirectorID) {
+			writeError(w, "Front end director cannot be found: "+metricsParam.DirectorID, http.StatusNotFound)
+			return
+		}
+	default:
+		writeError(w, "Performance category cannot be found: "+category, http.StatusNotFound)
+		return
+	}
+	first, _ := performanceAvailability()
+	if metricsParam.StartDate < first {
+		writeError(w, "the start date is before the first available date of the performance data", http.StatusBadRequest)
+		return
+	}
+	if metricsParam.StartDate >= metricsParam.EndDate || len(metricsParam.Metrics) == 0 {
+		writeError(w, "a start date before the end date and metrics are required", http.StatusBadRequest)
+		return
+	}
+	interval := int64(types.PerformanceSampleInterval / time.Millisecond)
+	results := make([]map[string]float64, 0)
+	for timestamp := (metricsParam.StartDate + interval - 1) / interval * interval; timestamp < metricsParam.EndDate; timestamp += interval {
+		result := map[string]float64{"timestamp": float64(timestamp)}
+		for n, metric := range metricsParam.Metrics {
+			result[metric] = float64(100*(n+1) + len(results))
+		}
+		results = append(results, result)
+	}
+	if category == types.PerformanceCategoryVolume {
+		writeJSON(w, &types.VolumePerformanceIterator{
+			ResultList: types.VolumePerformanceResultList{
+				Result: []types.VolumePerformanceResult{{VolumeID: metricsParam.VolumeStartRange, VolumeResult: results}},
+				From:   1,
+				To:     1,
+			},
+			Count:       1,
+			MaxPageSize: 1000,
+		})
+		return
+	}
+	iterator := &types.PerformanceMetricsIterator{
+		ResultList: types.PerformanceMetricsResultList{
+			Result: results,
+			From:   1,
+			To:     len(results),
+		},
+		Count:       len(results),
+		MaxPageSize: performanceMaxPageSize,
+	}
+	if len(results) > performanceMaxPageSize {
+		iterator.ID = fmt.Sprintf("performance-%d", len(Data.IteratorIDToPerformanceResults)+1)
+		iterator.ResultList.Result = results[:performanceMaxPageSize]
+		iterator.ResultList.To = performanceMaxPageSize
+		Data.IteratorIDToPerformanceResults[iterator.ID] = results
+	}
+	writeJSON(w, iterator)
+}
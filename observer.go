@@ -0,0 +1,243 @@
+/*
+ Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+package pmax
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Observer is notified of the API requests, retries, job waits and function calls of a Client,
+// for instance to record them as metrics or traces. The notifications are made synchronously,
+// from the goroutine of the call, so an Observer must be safe for concurrent use and return quickly.
+// Embed NoopObserver to implement only some of the notifications
+type Observer interface {
+	// RequestStart is called before a request is sent to Unisphere
+	RequestStart(event RequestEvent)
+	// RequestEnd is called once the response of a request is received, or the request failed
+	RequestEnd(event RequestEvent)
+	// Retry is called before an operation is attempted again, after a failed request or to poll a state again
+	Retry(event RetryEvent)
+	// JobWait is called once the client stops waiting on a job
+	JobWait(event JobWaitEvent)
+	// CallEnd is called when a function of the client returns
+	CallEnd(event CallEvent)
+}
+
+// RequestEvent describes an API request
+type RequestEvent struct {
+	Context context.Context
+	Method  string
+	// URL is the path of the request, relative to the endpoint
+	URL string
+	// URLTemplate is the path of the request with the IDs replaced by {id} and without the query,
+	// for instance univmax/restapi/91/sloprovisioning/symmetrix/{id}/volume/{id}
+	URLTemplate string
+	// StatusCode is the HTTP status of the response, 0 on RequestStart or if no response was received
+	StatusCode int
+	// Duration is the time taken by the request, 0 on RequestStart
+	Duration time.Duration
+	// Err is the error of a request which received no response
+	Err error
+}
+
+// RetryEvent describes an operation attempted again
+type RetryEvent struct {
+	Operation string
+	// Attempt is the number of the attempt about to be made, starting from 2 for the first retry
+	Attempt int
+	// Err is the error of the previous attempt, nil if a state is polled again
+	Err error
+}
+
+// JobWaitEvent describes the wait on a job
+type JobWaitEvent struct {
+	SymmetrixID string
+	JobID       string
+	// Status is the last known status of the job, empty if it could not be retrieved
+	Status string
+	// Polls is the number of times the job was retrieved
+	Polls    int
+	Duration time.Duration
+	Err      error
+}
+
+// CallEvent describes a call to a function of the client
+type CallEvent struct {
+	Function string
+	Duration time.Duration
+}
+
+// NoopObserver ignores all the notifications, embed it in an Observer implementing only some of them
+type NoopObserver struct{}
+
+// RequestStart ignores the request
+func (NoopObserver) RequestStart(event RequestEvent) {}
+
+// RequestEnd ignores the request
+func (NoopObserver) RequestEnd(event RequestEvent) {}
+
+// Retry ignores the retry
+func (NoopObserver) Retry(event RetryEvent) {}
+
+// JobWait ignores the job wait
+func (NoopObserver) JobWait(event JobWaitEvent) {}
+
+// CallEnd ignores the call
+func (NoopObserver) CallEnd(event CallEvent) {}
+
+// responseTimeLogger logs the time spent in the functions of the client, it is added to
+// the clients created while the X_CSI_POWERMAX_RESPONSE_TIMES environment variable is set
+type responseTimeLogger struct {
+	NoopObserver
+}
+
+func (responseTimeLogger) CallEnd(event CallEvent) {
+	log.Infof("pmax-time: %s took %.2f seconds to complete", event.Function, event.Duration.Seconds())
+}
+
+// clientHooks holds the observers and the tracer of a client
+type clientHooks struct {
+	mutex     sync.RWMutex
+	observers []Observer
+	tracer    Tracer
+}
+
+func (h *clientHooks) getObservers() []Observer {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.observers
+}
+
+func (h *clientHooks) getTracer() Tracer {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.tracer
+}
+
+// AddObserver adds an observer notified of the API requests, retries, job waits and function calls of the client
+func (c *Client) AddObserver(observer Observer) {
+	c.hooks.mutex.Lock()
+	defer c.hooks.mutex.Unlock()
+	c.hooks.observers = append(c.hooks.observers, observer)
+}
+
+// getObservers returns the observers of the client
+func (c *Client) getObservers() []Observer {
+	return c.hooks.getObservers()
+}
+
+func (c *Client) notifyRetry(operation string, attempt int, err error) {
+	for _, observer := range c.getObservers() {
+		observer.Retry(RetryEvent{Operation: operation, Attempt: attempt, Err: err})
+	}
+}
+
+func (c *Client) notifyJobWait(event JobWaitEvent) {
+	for _, observer := range c.getObservers() {
+		observer.JobWait(event)
+	}
+}
+
+// requestObserver notifies the observers of a client of the requests of its HTTP client,
+// and propagates the trace context of the span of each request
+type requestObserver struct {
+	hooks *clientHooks
+}
+
+func (o *requestObserver) RequestStart(req *http.Request, path string) {
+	if span := requestSpanFromContext(req.Context()); span != nil {
+		if traceParent := span.TraceParent(); traceParent != "" {
+			req.Header.Set(TraceParentHeader, traceParent)
+		}
+	}
+	observers := o.hooks.getObservers()
+	if len(observers) == 0 {
+		return
+	}
+	event := newRequestEvent(req, path)
+	for _, observer := range observers {
+		observer.RequestStart(event)
+	}
+}
+
+func (o *requestObserver) RequestEnd(req *http.Request, path string, res *http.Response, err error, duration time.Duration) {
+	if span := requestSpanFromContext(req.Context()); span != nil && res != nil {
+		span.SetAttribute(AttributeHTTPStatusCode, res.StatusCode)
+	}
+	observers := o.hooks.getObservers()
+	if len(observers) == 0 {
+		return
+	}
+	event := newRequestEvent(req, path)
+	event.Duration = duration
+	event.Err = err
+	if res != nil {
+		event.StatusCode = res.StatusCode
+	}
+	for _, observer := range observers {
+		observer.RequestEnd(event)
+	}
+}
+
+func newRequestEvent(req *http.Request, path string) RequestEvent {
+	return RequestEvent{
+		Context:     req.Context(),
+		Method:      req.Method,
+		URL:         path,
+		URLTemplate: URLTemplate(path),
+	}
+}
+
+// urlTemplateCollections are the path segments of the REST API followed by the ID of a resource
+var urlTemplateCollections = map[string]bool{
+	"symmetrix":       true,
+	"volume":          true,
+	"storagegroup":    true,
+	"srp":             true,
+	"slo":             true,
+	"workloadtype":    true,
+	"host":            true,
+	"hostgroup":       true,
+	"initiator":       true,
+	"portgroup":       true,
+	"maskingview":     true,
+	"director":        true,
+	"port":            true,
+	"job":             true,
+	"snapshot":        true,
+	"generation":      true,
+	"rdf_group":       true,
+	"snapshot_policy": true,
+	"Iterator":        true,
+}
+
+// URLTemplate returns the path of a request with the IDs of the resources replaced by {id} and without the query
+func URLTemplate(path string) string {
+	if i := strings.Index(path, "?"); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(path, "/")
+	for i := 1; i < len(segments); i++ {
+		if segments[i] != "" && urlTemplateCollections[segments[i-1]] && !urlTemplateCollections[segments[i]] {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
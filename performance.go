@@ -0,0 +1,382 @@
+/*
+ Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+package pmax
+
+import (
+	"fmt"
+	"time"
+
+	types "github.com/dell/gopowermax/types/v90"
+	log "github.com/sirupsen/logrus"
+)
+
+// The following constants are for internal use within the pmax library.
+const (
+	PerformanceX = "performance/"
+	XKeys        = "/keys"
+	XMetrics     = "/metrics"
+)
+
+// performanceKeyMetrics holds the names of the metrics of a performance category
+// mapped to the fields of the samples. Metrics which do not apply are empty
+type performanceKeyMetrics struct {
+	iops         string
+	mbPerSec     string
+	responseTime string
+	cacheHit     string
+	utilization  string
+}
+
+var keyMetricsByCategory = map[string]performanceKeyMetrics{
+	types.PerformanceCategoryArray:        {iops: "HostIOs", mbPerSec: "HostMBs", responseTime: "ResponseTime", cacheHit: "PercentHit"},
+	types.PerformanceCategoryStorageGroup: {iops: "HostIOs", mbPerSec: "HostMBs", responseTime: "ResponseTime", cacheHit: "PercentHit"},
+	types.PerformanceCategoryVolume:       {iops: "HostIOs", mbPerSec: "HostMBs", responseTime: "ResponseTime", cacheHit: "PercentHit"},
+	types.PerformanceCategoryFEDirector:   {iops: "HostIOs", mbPerSec: "HostMBs", utilization: "PercentBusy"},
+	types.PerformanceCategoryFEPort:       {iops: "IOs", mbPerSec: "MBs", utilization: "PercentBusy"},
+}
+
+func (m performanceKeyMetrics) names() []string {
+	names := make([]string, 0)
+	for _, name := range []string{m.iops, m.mbPerSec, m.responseTime, m.cacheHit, m.utilization} {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (c *Client) performanceURL(category, resource string) string {
+	return RESTPrefix + PerformanceX + category + resource
+}
+
+// GetArrayPerformanceKeys returns the time range of the performance data of the array
+func (c *Client) GetArrayPerformanceKeys(symID string) ([]types.PerformanceKey, error) {
+	defer c.TimeSpent("GetArrayPerformanceKeys", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	keysResult, err := c.getPerformanceKeys(types.PerformanceCategoryArray, &types.PerformanceKeysParam{})
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]types.PerformanceKey, 0)
+	for _, info := range keysResult.ArrayInfo {
+		if info.SymmetrixID == symID {
+			keys = append(keys, newPerformanceKey(info.SymmetrixID, info))
+		}
+	}
+	return keys, nil
+}
+
+// GetStorageGroupPerformanceKeys returns the storage groups of the array with performance data and its time range
+func (c *Client) GetStorageGroupPerformanceKeys(symID string) ([]types.PerformanceKey, error) {
+	defer c.TimeSpent("GetStorageGroupPerformanceKeys", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	keysResult, err := c.getPerformanceKeys(types.PerformanceCategoryStorageGroup, &types.PerformanceKeysParam{SymmetrixID: symID})
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]types.PerformanceKey, 0)
+	for _, info := range keysResult.StorageGroupInfo {
+		keys = append(keys, newPerformanceKey(info.StorageGroupID, info))
+	}
+	return keys, nil
+}
+
+// GetFEDirectorPerformanceKeys returns the front-end directors of the array with performance data and its time range
+func (c *Client) GetFEDirectorPerformanceKeys(symID string) ([]types.PerformanceKey, error) {
+	defer c.TimeSpent("GetFEDirectorPerformanceKeys", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	keysResult, err := c.getPerformanceKeys(types.PerformanceCategoryFEDirector, &types.PerformanceKeysParam{SymmetrixID: symID})
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]types.PerformanceKey, 0)
+	for _, info := range keysResult.FEDirectorInfo {
+		keys = append(keys, newPerformanceKey(info.DirectorID, info))
+	}
+	return keys, nil
+}
+
+// GetFEPortPerformanceKeys returns the ports of a front-end director with performance data and its time range
+func (c *Client) GetFEPortPerformanceKeys(symID, directorID string) ([]types.PerformanceKey, error) {
+	defer c.TimeSpent("GetFEPortPerformanceKeys", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	keysResult, err := c.getPerformanceKeys(types.PerformanceCategoryFEPort, &types.PerformanceKeysParam{SymmetrixID: symID, DirectorID: directorID})
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]types.PerformanceKey, 0)
+	for _, info := range keysResult.FEPortInfo {
+		keys = append(keys, newPerformanceKey(info.PortID, info))
+	}
+	return keys, nil
+}
+
+func (c *Client) getPerformanceKeys(category string, keysParam *types.PerformanceKeysParam) (*types.PerformanceKeysResult, error) {
+	URL := c.performanceURL(category, XKeys)
+	keysResult := &types.PerformanceKeysResult{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err := c.api.Post(ctx, URL, c.getDefaultHeaders(), keysParam, keysResult)
+	if err != nil {
+		log.Error("Get" + category + "PerformanceKeys failed: " + err.Error())
+		return nil, err
+	}
+	return keysResult, nil
+}
+
+func newPerformanceKey(id string, info types.PerformanceKeyInfo) types.PerformanceKey {
+	return types.PerformanceKey{
+		ID:             id,
+		FirstAvailable: fromPerformanceDate(info.FirstAvailableDate),
+		LastAvailable:  fromPerformanceDate(info.LastAvailableDate),
+	}
+}
+
+// GetArrayPerformance returns the performance samples of the array over the time range of the query
+func (c *Client) GetArrayPerformance(symID string, query *types.PerformanceQuery) (*types.PerformanceResult, error) {
+	defer c.TimeSpent("GetArrayPerformance", time.Now())
+	return c.getPerformance(symID, types.PerformanceCategoryArray, symID, query, &types.PerformanceMetricsParam{
+		SymmetrixID: symID,
+	})
+}
+
+// GetStorageGroupPerformance returns the performance samples of a storage group over the time range of the query
+func (c *Client) GetStorageGroupPerformance(symID, sgID string, query *types.PerformanceQuery) (*types.PerformanceResult, error) {
+	defer c.TimeSpent("GetStorageGroupPerformance", time.Now())
+	return c.getPerformance(symID, types.PerformanceCategoryStorageGroup, sgID, query, &types.PerformanceMetricsParam{
+		SymmetrixID:    symID,
+		StorageGroupID: sgID,
+	})
+}
+
+// GetVolumePerformance returns the performance samples of a volume over the time range of the query
+func (c *Client) GetVolumePerformance(symID, volumeID string, query *types.PerformanceQuery) (*types.PerformanceResult, error) {
+	defer c.TimeSpent("GetVolumePerformance", time.Now())
+	return c.getPerformance(symID, types.PerformanceCategoryVolume, volumeID, query, &types.PerformanceMetricsParam{
+		SystemID:         symID,
+		VolumeStartRange: volumeID,
+		VolumeEndRange:   volumeID,
+	})
+}
+
+// GetFEDirectorPerformance returns the performance samples of a front-end director over the time range of the query
+func (c *Client) GetFEDirectorPerformance(symID, directorID string, query *types.PerformanceQuery) (*types.PerformanceResult, error) {
+	defer c.TimeSpent("GetFEDirectorPerformance", time.Now())
+	return c.getPerformance(symID, types.PerformanceCategoryFEDirector, directorID, query, &types.PerformanceMetricsParam{
+		SymmetrixID: symID,
+		DirectorID:  directorID,
+	})
+}
+
+// GetFEPortPerformance returns the performance samples of a port of a front-end director over the time range of the query
+func (c *Client) GetFEPortPerformance(symID, directorID, portID string, query *types.PerformanceQuery) (*types.PerformanceResult, error) {
+	defer c.TimeSpent("GetFEPortPerformance", time.Now())
+	return c.getPerformance(symID, types.PerformanceCategoryFEPort, directorID+":"+portID, query, &types.PerformanceMetricsParam{
+		SymmetrixID: symID,
+		DirectorID:  directorID,
+		PortID:      portID,
+	})
+}
+
+// getPerformance queries the key metrics of the category, and the additional metrics of the query,
+// then aggregates the samples into the intervals of the query
+func (c *Client) getPerformance(symID, category, id string, query *types.PerformanceQuery, metricsParam *types.PerformanceMetricsParam) (*types.PerformanceResult, error) {
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if query == nil || query.StartTime.IsZero() || query.EndTime.IsZero() {
+		return nil, fmt.Errorf("a performance query with a start and an end time is required")
+	}
+	if !query.StartTime.Before(query.EndTime) {
+		return nil, fmt.Errorf("the start time of the performance query must be before its end time")
+	}
+	dataFormat := query.DataFormat
+	if dataFormat == "" {
+		dataFormat = types.PerformanceDataFormatAverage
+	}
+	if dataFormat != types.PerformanceDataFormatAverage && dataFormat != types.PerformanceDataFormatMaximum {
+		return nil, fmt.Errorf("not a supported performance data format: %s", dataFormat)
+	}
+	keyMetrics := keyMetricsByCategory[category]
+	metricsParam.StartDate = toPerformanceDate(query.StartTime)
+	metricsParam.EndDate = toPerformanceDate(query.EndTime)
+	metricsParam.DataFormat = dataFormat
+	metricsParam.Metrics = keyMetrics.names()
+	for _, metric := range query.Metrics {
+		if !stringInSlice(metric, metricsParam.Metrics) {
+			metricsParam.Metrics = append(metricsParam.Metrics, metric)
+		}
+	}
+	ifDebugLogPayload(metricsParam)
+
+	URL := c.performanceURL(category, XMetrics)
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	var results []map[string]float64
+	if category == types.PerformanceCategoryVolume {
+		iterator := &types.VolumePerformanceIterator{}
+		if err := c.api.Post(ctx, URL, c.getDefaultHeaders(), metricsParam, iterator); err != nil {
+			log.Error("GetVolumePerformance failed: " + err.Error())
+			return nil, err
+		}
+		volumeResults := iterator.ResultList.Result
+		for len(volumeResults) < iterator.Count {
+			page := &types.VolumePerformanceResultList{}
+			if err := c.getPerformanceIteratorPage(iterator.ID, len(volumeResults)+1, iterator.Count, iterator.MaxPageSize, page); err != nil {
+				return nil, err
+			}
+			if len(page.Result) == 0 {
+				return nil, fmt.Errorf("Expected %d volume performance results but got %d", iterator.Count, len(volumeResults))
+			}
+			volumeResults = append(volumeResults, page.Result...)
+		}
+		for _, volumeResult := range volumeResults {
+			if volumeResult.VolumeID == id {
+				results = volumeResult.VolumeResult
+			}
+		}
+	} else {
+		iterator := &types.PerformanceMetricsIterator{}
+		if err := c.api.Post(ctx, URL, c.getDefaultHeaders(), metricsParam, iterator); err != nil {
+			log.Error("Get" + category + "Performance failed: " + err.Error())
+			return nil, err
+		}
+		results = iterator.ResultList.Result
+		for len(results) < iterator.Count {
+			page := &types.PerformanceMetricsResultList{}
+			if err := c.getPerformanceIteratorPage(iterator.ID, len(results)+1, iterator.Count, iterator.MaxPageSize, page); err != nil {
+				return nil, err
+			}
+			if len(page.Result) == 0 {
+				return nil, fmt.Errorf("Expected %d performance results but got %d", iterator.Count, len(results))
+			}
+			results = append(results, page.Result...)
+		}
+	}
+	samples := make([]types.PerformanceSample, 0, len(results))
+	for _, metrics := range results {
+		samples = append(samples, newPerformanceSample(metrics))
+	}
+	samples = aggregatePerformanceSamples(samples, query.StartTime, query.Interval, dataFormat)
+	for i := range samples {
+		keyMetrics.setKeyMetrics(&samples[i])
+	}
+	return &types.PerformanceResult{
+		SymmetrixID: symID,
+		Category:    category,
+		ID:          id,
+		Samples:     samples,
+	}, nil
+}
+
+// getPerformanceIteratorPage gets the page of at most maxPageSize results of a performance iterator of count
+// results starting at from, the first result being 1, then deletes the iterator once its last page is read
+func (c *Client) getPerformanceIteratorPage(iteratorID string, from, count, maxPageSize int, page interface{}) error {
+	to := from + maxPageSize - 1
+	if maxPageSize <= 0 || to > count {
+		to = count
+	}
+	URL := RESTPrefix + IteratorX + iteratorID + XPage + fmt.Sprintf("?from=%d&to=%d", from, to)
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	if err := c.api.Get(ctx, URL, c.getDefaultHeaders(), page); err != nil {
+		log.Error("GetPerformanceIteratorPage failed: " + err.Error())
+		return err
+	}
+	if to == count {
+		deleteCtx, deleteCancel := GetTimeoutContext()
+		defer deleteCancel()
+		if err := c.api.Delete(deleteCtx, RESTPrefix+IteratorX+iteratorID, c.getDefaultHeaders(), nil); err != nil {
+			log.Debug("Deleting performance iterator " + iteratorID + " failed: " + err.Error())
+		}
+	}
+	return nil
+}
+
+func newPerformanceSample(metrics map[string]float64) types.PerformanceSample {
+	sample := types.PerformanceSample{
+		Timestamp: fromPerformanceDate(int64(metrics["timestamp"])),
+		Metrics:   make(map[string]float64),
+	}
+	for name, value := range metrics {
+		if name != "timestamp" {
+			sample.Metrics[name] = value
+		}
+	}
+	return sample
+}
+
+func (m performanceKeyMetrics) setKeyMetrics(sample *types.PerformanceSample) {
+	sample.IOPS = sample.Metrics[m.iops]
+	sample.MBPerSec = sample.Metrics[m.mbPerSec]
+	sample.ResponseTime = sample.Metrics[m.responseTime]
+	sample.CacheHitPercent = sample.Metrics[m.cacheHit]
+	sample.UtilizationPercent = sample.Metrics[m.utilization]
+}
+
+// aggregatePerformanceSamples aggregates the samples, oldest first, into intervals starting at start, averaging
+// the metrics of each interval or taking their maximum. The aggregated samples are timestamped at the start of
+// their interval. An interval no longer than the sample interval keeps every sample
+func aggregatePerformanceSamples(samples []types.PerformanceSample, start time.Time, interval time.Duration, dataFormat string) []types.PerformanceSample {
+	if interval <= types.PerformanceSampleInterval || len(samples) == 0 {
+		return samples
+	}
+	aggregated := make([]types.PerformanceSample, 0)
+	counts := make([]int, 0)
+	for _, sample := range samples {
+		intervalStart := start.Add(sample.Timestamp.Sub(start) / interval * interval)
+		last := len(aggregated) - 1
+		if last < 0 || !aggregated[last].Timestamp.Equal(intervalStart) {
+			aggregated = append(aggregated, types.PerformanceSample{Timestamp: intervalStart, Metrics: make(map[string]float64)})
+			counts = append(counts, 0)
+			last++
+		}
+		for name, value := range sample.Metrics {
+			if dataFormat == types.PerformanceDataFormatMaximum {
+				if current, ok := aggregated[last].Metrics[name]; !ok || value > current {
+					aggregated[last].Metrics[name] = value
+				}
+			} else {
+				aggregated[last].Metrics[name] += value
+			}
+		}
+		counts[last]++
+	}
+	for i := range aggregated {
+		if dataFormat != types.PerformanceDataFormatMaximum {
+			for name := range aggregated[i].Metrics {
+				aggregated[i].Metrics[name] /= float64(counts[i])
+			}
+		}
+	}
+	return aggregated
+}
+
+// toPerformanceDate returns the time in milliseconds since the epoch
+func toPerformanceDate(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// fromPerformanceDate returns the time of a date in milliseconds since the epoch
+func fromPerformanceDate(date int64) time.Time {
+	return time.Unix(0, date*int64(time.Millisecond))
+}
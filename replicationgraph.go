@@ -0,0 +1,56 @@
+/*
+ Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+package pmax
+
+import (
+	"fmt"
+	"time"
+
+	types "github.com/dell/gopowermax/types/v90"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetReplicationGraph returns the replication graph of the volumes. Starting from the given volumes, the graph
+// follows the snapshot links, clone and mirror sessions to the volumes they relate to, so that it holds whole
+// chains of source volumes, snapshots, generations and linked targets
+func (c *Client) GetReplicationGraph(symID string, volumeIDs ...string) (*types.ReplicationGraph, error) {
+	defer c.TimeSpent("GetReplicationGraph", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if len(volumeIDs) == 0 {
+		return nil, fmt.Errorf("at least one volume is required to build the replication graph")
+	}
+	graph := &types.ReplicationGraph{
+		SymmetrixID: symID,
+		Volumes:     make(map[string]*types.ReplicationGraphVolume),
+	}
+	queue := append([]string{}, volumeIDs...)
+	for len(queue) > 0 {
+		volumeID := queue[0]
+		queue = queue[1:]
+		if _, ok := graph.Volumes[volumeID]; ok {
+			continue
+		}
+		privVolume, err := c.GetPrivVolumeByID(symID, volumeID)
+		if err != nil {
+			return nil, err
+		}
+		volume := types.NewReplicationGraphVolume(volumeID, &privVolume.TimeFinderInfo)
+		graph.Volumes[volumeID] = volume
+		queue = append(queue, volume.RelatedVolumes()...)
+	}
+	log.Debug(fmt.Sprintf("Replication graph of volumes %v has %d volumes", volumeIDs, len(graph.Volumes)))
+	return graph, nil
+}
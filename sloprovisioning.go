@@ -18,12 +18,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/dell/gopowermax/api"
 	types "github.com/dell/gopowermax/types/v90"
 	log "github.com/sirupsen/logrus"
 )
@@ -57,24 +59,30 @@ func GetTimeoutContext() (context.Context, context.CancelFunc) {
 	return ctx, cancel
 }
 
-//TimeSpent - Calculates and prints time spent for a caller function
+//TimeSpent - Calculates the time spent for a caller function and notifies the observers of the client
 func (c *Client) TimeSpent(functionName string, startTime time.Time) {
-	if logResponseTimes {
-		if functionName == "" {
-			pc, _, _, ok := runtime.Caller(1)
-			details := runtime.FuncForPC(pc)
-			if ok && details != nil {
-				functionName = details.Name()
-			}
+	observers := c.getObservers()
+	if len(observers) == 0 {
+		return
+	}
+	if functionName == "" {
+		pc, _, _, ok := runtime.Caller(1)
+		details := runtime.FuncForPC(pc)
+		if ok && details != nil {
+			functionName = details.Name()
 		}
-		endTime := time.Now()
-		log.Infof("pmax-time: %s took %.2f seconds to complete", functionName, endTime.Sub(startTime).Seconds())
+	}
+	event := CallEvent{Function: functionName, Duration: time.Since(startTime)}
+	for _, observer := range observers {
+		observer.CallEnd(event)
 	}
 }
 
 // GetVolumeIDsIterator returns a VolumeIDs Iterator. It generally fetches the first page in the result as part of the operation.
-func (c *Client) GetVolumeIDsIterator(symID string, volumeIdentifierMatch string, like bool) (*types.VolumeIterator, error) {
+func (c *Client) GetVolumeIDsIterator(symID string, volumeIdentifierMatch string, like bool) (_ *types.VolumeIterator, err error) {
 	defer c.TimeSpent("GetVolumeIDsIterator", time.Now())
+	c, endSpan := c.traceOperation("GetVolumeIDsIterator", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -91,7 +99,9 @@ func (c *Client) GetVolumeIDsIterator(symID string, volumeIdentifierMatch string
 }
 
 // GetVolumesInStorageGroupIterator returns a iterator of a list of volumes associated with a StorageGroup.
-func (c *Client) GetVolumesInStorageGroupIterator(symID string, storageGroupId string) (*types.VolumeIterator, error) {
+func (c *Client) GetVolumesInStorageGroupIterator(symID string, storageGroupId string) (_ *types.VolumeIterator, err error) {
+	c, endSpan := c.traceOperation("GetVolumesInStorageGroupIterator", AttributeSymmetrixID, symID, AttributeStorageGroupID, storageGroupId)
+	defer endSpan(&err)
 	var query string
 	if storageGroupId == "" {
 		return nil, fmt.Errorf("storageGroupId is empty")
@@ -130,8 +140,10 @@ func (c *Client) getVolumeIDsIteratorBase(symID string, query string) (*types.Vo
 }
 
 // GetVolumeIDsIteratorPage fetches the next page of the iterator's result. From is the starting point. To can be left as 0, or can be set to the last element desired.
-func (c *Client) GetVolumeIDsIteratorPage(iter *types.VolumeIterator, from, to int) ([]string, error) {
+func (c *Client) GetVolumeIDsIteratorPage(iter *types.VolumeIterator, from, to int) (_ []string, err error) {
 	defer c.TimeSpent("GetVolumeIDsIteratorPage", time.Now())
+	c, endSpan := c.traceOperation("GetVolumeIDsIteratorPage")
+	defer endSpan(&err)
 	if to == 0 || to-from+1 > iter.MaxPageSize {
 		to = from + iter.MaxPageSize - 1
 	}
@@ -168,12 +180,14 @@ func (c *Client) GetVolumeIDsIteratorPage(iter *types.VolumeIterator, from, to i
 }
 
 // DeleteVolumeIDsIterator deletes a volume iterator.
-func (c *Client) DeleteVolumeIDsIterator(iter *types.VolumeIterator) error {
+func (c *Client) DeleteVolumeIDsIterator(iter *types.VolumeIterator) (err error) {
 	defer c.TimeSpent("DeleteVolumeIDsIterator", time.Now())
+	c, endSpan := c.traceOperation("DeleteVolumeIDsIterator")
+	defer endSpan(&err)
 	URL := RESTPrefix + IteratorX + iter.ID
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Delete(ctx, URL, c.getDefaultHeaders(), nil)
+	err = c.api.Delete(ctx, URL, c.getDefaultHeaders(), nil)
 	if err != nil {
 		return err
 	}
@@ -184,8 +198,10 @@ func (c *Client) DeleteVolumeIDsIterator(iter *types.VolumeIterator) error {
 // all volumes are returned. Otherwise the volumes are filtered to volumes whose VolumeIdentifier
 // exactly matches the volumeIdentfierMatch argument (when like is false), or whose VolumeIdentifier
 // contains the volumeIdentifierMatch argument (when like is true).
-func (c *Client) GetVolumeIDList(symID string, volumeIdentifierMatch string, like bool) ([]string, error) {
+func (c *Client) GetVolumeIDList(symID string, volumeIdentifierMatch string, like bool) (_ []string, err error) {
 	defer c.TimeSpent("GetVolumeIDList", time.Now())
+	c, endSpan := c.traceOperation("GetVolumeIDList", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -196,7 +212,9 @@ func (c *Client) GetVolumeIDList(symID string, volumeIdentifierMatch string, lik
 	return c.volumeIteratorToVolIdList(iter)
 }
 
-func (c *Client) GetVolumeIDListInStorageGroup(symID string, storageGroupId string) ([]string, error) {
+func (c *Client) GetVolumeIDListInStorageGroup(symID string, storageGroupId string) (_ []string, err error) {
+	c, endSpan := c.traceOperation("GetVolumeIDListInStorageGroup", AttributeSymmetrixID, symID, AttributeStorageGroupID, storageGroupId)
+	defer endSpan(&err)
 	iter, err := c.GetVolumesInStorageGroupIterator(symID, storageGroupId)
 	if err != nil {
 		return nil, err
@@ -233,8 +251,10 @@ func (c *Client) volumeIteratorToVolIdList(iter *types.VolumeIterator) ([]string
 }
 
 // GetVolumeByID returns a Volume structure given the symmetrix and volume ID (volume ID is 5-digit hex field)
-func (c *Client) GetVolumeByID(symID string, volumeID string) (*types.Volume, error) {
+func (c *Client) GetVolumeByID(symID string, volumeID string) (_ *types.Volume, err error) {
 	defer c.TimeSpent("GetVolumeByID", time.Now())
+	c, endSpan := c.traceOperation("GetVolumeByID", AttributeSymmetrixID, symID, AttributeVolumeID, volumeID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -261,8 +281,10 @@ func (c *Client) GetVolumeByID(symID string, volumeID string) (*types.Volume, er
 }
 
 // GetStorageGroupIDList returns a list of StorageGroupIds in a StorageGroupIDList type.
-func (c *Client) GetStorageGroupIDList(symID string) (*types.StorageGroupIDList, error) {
+func (c *Client) GetStorageGroupIDList(symID string) (_ *types.StorageGroupIDList, err error) {
 	defer c.TimeSpent("GetStorageGroupIDList", time.Now())
+	c, endSpan := c.traceOperation("GetStorageGroupIDList", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -291,8 +313,10 @@ func (c *Client) GetStorageGroupIDList(symID string) (*types.StorageGroupIDList,
 
 // CreateStorageGroup creates a Storage Group given the storageGroupID (name), srpID (storage resource pool), service level, and boolean for thick volumes.
 // If srpID is "None" then serviceLevel and thickVolumes settings are ignored
-func (c *Client) CreateStorageGroup(symID, storageGroupID, srpID, serviceLevel string, thickVolumes bool) (*types.StorageGroup, error) {
+func (c *Client) CreateStorageGroup(symID, storageGroupID, srpID, serviceLevel string, thickVolumes bool) (_ *types.StorageGroup, err error) {
 	defer c.TimeSpent("CreateStorageGroup", time.Now())
+	c, endSpan := c.traceOperation("CreateStorageGroup", AttributeSymmetrixID, symID, AttributeStorageGroupID, storageGroupID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -339,15 +363,17 @@ func (c *Client) CreateStorageGroup(symID, storageGroupID, srpID, serviceLevel s
 }
 
 //DeleteStorageGroup deletes a storage group
-func (c *Client) DeleteStorageGroup(symID string, storageGroupID string) error {
+func (c *Client) DeleteStorageGroup(symID string, storageGroupID string) (err error) {
 	defer c.TimeSpent("DeleteStorageGroup", time.Now())
+	c, endSpan := c.traceOperation("DeleteStorageGroup", AttributeSymmetrixID, symID, AttributeStorageGroupID, storageGroupID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return err
 	}
 	URL := c.urlPrefix() + SLOProvisioningX + SymmetrixX + symID + XStorageGroup + "/" + storageGroupID
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Delete(ctx, URL, c.getDefaultHeaders(), nil)
+	err = c.api.Delete(ctx, URL, c.getDefaultHeaders(), nil)
 	if err != nil {
 		log.Error("DeleteStorageGroup failed: " + err.Error())
 		return err
@@ -357,15 +383,17 @@ func (c *Client) DeleteStorageGroup(symID string, storageGroupID string) error {
 }
 
 //DeleteMaskingView deletes a storage group
-func (c *Client) DeleteMaskingView(symID string, maskingViewID string) error {
+func (c *Client) DeleteMaskingView(symID string, maskingViewID string) (err error) {
 	defer c.TimeSpent("DeleteMaskingView", time.Now())
+	c, endSpan := c.traceOperation("DeleteMaskingView", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return err
 	}
 	URL := c.urlPrefix() + SLOProvisioningX + SymmetrixX + symID + XMaskingView + "/" + maskingViewID
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Delete(ctx, URL, c.getDefaultHeaders(), nil)
+	err = c.api.Delete(ctx, URL, c.getDefaultHeaders(), nil)
 	if err != nil {
 		log.Error("DeleteMaskingView failed: " + err.Error())
 		return err
@@ -375,8 +403,10 @@ func (c *Client) DeleteMaskingView(symID string, maskingViewID string) error {
 }
 
 // GetStorageGroup returns a StorageGroup given the Symmetrix ID and Storage Group ID (which is really a name).
-func (c *Client) GetStorageGroup(symID string, storageGroupID string) (*types.StorageGroup, error) {
+func (c *Client) GetStorageGroup(symID string, storageGroupID string) (_ *types.StorageGroup, err error) {
 	defer c.TimeSpent("GetStorageGroup", time.Now())
+	c, endSpan := c.traceOperation("GetStorageGroup", AttributeSymmetrixID, symID, AttributeStorageGroupID, storageGroupID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -403,8 +433,10 @@ func (c *Client) GetStorageGroup(symID string, storageGroupID string) (*types.St
 }
 
 // GetStoragePool returns a StoragePool given the Symmetrix ID and Storage Pool ID
-func (c *Client) GetStoragePool(symID string, storagePoolID string) (*types.StoragePool, error) {
+func (c *Client) GetStoragePool(symID string, storagePoolID string) (_ *types.StoragePool, err error) {
 	defer c.TimeSpent("GetStoragePool", time.Now())
+	c, endSpan := c.traceOperation("GetStoragePool", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -412,7 +444,7 @@ func (c *Client) GetStoragePool(symID string, storagePoolID string) (*types.Stor
 	storagePool := &types.StoragePool{}
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), storagePool)
+	err = c.api.Get(ctx, URL, c.getDefaultHeaders(), storagePool)
 	if err != nil {
 		log.Error("GetStoragePool failed: " + err.Error())
 		return nil, err
@@ -421,8 +453,10 @@ func (c *Client) GetStoragePool(symID string, storagePoolID string) (*types.Stor
 }
 
 // UpdateStorageGroup is a general method to update a StorageGroup (PUT operation) using a UpdateStorageGroupPayload.
-func (c *Client) UpdateStorageGroup(symID string, storageGroupID string, payload *types.UpdateStorageGroupPayload) (*types.Job, error) {
+func (c *Client) UpdateStorageGroup(symID string, storageGroupID string, payload *types.UpdateStorageGroupPayload) (_ *types.Job, err error) {
 	defer c.TimeSpent("UpdateStorageGroup", time.Now())
+	c, endSpan := c.traceOperation("UpdateStorageGroup", AttributeSymmetrixID, symID, AttributeStorageGroupID, storageGroupID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -435,7 +469,7 @@ func (c *Client) UpdateStorageGroup(symID string, storageGroupID string, payload
 
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Put(
+	err = c.api.Put(
 		ctx, URL, c.getDefaultHeaders(), payload, job)
 	if err != nil {
 		log.WithFields(fields).Error("Error in UpdateStorageGroup: " + err.Error())
@@ -452,15 +486,90 @@ func ifDebugLogPayload(payload interface{}) {
 	if err != nil {
 		log.Error("could not Marshal json payload: " + err.Error())
 	} else {
-		log.Info("payload: " + string(payloadBytes))
+		log.Debug("payload: " + string(api.RedactJSON(payloadBytes)))
 	}
 }
 
 // CreateVolumeInStorageGroup creates a volume in the specified Storage Group with a given volumeName
 // and the size of the volume in cylinders.
+// When the Storage Group is SRDF protected in a single RDF group the remote mirror of the volume is created
+// in the Storage Group of the same name on the remote array.
 func (c *Client) CreateVolumeInStorageGroup(
-	symID string, storageGroupID string, volumeName string, sizeInCylinders int) (*types.Volume, error) {
+	symID string, storageGroupID string, volumeName string, sizeInCylinders int) (_ *types.Volume, err error) {
 	defer c.TimeSpent("CreateVolumeInStorageGroup", time.Now())
+	c, endSpan := c.traceOperation("CreateVolumeInStorageGroup", AttributeSymmetrixID, symID, AttributeStorageGroupID, storageGroupID)
+	defer endSpan(&err)
+	rdfGroupNumber, err := c.getStorageGroupRDFGroupNumber(symID, storageGroupID)
+	if err != nil {
+		return nil, err
+	}
+	if rdfGroupNumber == 0 {
+		return c.createVolumeInStorageGroup(symID, storageGroupID, volumeName, sizeInCylinders, nil)
+	}
+	devicePair, err := c.createRDFVolumeInStorageGroup(symID, storageGroupID, storageGroupID, rdfGroupNumber, volumeName, sizeInCylinders)
+	if err != nil {
+		return nil, err
+	}
+	log.Info(fmt.Sprintf("Created volume: %s in SG: %s mirrored by remote volume: %s on Symmetrix: %s",
+		devicePair.LocalVolumeName, storageGroupID, devicePair.RemoteVolumeName, devicePair.RemoteSymmetrixID))
+	return c.GetVolumeByID(symID, devicePair.LocalVolumeName)
+}
+
+// CreateRDFVolumeInStorageGroup creates a volume in an SRDF protected Storage Group, along with its
+// remote mirror in remoteStorageGroupID on the remote array of the RDF group protecting the Storage Group.
+// The returned RDFDevicePair holds the ids of both the local and the remote volumes.
+func (c *Client) CreateRDFVolumeInStorageGroup(
+	symID string, storageGroupID string, remoteStorageGroupID string, volumeName string, sizeInCylinders int) (_ *types.RDFDevicePair, err error) {
+	defer c.TimeSpent("CreateRDFVolumeInStorageGroup", time.Now())
+	c, endSpan := c.traceOperation("CreateRDFVolumeInStorageGroup", AttributeSymmetrixID, symID, AttributeStorageGroupID, storageGroupID)
+	defer endSpan(&err)
+	rdfGroupNumber, err := c.getStorageGroupRDFGroupNumber(symID, storageGroupID)
+	if err != nil {
+		return nil, err
+	}
+	if rdfGroupNumber == 0 {
+		return nil, fmt.Errorf("StorageGroup %s is not SRDF protected", storageGroupID)
+	}
+	return c.createRDFVolumeInStorageGroup(symID, storageGroupID, remoteStorageGroupID, rdfGroupNumber, volumeName, sizeInCylinders)
+}
+
+// getStorageGroupRDFGroupNumber returns the number of the RDF group protecting a Storage Group, or 0 if it is not SRDF protected.
+// The RDF group of a Storage Group protected in several RDF groups cannot be chosen and is an error.
+func (c *Client) getStorageGroupRDFGroupNumber(symID string, storageGroupID string) (int, error) {
+	sgRDFGroupList, err := c.GetStorageGroupRDFGroupList(symID, storageGroupID)
+	if err != nil {
+		return 0, err
+	}
+	switch len(sgRDFGroupList.RDFGroupNumbers) {
+	case 0:
+		return 0, nil
+	case 1:
+		return sgRDFGroupList.RDFGroupNumbers[0], nil
+	}
+	return 0, fmt.Errorf("StorageGroup %s is SRDF protected in %d RDF groups, its volumes must be paired in each of them",
+		storageGroupID, len(sgRDFGroupList.RDFGroupNumbers))
+}
+
+// createRDFVolumeInStorageGroup creates a volume in a Storage Group and its remote mirror in the RDF group
+func (c *Client) createRDFVolumeInStorageGroup(
+	symID string, storageGroupID string, remoteStorageGroupID string, rdfGroupNumber int, volumeName string, sizeInCylinders int) (*types.RDFDevicePair, error) {
+	rdfGroup, err := c.GetRDFGroup(symID, rdfGroupNumber)
+	if err != nil {
+		return nil, err
+	}
+	remoteSGInfo := &types.RemoteSymmSGInfoParam{
+		RemoteSymmetrix1ID:  rdfGroup.RemoteSymmetrix,
+		RemoteSymmetrix1SGs: []string{remoteStorageGroupID},
+	}
+	vol, err := c.createVolumeInStorageGroup(symID, storageGroupID, volumeName, sizeInCylinders, remoteSGInfo)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetRDFDevicePair(symID, rdfGroupNumber, vol.VolumeID)
+}
+
+func (c *Client) createVolumeInStorageGroup(
+	symID string, storageGroupID string, volumeName string, sizeInCylinders int, remoteSGInfo *types.RemoteSymmSGInfoParam) (*types.Volume, error) {
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -481,6 +590,7 @@ func (c *Client) CreateVolumeInStorageGroup(
 			VolumeIdentifierChoice: "identifier_name",
 			IdentifierName:         volumeName,
 		},
+		RemoteSymmSGInfoParam: remoteSGInfo,
 	}
 
 	payload := &types.UpdateStorageGroupPayload{
@@ -493,7 +603,10 @@ func (c *Client) CreateVolumeInStorageGroup(
 	ifDebugLogPayload(payload)
 
 	job, err := c.UpdateStorageGroup(symID, storageGroupID, payload)
-	if err != nil || job == nil {
+	if err != nil {
+		return nil, fmt.Errorf("A job was not returned from UpdateStorageGroup: %s", err.Error())
+	}
+	if job == nil {
 		return nil, fmt.Errorf("A job was not returned from UpdateStorageGroup")
 	}
 
@@ -531,8 +644,37 @@ func (c *Client) CreateVolumeInStorageGroup(
 	return nil, fmt.Errorf(errormsg)
 }
 
-// Expand an existing volume to a new (larger) size in GB
-func (c *Client) ExpandVolume(symID string, volumeID string, newSizeGB int) (*types.Volume, error) {
+// ExpandVolume expands an existing volume to a new (larger) size in GB.
+// An SRDF protected volume is expanded along with its remote mirror when it is paired in a single RDF group.
+func (c *Client) ExpandVolume(symID string, volumeID string, newSizeGB int) (_ *types.Volume, err error) {
+	defer c.TimeSpent("ExpandVolume", time.Now())
+	c, endSpan := c.traceOperation("ExpandVolume", AttributeSymmetrixID, symID, AttributeVolumeID, volumeID)
+	defer endSpan(&err)
+	rdfGroupNumber, err := c.getVolumeRDFGroupNumber(symID, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	return c.expandVolume(symID, volumeID, rdfGroupNumber, newSizeGB)
+}
+
+// getVolumeRDFGroupNumber returns the number of the RDF group a volume is paired in, or 0 if it is not SRDF protected.
+// The RDF group of a volume paired in several RDF groups cannot be chosen and is an error.
+func (c *Client) getVolumeRDFGroupNumber(symID string, volumeID string) (int, error) {
+	vol, err := c.GetVolumeByID(symID, volumeID)
+	if err != nil {
+		return 0, err
+	}
+	switch len(vol.RDFGroupIDList) {
+	case 0:
+		return 0, nil
+	case 1:
+		return vol.RDFGroupIDList[0].RDFGroupNumber, nil
+	}
+	return 0, fmt.Errorf("Volume %s is paired in %d RDF groups, the RDF group number must be given", volumeID, len(vol.RDFGroupIDList))
+}
+
+// expandVolume expands a volume, along with its remote mirror in the RDF group if rdfGroupNumber is not 0
+func (c *Client) expandVolume(symID string, volumeID string, rdfGroupNumber int, newSizeGB int) (*types.Volume, error) {
 	payload := &types.EditVolumeParam{
 		EditVolumeActionParam: types.EditVolumeActionParam{
 			ExpandVolumeParam: &types.ExpandVolumeParam{
@@ -540,6 +682,7 @@ func (c *Client) ExpandVolume(symID string, volumeID string, newSizeGB int) (*ty
 					VolumeSize:   fmt.Sprintf("%d", newSizeGB),
 					CapacityUnit: "GB",
 				},
+				RDFGroupNumber: rdfGroupNumber,
 			},
 		},
 	}
@@ -558,9 +701,50 @@ func (c *Client) ExpandVolume(symID string, volumeID string, newSizeGB int) (*ty
 	return vol, err
 }
 
+// ExpandRDFVolume expands an SRDF protected volume and its remote mirror in the RDF group to a new (larger) size in GB.
+// The RDF group is looked up when rdfGroupNumber is 0, which requires the volume to be paired in a single RDF group.
+// The returned RDFDevicePair holds the ids of both the local and the remote volumes.
+func (c *Client) ExpandRDFVolume(symID string, volumeID string, rdfGroupNumber int, newSizeGB int) (_ *types.RDFDevicePair, err error) {
+	defer c.TimeSpent("ExpandRDFVolume", time.Now())
+	c, endSpan := c.traceOperation("ExpandRDFVolume", AttributeSymmetrixID, symID, AttributeVolumeID, volumeID)
+	defer endSpan(&err)
+	var devicePair *types.RDFDevicePair
+	if rdfGroupNumber == 0 {
+		devicePair, err = c.GetVolumeRDFPair(symID, volumeID)
+	} else {
+		devicePair, err = c.GetRDFDevicePair(symID, rdfGroupNumber, volumeID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	rdfGroupNumber = devicePair.LocalRDFGroupNumber
+	if _, err = c.expandVolume(symID, volumeID, rdfGroupNumber, newSizeGB); err != nil {
+		return nil, err
+	}
+	return devicePair, nil
+}
+
+// GetVolumeRDFPair returns the SRDF relationship of a volume paired in a single RDF group.
+// The relationships of a volume in concurrent or cascaded SRDF must be retrieved with GetRDFDevicePair.
+func (c *Client) GetVolumeRDFPair(symID string, volumeID string) (_ *types.RDFDevicePair, err error) {
+	defer c.TimeSpent("GetVolumeRDFPair", time.Now())
+	c, endSpan := c.traceOperation("GetVolumeRDFPair", AttributeSymmetrixID, symID, AttributeVolumeID, volumeID)
+	defer endSpan(&err)
+	rdfGroupNumber, err := c.getVolumeRDFGroupNumber(symID, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	if rdfGroupNumber == 0 {
+		return nil, fmt.Errorf("Volume %s is not SRDF protected", volumeID)
+	}
+	return c.GetRDFDevicePair(symID, rdfGroupNumber, volumeID)
+}
+
 // AddVolumesToStorageGroup adds one or more volumes (given by their volumeIDs) to a StorageGroup.
-func (c *Client) AddVolumesToStorageGroup(symID string, storageGroupID string, volumeIDs ...string) error {
+func (c *Client) AddVolumesToStorageGroup(symID string, storageGroupID string, volumeIDs ...string) (err error) {
 	defer c.TimeSpent("AddVolumesToStorageGroup", time.Now())
+	c, endSpan := c.traceOperation("AddVolumesToStorageGroup", AttributeSymmetrixID, symID, AttributeStorageGroupID, storageGroupID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return err
 	}
@@ -599,8 +783,10 @@ func (c *Client) AddVolumesToStorageGroup(symID string, storageGroupID string, v
 }
 
 // RemoveVolumesFromStorageGroup removes one or more volumes (given by their volumeIDs) from a StorageGroup.
-func (c *Client) RemoveVolumesFromStorageGroup(symID string, storageGroupID string, volumeIDs ...string) (*types.StorageGroup, error) {
+func (c *Client) RemoveVolumesFromStorageGroup(symID string, storageGroupID string, volumeIDs ...string) (_ *types.StorageGroup, err error) {
 	defer c.TimeSpent("RemoveVolumesFromStorageGroup", time.Now())
+	c, endSpan := c.traceOperation("RemoveVolumesFromStorageGroup", AttributeSymmetrixID, symID, AttributeStorageGroupID, storageGroupID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -624,7 +810,7 @@ func (c *Client) RemoveVolumesFromStorageGroup(symID string, storageGroupID stri
 	updatedStorageGroup := &types.StorageGroup{}
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Put(
+	err = c.api.Put(
 		ctx, URL, c.getDefaultHeaders(), payload, updatedStorageGroup)
 	if err != nil {
 		log.WithFields(fields).Error("Error in RemoveVolumesFromStorageGroup: " + err.Error())
@@ -635,8 +821,10 @@ func (c *Client) RemoveVolumesFromStorageGroup(symID string, storageGroupID stri
 }
 
 // GetStoragePoolList returns a StoragePoolList object, which contains a list of all the Storage Pool names.
-func (c *Client) GetStoragePoolList(symid string) (*types.StoragePoolList, error) {
+func (c *Client) GetStoragePoolList(symid string) (_ *types.StoragePoolList, err error) {
 	defer c.TimeSpent("GetStoragePoolList", time.Now())
+	c, endSpan := c.traceOperation("GetStoragePoolList", AttributeSymmetrixID, symid)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symid); err != nil {
 		return nil, err
 	}
@@ -644,7 +832,7 @@ func (c *Client) GetStoragePoolList(symid string) (*types.StoragePoolList, error
 	spList := &types.StoragePoolList{}
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), spList)
+	err = c.api.Get(ctx, URL, c.getDefaultHeaders(), spList)
 	if err != nil {
 		log.Error("GetStoragePoolList failed: " + err.Error())
 		return nil, err
@@ -653,8 +841,10 @@ func (c *Client) GetStoragePoolList(symid string) (*types.StoragePoolList, error
 }
 
 // RenameVolume renames a volume.
-func (c *Client) RenameVolume(symID string, volumeID string, newName string) (*types.Volume, error) {
+func (c *Client) RenameVolume(symID string, volumeID string, newName string) (_ *types.Volume, err error) {
 	defer c.TimeSpent("RenameVolume", time.Now())
+	c, endSpan := c.traceOperation("RenameVolume", AttributeSymmetrixID, symID, AttributeVolumeID, volumeID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -683,7 +873,7 @@ func (c *Client) RenameVolume(symID string, volumeID string, newName string) (*t
 	log.WithFields(fields).Info("Renaming volume")
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Put(
+	err = c.api.Put(
 		ctx, URL, c.getDefaultHeaders(), payload, volume)
 	if err != nil {
 		log.WithFields(fields).Error("Error in RenameVolume: " + err.Error())
@@ -696,8 +886,10 @@ func (c *Client) RenameVolume(symID string, volumeID string, newName string) (*t
 // DeleteVolume deletes a volume given the symmetrix ID and volume ID.
 // Any storage tracks for the volume must have been previously deallocated using InitiateDeallocationOfTracksFromVolume,
 // and the volume must not be a member of any Storage Group.
-func (c *Client) DeleteVolume(symID string, volumeID string) error {
+func (c *Client) DeleteVolume(symID string, volumeID string) (err error) {
 	defer c.TimeSpent("DeleteVolume", time.Now())
+	c, endSpan := c.traceOperation("DeleteVolume", AttributeSymmetrixID, symID, AttributeVolumeID, volumeID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return err
 	}
@@ -709,7 +901,7 @@ func (c *Client) DeleteVolume(symID string, volumeID string) error {
 	log.WithFields(fields).Info("Deleting volume")
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Delete(ctx, URL, c.getDefaultHeaders(), nil)
+	err = c.api.Delete(ctx, URL, c.getDefaultHeaders(), nil)
 	if err != nil {
 		log.WithFields(fields).Error("Error in DeleteVolume: " + err.Error())
 	} else {
@@ -719,8 +911,10 @@ func (c *Client) DeleteVolume(symID string, volumeID string) error {
 }
 
 // InitiateDeallocationOfTracksFromVolume is an asynchrnous operation (that returns a job) to remove tracks from a volume.
-func (c *Client) InitiateDeallocationOfTracksFromVolume(symID string, volumeID string) (*types.Job, error) {
+func (c *Client) InitiateDeallocationOfTracksFromVolume(symID string, volumeID string) (_ *types.Job, err error) {
 	defer c.TimeSpent("InitiateDeallocationOfTracksFromVolume", time.Now())
+	c, endSpan := c.traceOperation("InitiateDeallocationOfTracksFromVolume", AttributeSymmetrixID, symID, AttributeVolumeID, volumeID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -744,7 +938,7 @@ func (c *Client) InitiateDeallocationOfTracksFromVolume(symID string, volumeID s
 	log.WithFields(fields).Info("Initiating track deletion...")
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Put(ctx, URL, c.getDefaultHeaders(), payload, job)
+	err = c.api.Put(ctx, URL, c.getDefaultHeaders(), payload, job)
 	if err != nil {
 		log.WithFields(fields).Error("Error in InitiateDellocationOfTracksFromVolume: " + err.Error())
 		return nil, err
@@ -754,8 +948,10 @@ func (c *Client) InitiateDeallocationOfTracksFromVolume(symID string, volumeID s
 
 // GetPortGroupList returns a PortGroupList object, which contains a list of the Port Groups
 // which can be optionally filtered based on type
-func (c *Client) GetPortGroupList(symid string, portGroupType string) (*types.PortGroupList, error) {
+func (c *Client) GetPortGroupList(symid string, portGroupType string) (_ *types.PortGroupList, err error) {
 	defer c.TimeSpent("GetPortGroupList", time.Now())
+	c, endSpan := c.traceOperation("GetPortGroupList", AttributeSymmetrixID, symid)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symid); err != nil {
 		return nil, err
 	}
@@ -773,7 +969,7 @@ func (c *Client) GetPortGroupList(symid string, portGroupType string) (*types.Po
 
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), pgList)
+	err = c.api.Get(ctx, URL, c.getDefaultHeaders(), pgList)
 	if err != nil {
 		log.Error("GetPortGrouplList failed: " + err.Error())
 		return nil, err
@@ -782,8 +978,10 @@ func (c *Client) GetPortGroupList(symid string, portGroupType string) (*types.Po
 }
 
 // GetPortGroupByID returns a PortGroup given the Symmetrix ID and Port Group ID.
-func (c *Client) GetPortGroupByID(symID string, portGroupID string) (*types.PortGroup, error) {
+func (c *Client) GetPortGroupByID(symID string, portGroupID string) (_ *types.PortGroup, err error) {
 	defer c.TimeSpent("GetPortGroupByID", time.Now())
+	c, endSpan := c.traceOperation("GetPortGroupByID", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -791,7 +989,7 @@ func (c *Client) GetPortGroupByID(symID string, portGroupID string) (*types.Port
 	portGroup := &types.PortGroup{}
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), portGroup)
+	err = c.api.Get(ctx, URL, c.getDefaultHeaders(), portGroup)
 	if err != nil {
 		log.Error("GetPortGroupByID failed: " + err.Error())
 		return nil, err
@@ -801,33 +999,60 @@ func (c *Client) GetPortGroupByID(symID string, portGroupID string) (*types.Port
 
 // GetInitiatorList returns an InitiatorList object, which contains a list of all the Initiators.
 // initiatorHBA, isISCSI, inHost are optional arguments which act as filters for the initiator list
-func (c *Client) GetInitiatorList(symid string, initiatorHBA string, isISCSI bool, inHost bool) (*types.InitiatorList, error) {
+func (c *Client) GetInitiatorList(symid string, initiatorHBA string, isISCSI bool, inHost bool) (_ *types.InitiatorList, err error) {
 	defer c.TimeSpent("GetInitiatorList", time.Now())
-	if _, err := c.IsAllowedArray(symid); err != nil {
+	c, endSpan := c.traceOperation("GetInitiatorList", AttributeSymmetrixID, symid)
+	defer endSpan(&err)
+	filter := &types.InitiatorFilter{
+		InitiatorHBA: initiatorHBA,
+		ISCSI:        isISCSI,
+		InHost:       inHost,
+	}
+	return c.getInitiatorList(symid, "GetInitiatorList", filter)
+}
+
+// GetInitiatorListWithFilter returns an InitiatorList object containing the Initiators matching the filter.
+// In addition to the filters of GetInitiatorList, initiators can be selected by login and fabric status,
+// director (port) and host group.
+func (c *Client) GetInitiatorListWithFilter(symID string, filter *types.InitiatorFilter) (_ *types.InitiatorList, err error) {
+	defer c.TimeSpent("GetInitiatorListWithFilter", time.Now())
+	c, endSpan := c.traceOperation("GetInitiatorListWithFilter", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
+	return c.getInitiatorList(symID, "GetInitiatorListWithFilter", filter)
+}
+
+func (c *Client) getInitiatorList(symID string, functionName string, initFilter *types.InitiatorFilter) (*types.InitiatorList, error) {
+	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
-	filter := "?"
-	if inHost {
-		if len(filter) > 1 {
-			filter += "&"
-		}
-		filter += "in_a_host=true"
+	if initFilter == nil {
+		initFilter = &types.InitiatorFilter{}
 	}
-	if initiatorHBA != "" {
-		if len(filter) > 1 {
-			filter += "&"
-		}
-		filter = filter + "initiator_hba=" + initiatorHBA
+	filter := url.Values{}
+	if initFilter.InHost {
+		filter.Set("in_a_host", "true")
 	}
-	if isISCSI {
-		if len(filter) > 1 {
-			filter += "&"
-		}
-		filter += "iscsi=true"
+	if initFilter.InitiatorHBA != "" {
+		filter.Set("initiator_hba", initFilter.InitiatorHBA)
 	}
-	URL := c.urlPrefix() + SLOProvisioningX + SymmetrixX + symid + XInitiator
-	if len(filter) > 1 {
-		URL += filter
+	if initFilter.ISCSI {
+		filter.Set("iscsi", "true")
+	}
+	if initFilter.LoggedIn != nil {
+		filter.Set("logged_in", strconv.FormatBool(*initFilter.LoggedIn))
+	}
+	if initFilter.OnFabric != nil {
+		filter.Set("on_fabric", strconv.FormatBool(*initFilter.OnFabric))
+	}
+	if initFilter.DirectorPort != "" {
+		filter.Set("dir_port", initFilter.DirectorPort)
+	}
+	if initFilter.HostGroupID != "" {
+		filter.Set("host_group_id", initFilter.HostGroupID)
+	}
+	URL := c.urlPrefix() + SLOProvisioningX + SymmetrixX + symID + XInitiator
+	if len(filter) > 0 {
+		URL += "?" + filter.Encode()
 	}
 	initList := &types.InitiatorList{}
 
@@ -835,15 +1060,17 @@ func (c *Client) GetInitiatorList(symid string, initiatorHBA string, isISCSI boo
 	defer cancel()
 	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), initList)
 	if err != nil {
-		log.Error("GetInitiatorList failed: " + err.Error())
+		log.Error(functionName + " failed: " + err.Error())
 		return nil, err
 	}
 	return initList, nil
 }
 
 // GetInitiatorByID returns an Initiator given the Symmetrix ID and Initiator ID.
-func (c *Client) GetInitiatorByID(symID string, initID string) (*types.Initiator, error) {
+func (c *Client) GetInitiatorByID(symID string, initID string) (_ *types.Initiator, err error) {
 	defer c.TimeSpent("GetInitiatorByID", time.Now())
+	c, endSpan := c.traceOperation("GetInitiatorByID", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -851,7 +1078,7 @@ func (c *Client) GetInitiatorByID(symID string, initID string) (*types.Initiator
 	initiator := &types.Initiator{}
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), initiator)
+	err = c.api.Get(ctx, URL, c.getDefaultHeaders(), initiator)
 	if err != nil {
 		log.Error("GetInitiatorByID failed: " + err.Error())
 		return nil, err
@@ -859,9 +1086,77 @@ func (c *Client) GetInitiatorByID(symID string, initID string) (*types.Initiator
 	return initiator, nil
 }
 
+// SetInitiatorAlias sets the alias (node_name/port_name) of an initiator and returns the updated initiator
+func (c *Client) SetInitiatorAlias(symID string, initiatorID string, nodeName string, portName string) (_ *types.Initiator, err error) {
+	defer c.TimeSpent("SetInitiatorAlias", time.Now())
+	c, endSpan := c.traceOperation("SetInitiatorAlias", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	payload := &types.EditInitiatorParam{
+		EditInitiatorActionParam: &types.EditInitiatorActionParam{
+			RenameAliasParam: &types.RenameAliasParam{
+				NodeName: nodeName,
+				PortName: portName,
+			},
+		},
+		ExecutionOption: types.ExecutionOptionSynchronous,
+	}
+	ifDebugLogPayload(payload)
+	initiator := &types.Initiator{}
+
+	URL := c.urlPrefix() + SLOProvisioningX + SymmetrixX + symID + XInitiator + "/" + initiatorID
+	fields := map[string]interface{}{
+		http.MethodPut: URL,
+		"InitiatorID":  initiatorID,
+		"Alias":        nodeName + "/" + portName,
+	}
+	log.WithFields(fields).Info("Setting initiator alias")
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err = c.api.Put(ctx, URL, c.getDefaultHeaders(), payload, initiator)
+	if err != nil {
+		log.WithFields(fields).Error("Error in SetInitiatorAlias: " + err.Error())
+		return nil, err
+	}
+	log.Info(fmt.Sprintf("Successfully set alias of Initiator: %s", initiatorID))
+	return initiator, nil
+}
+
+// GetInitiatorsForHost returns the full Initiator records of all the initiators of a host.
+// A host initiator (HBA or IQN) has one record per array port it is zoned or connected to,
+// so the LoggedIn and OnFabric fields of the records show the paths available to the host.
+func (c *Client) GetInitiatorsForHost(symID string, hostID string) (_ []*types.Initiator, err error) {
+	defer c.TimeSpent("GetInitiatorsForHost", time.Now())
+	c, endSpan := c.traceOperation("GetInitiatorsForHost", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
+	host, err := c.GetHostByID(symID, hostID)
+	if err != nil {
+		return nil, err
+	}
+	initiators := make([]*types.Initiator, 0)
+	for _, hba := range host.Initiators {
+		initList, err := c.getInitiatorList(symID, "GetInitiatorsForHost", &types.InitiatorFilter{InitiatorHBA: hba})
+		if err != nil {
+			return nil, err
+		}
+		for _, initID := range initList.InitiatorIDs {
+			initiator, err := c.GetInitiatorByID(symID, initID)
+			if err != nil {
+				return nil, err
+			}
+			initiators = append(initiators, initiator)
+		}
+	}
+	return initiators, nil
+}
+
 // GetHostList returns an HostList object, which contains a list of all the Hosts.
-func (c *Client) GetHostList(symid string) (*types.HostList, error) {
+func (c *Client) GetHostList(symid string) (_ *types.HostList, err error) {
 	defer c.TimeSpent("GetHostList", time.Now())
+	c, endSpan := c.traceOperation("GetHostList", AttributeSymmetrixID, symid)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symid); err != nil {
 		return nil, err
 	}
@@ -869,7 +1164,7 @@ func (c *Client) GetHostList(symid string) (*types.HostList, error) {
 	hostList := &types.HostList{}
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), hostList)
+	err = c.api.Get(ctx, URL, c.getDefaultHeaders(), hostList)
 	if err != nil {
 		log.Error("GetHostList failed: " + err.Error())
 		return nil, err
@@ -878,8 +1173,10 @@ func (c *Client) GetHostList(symid string) (*types.HostList, error) {
 }
 
 // GetHostByID returns a Host given the Symmetrix ID and Host ID.
-func (c *Client) GetHostByID(symID string, hostID string) (*types.Host, error) {
+func (c *Client) GetHostByID(symID string, hostID string) (_ *types.Host, err error) {
 	defer c.TimeSpent("GetHostByID", time.Now())
+	c, endSpan := c.traceOperation("GetHostByID", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -887,7 +1184,7 @@ func (c *Client) GetHostByID(symID string, hostID string) (*types.Host, error) {
 	host := &types.Host{}
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), host)
+	err = c.api.Get(ctx, URL, c.getDefaultHeaders(), host)
 	if err != nil {
 		log.Error("GetHostByID failed: " + err.Error())
 		return nil, err
@@ -898,8 +1195,10 @@ func (c *Client) GetHostByID(symID string, hostID string) (*types.Host, error) {
 // CreateHost creates a host from a list of InitiatorIDs (and optional HostFlags) return returns a types.Host.
 // Initiator IDs do not contain the storage port designations, just the IQN string or FC WWN.
 // Initiator IDs cannot be a member of more than one host.
-func (c *Client) CreateHost(symID string, hostID string, initiatorIDs []string, hostFlags *types.HostFlags) (*types.Host, error) {
+func (c *Client) CreateHost(symID string, hostID string, initiatorIDs []string, hostFlags *types.HostFlags) (_ *types.Host, err error) {
 	defer c.TimeSpent("CreateHost", time.Now())
+	c, endSpan := c.traceOperation("CreateHost", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -910,12 +1209,11 @@ func (c *Client) CreateHost(symID string, hostID string, initiatorIDs []string,
 		ExecutionOption: types.ExecutionOptionSynchronous,
 	}
 	host := &types.Host{}
-	Debug = true
 	ifDebugLogPayload(hostParam)
 	URL := c.urlPrefix() + SLOProvisioningX + SymmetrixX + symID + XHost
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Post(ctx, URL, c.getDefaultHeaders(), hostParam, host)
+	err = c.api.Post(ctx, URL, c.getDefaultHeaders(), hostParam, host)
 	if err != nil {
 		log.Error("CreateHost failed: " + err.Error())
 		return nil, err
@@ -925,8 +1223,10 @@ func (c *Client) CreateHost(symID string, hostID string, initiatorIDs []string,
 }
 
 // UpdateHostInitiators updates a host from a list of InitiatorIDs and returns a types.Host.
-func (c *Client) UpdateHostInitiators(symID string, host *types.Host, initiatorIDs []string) (*types.Host, error) {
+func (c *Client) UpdateHostInitiators(symID string, host *types.Host, initiatorIDs []string) (_ *types.Host, err error) {
 	defer c.TimeSpent("UpdateHostInitiators", time.Now())
+	c, endSpan := c.traceOperation("UpdateHostInitiators", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -998,15 +1298,17 @@ func stringInSlice(a string, list []string) bool {
 }
 
 // DeleteHost deletes a host entry.
-func (c *Client) DeleteHost(symID string, hostID string) error {
+func (c *Client) DeleteHost(symID string, hostID string) (err error) {
 	defer c.TimeSpent("DeleteHost", time.Now())
+	c, endSpan := c.traceOperation("DeleteHost", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return err
 	}
 	URL := c.urlPrefix() + SLOProvisioningX + SymmetrixX + symID + XHost + "/" + hostID
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Delete(ctx, URL, c.getDefaultHeaders(), nil)
+	err = c.api.Delete(ctx, URL, c.getDefaultHeaders(), nil)
 	if err != nil {
 		log.Error("DeleteHost failed: " + err.Error())
 		return err
@@ -1016,26 +1318,60 @@ func (c *Client) DeleteHost(symID string, hostID string) error {
 }
 
 // GetMaskingViewList  returns a list of the MaskingView names.
-func (c *Client) GetMaskingViewList(symid string) (*types.MaskingViewList, error) {
+func (c *Client) GetMaskingViewList(symid string) (_ *types.MaskingViewList, err error) {
 	defer c.TimeSpent("GetMaskingViewList", time.Now())
-	if _, err := c.IsAllowedArray(symid); err != nil {
+	c, endSpan := c.traceOperation("GetMaskingViewList", AttributeSymmetrixID, symid)
+	defer endSpan(&err)
+	return c.getMaskingViewList(symid, "GetMaskingViewList", "")
+}
+
+// GetMaskingViewsForHost returns the names of the MaskingViews which contain the given host or host group.
+// The filtering is done by Unisphere, so only the matching views are returned.
+func (c *Client) GetMaskingViewsForHost(symID string, hostOrHostGroupID string) (_ *types.MaskingViewList, err error) {
+	defer c.TimeSpent("GetMaskingViewsForHost", time.Now())
+	c, endSpan := c.traceOperation("GetMaskingViewsForHost", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
+	return c.getMaskingViewList(symID, "GetMaskingViewsForHost", "?"+url.Values{"host_or_host_group_name": {hostOrHostGroupID}}.Encode())
+}
+
+// GetMaskingViewsForStorageGroup returns the names of the MaskingViews which contain the given storage group.
+func (c *Client) GetMaskingViewsForStorageGroup(symID string, storageGroupID string) (_ *types.MaskingViewList, err error) {
+	defer c.TimeSpent("GetMaskingViewsForStorageGroup", time.Now())
+	c, endSpan := c.traceOperation("GetMaskingViewsForStorageGroup", AttributeSymmetrixID, symID, AttributeStorageGroupID, storageGroupID)
+	defer endSpan(&err)
+	return c.getMaskingViewList(symID, "GetMaskingViewsForStorageGroup", "?"+url.Values{"storage_group_name": {storageGroupID}}.Encode())
+}
+
+// GetMaskingViewsForPortGroup returns the names of the MaskingViews which contain the given port group.
+func (c *Client) GetMaskingViewsForPortGroup(symID string, portGroupID string) (_ *types.MaskingViewList, err error) {
+	defer c.TimeSpent("GetMaskingViewsForPortGroup", time.Now())
+	c, endSpan := c.traceOperation("GetMaskingViewsForPortGroup", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
+	return c.getMaskingViewList(symID, "GetMaskingViewsForPortGroup", "?"+url.Values{"port_group_name": {portGroupID}}.Encode())
+}
+
+// getMaskingViewList fetches the masking view list, optionally narrowed down by a query filter.
+func (c *Client) getMaskingViewList(symID string, functionName string, filter string) (*types.MaskingViewList, error) {
+	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
-	URL := c.urlPrefix() + SLOProvisioningX + SymmetrixX + symid + XMaskingView
+	URL := c.urlPrefix() + SLOProvisioningX + SymmetrixX + symID + XMaskingView + filter
 	mvList := &types.MaskingViewList{}
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
 	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), mvList)
 	if err != nil {
-		log.Error("GetMaskingViewList failed: " + err.Error())
+		log.Error(functionName + " failed: " + err.Error())
 		return nil, err
 	}
 	return mvList, nil
 }
 
 // GetMaskingViewByID returns a masking view given it's identifier (which is the name)
-func (c *Client) GetMaskingViewByID(symid string, maskingViewID string) (*types.MaskingView, error) {
+func (c *Client) GetMaskingViewByID(symid string, maskingViewID string) (_ *types.MaskingView, err error) {
 	defer c.TimeSpent("GetMaskingViewByID", time.Now())
+	c, endSpan := c.traceOperation("GetMaskingViewByID", AttributeSymmetrixID, symid)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symid); err != nil {
 		return nil, err
 	}
@@ -1043,7 +1379,7 @@ func (c *Client) GetMaskingViewByID(symid string, maskingViewID string) (*types.
 	mv := &types.MaskingView{}
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), mv)
+	err = c.api.Get(ctx, URL, c.getDefaultHeaders(), mv)
 	if err != nil {
 		log.Error("GetMaskingViewByID failed: " + err.Error())
 		return nil, err
@@ -1053,8 +1389,10 @@ func (c *Client) GetMaskingViewByID(symid string, maskingViewID string) (*types.
 
 // GetMaskingViewConnections returns the connections of a masking view (optionally for a specific volume id.)
 // Here volume id is the 5 digit volume ID.
-func (c *Client) GetMaskingViewConnections(symid string, maskingViewID string, volumeID string) ([]*types.MaskingViewConnection, error) {
+func (c *Client) GetMaskingViewConnections(symid string, maskingViewID string, volumeID string) (_ []*types.MaskingViewConnection, err error) {
 	defer c.TimeSpent("GetMaskingViewConnections", time.Now())
+	c, endSpan := c.traceOperation("GetMaskingViewConnections", AttributeSymmetrixID, symid, AttributeVolumeID, volumeID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symid); err != nil {
 		return nil, err
 	}
@@ -1065,7 +1403,7 @@ func (c *Client) GetMaskingViewConnections(symid string, maskingViewID string, v
 	cn := &types.MaskingViewConnectionsResult{}
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), cn)
+	err = c.api.Get(ctx, URL, c.getDefaultHeaders(), cn)
 	if err != nil {
 		log.Error("GetMaskingViewConnections failed: " + err.Error())
 		return nil, err
@@ -1073,9 +1411,104 @@ func (c *Client) GetMaskingViewConnections(symid string, maskingViewID string, v
 	return cn.MaskingViewConnections, nil
 }
 
+// CreateISCSIPortGroup creates a port group containing the ports which expose the given iSCSI target IQNs.
+// The IQNs are resolved to director ports using GetISCSITargets.
+func (c *Client) CreateISCSIPortGroup(symID string, portGroupID string, targetIQNs []string) (_ *types.PortGroup, err error) {
+	defer c.TimeSpent("CreateISCSIPortGroup", time.Now())
+	c, endSpan := c.traceOperation("CreateISCSIPortGroup", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if len(targetIQNs) == 0 {
+		return nil, fmt.Errorf("At least one iSCSI target is required to create Port Group %s", portGroupID)
+	}
+	targets, err := c.GetISCSITargets(symID)
+	if err != nil {
+		return nil, err
+	}
+	iqnToPortKey := make(map[string]types.PortKey)
+	for _, target := range targets {
+		iqnToPortKey[target.IQN] = target.DirectorPort
+	}
+	dirPorts := make([]types.PortKey, 0)
+	for _, iqn := range targetIQNs {
+		portKey, ok := iqnToPortKey[iqn]
+		if !ok {
+			return nil, fmt.Errorf("iSCSI target %s not found on array %s", iqn, symID)
+		}
+		dirPorts = append(dirPorts, portKey)
+	}
+	return c.CreatePortGroup(symID, portGroupID, dirPorts)
+}
+
+// GetISCSITargetsForPortGroup returns the iSCSI targets exposed by the ports of a port group.
+// Ports which are not iSCSI targets are skipped.
+func (c *Client) GetISCSITargetsForPortGroup(symID string, portGroupID string) (_ []types.ISCSITarget, err error) {
+	defer c.TimeSpent("GetISCSITargetsForPortGroup", time.Now())
+	c, endSpan := c.traceOperation("GetISCSITargetsForPortGroup", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
+	pg, err := c.GetPortGroupByID(symID, portGroupID)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]types.ISCSITarget, 0)
+	for _, key := range pg.SymmetrixPortKey {
+		portID := portNumber(key.PortID)
+		port, err := c.GetPort(symID, key.DirectorID, portID)
+		if err != nil {
+			return nil, err
+		}
+		if !port.SymmetrixPort.ISCSITarget {
+			continue
+		}
+		targets = append(targets, newISCSITarget(key.DirectorID, portID, port))
+	}
+	return targets, nil
+}
+
+// RenameMaskingView renames a masking view and returns the updated masking view object
+func (c *Client) RenameMaskingView(symID string, maskingViewID string, newName string) (_ *types.MaskingView, err error) {
+	defer c.TimeSpent("RenameMaskingView", time.Now())
+	c, endSpan := c.traceOperation("RenameMaskingView", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	payload := &types.EditMaskingViewParam{
+		EditMaskingViewActionParam: &types.EditMaskingViewActionParam{
+			RenameMaskingViewParam: &types.RenameMaskingViewParam{
+				NewMaskingViewName: newName,
+			},
+		},
+		ExecutionOption: types.ExecutionOptionSynchronous,
+	}
+	ifDebugLogPayload(payload)
+	maskingView := &types.MaskingView{}
+
+	URL := c.urlPrefix() + SLOProvisioningX + SymmetrixX + symID + XMaskingView + "/" + maskingViewID
+	fields := map[string]interface{}{
+		http.MethodPut:  URL,
+		"MaskingViewID": maskingViewID,
+		"NewName":       newName,
+	}
+	log.WithFields(fields).Info("Renaming masking view")
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err = c.api.Put(ctx, URL, c.getDefaultHeaders(), payload, maskingView)
+	if err != nil {
+		log.WithFields(fields).Error("Error in RenameMaskingView: " + err.Error())
+		return nil, err
+	}
+	log.Info(fmt.Sprintf("Successfully renamed Masking View: %s to %s", maskingViewID, newName))
+	return maskingView, nil
+}
+
 // CreatePortGroup - Creates a Port Group
-func (c *Client) CreatePortGroup(symID string, portGroupID string, dirPorts []types.PortKey) (*types.PortGroup, error) {
+func (c *Client) CreatePortGroup(symID string, portGroupID string, dirPorts []types.PortKey) (_ *types.PortGroup, err error) {
 	defer c.TimeSpent("CreatePortGroup", time.Now())
+	c, endSpan := c.traceOperation("CreatePortGroup", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -1089,7 +1522,7 @@ func (c *Client) CreatePortGroup(symID string, portGroupID string, dirPorts []ty
 	portGroup := &types.PortGroup{}
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Post(ctx, URL, c.getDefaultHeaders(), createPortGroupParams, portGroup)
+	err = c.api.Post(ctx, URL, c.getDefaultHeaders(), createPortGroupParams, portGroup)
 	if err != nil {
 		log.Error("CreatePortGroup failed: " + err.Error())
 		return nil, err
@@ -1099,8 +1532,10 @@ func (c *Client) CreatePortGroup(symID string, portGroupID string, dirPorts []ty
 }
 
 // CreateMaskingView creates a masking view and returns the masking view object
-func (c *Client) CreateMaskingView(symID string, maskingViewID string, storageGroupID string, hostOrhostGroupID string, isHost bool, portGroupID string) (*types.MaskingView, error) {
+func (c *Client) CreateMaskingView(symID string, maskingViewID string, storageGroupID string, hostOrhostGroupID string, isHost bool, portGroupID string) (_ *types.MaskingView, err error) {
 	defer c.TimeSpent("CreateMaskingView", time.Now())
+	c, endSpan := c.traceOperation("CreateMaskingView", AttributeSymmetrixID, symID, AttributeStorageGroupID, storageGroupID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
@@ -1135,7 +1570,7 @@ func (c *Client) CreateMaskingView(symID string, maskingViewID string, storageGr
 	maskingView := &types.MaskingView{}
 	ctx, cancel := GetTimeoutContext()
 	defer cancel()
-	err := c.api.Post(ctx, URL, c.getDefaultHeaders(), createMaskingViewParam, maskingView)
+	err = c.api.Post(ctx, URL, c.getDefaultHeaders(), createMaskingViewParam, maskingView)
 	if err != nil {
 		log.Error("CreateMaskingView failed: " + err.Error())
 		return nil, err
@@ -1144,10 +1579,12 @@ func (c *Client) CreateMaskingView(symID string, maskingViewID string, storageGr
 	return maskingView, nil
 }
 
-func (c *Client) DeletePortGroup(symID string, portGroupID string) error {
+func (c *Client) DeletePortGroup(symID string, portGroupID string) (err error) {
+	c, endSpan := c.traceOperation("DeletePortGroup", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
 	URL := c.urlPrefix() + SLOProvisioningX + SymmetrixX + symID + XPortGroup + "/" + portGroupID
 
-	err := c.api.Delete(context.Background(), URL, c.getDefaultHeaders(), nil)
+	err = c.api.Delete(context.Background(), URL, c.getDefaultHeaders(), nil)
 	if err != nil {
 		log.Error("DeletePortGroup failed: " + err.Error())
 		return err
@@ -1160,7 +1597,9 @@ func (c *Client) DeletePortGroup(symID string, portGroupID string) error {
 // NB: based on the passed in 'ports' the implementation will determine how to update
 // the PortGroup and make appropriate REST calls sequentially. Take this into
 // consideration when making parallel calls.
-func (c *Client) UpdatePortGroup(symID string, portGroupID string, ports []types.PortKey) (*types.PortGroup, error) {
+func (c *Client) UpdatePortGroup(symID string, portGroupID string, ports []types.PortKey) (_ *types.PortGroup, err error) {
+	c, endSpan := c.traceOperation("UpdatePortGroup", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
 	URL := c.urlPrefix() + SLOProvisioningX + SymmetrixX + symID + XPortGroup + "/" + portGroupID
 	fmt.Println(URL)
 
@@ -1184,20 +1623,12 @@ func (c *Client) UpdatePortGroup(symID string, portGroupID string, ports []types
 		return nil, err
 	}
 
-	portIdRegex, _ := regexp.Compile("\\w+:(\\d+)")
-
 	// Create map of string "<DIRECTOR ID>/<PORT ID>" to a SymmetrixPortKeyType object based on what's found
 	// in the PortGroup
 	pgPorts := make(map[string]*types.SymmetrixPortKeyType)
 	for _, p := range pg.SymmetrixPortKey {
 		director := strings.ToUpper(p.DirectorID)
-		// PortID string may come as a combination of directory + port_number
-		// Extract just the port_number part
-		port := strings.ToLower(p.PortID)
-		submatch := portIdRegex.FindAllStringSubmatch(port, -1)
-		if len(submatch) > 0 {
-			port = submatch[0][1]
-		}
+		port := portNumber(strings.ToLower(p.PortID))
 		key := fmt.Sprintf("%s/%s", director, port)
 		pgPorts[key] = &types.SymmetrixPortKeyType{
 			DirectorID: director,
@@ -1257,3 +1688,134 @@ func (c *Client) UpdatePortGroup(symID string, portGroupID string, ports []types
 
 	return pg, nil
 }
+
+// RenamePortGroup renames a port group and returns the updated port group object
+func (c *Client) RenamePortGroup(symID string, portGroupID string, newName string) (_ *types.PortGroup, err error) {
+	defer c.TimeSpent("RenamePortGroup", time.Now())
+	c, endSpan := c.traceOperation("RenamePortGroup", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
+	edit := &types.EditPortGroupActionParam{
+		RenamePortGroupParam: &types.RenamePortGroupParam{
+			NewPortGroupName: newName,
+		},
+	}
+	pg, err := c.editPortGroup(symID, portGroupID, "RenamePortGroup", edit)
+	if err != nil {
+		return nil, err
+	}
+	log.Info(fmt.Sprintf("Successfully renamed Port Group: %s to %s", portGroupID, newName))
+	return pg, nil
+}
+
+// AddPortsToPortGroup adds the given dir/port ids to a port group, leaving the existing ports in place
+func (c *Client) AddPortsToPortGroup(symID string, portGroupID string, ports []types.PortKey) (_ *types.PortGroup, err error) {
+	defer c.TimeSpent("AddPortsToPortGroup", time.Now())
+	c, endSpan := c.traceOperation("AddPortsToPortGroup", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
+	edit := &types.EditPortGroupActionParam{
+		AddPortParam: &types.AddPortParam{
+			Ports: toSymmetrixPortKeys(ports),
+		},
+	}
+	pg, err := c.editPortGroup(symID, portGroupID, "AddPortsToPortGroup", edit)
+	if err != nil {
+		return nil, err
+	}
+	log.Info(fmt.Sprintf("Successfully added ports %v to Port Group: %s", ports, portGroupID))
+	return pg, nil
+}
+
+// RemovePortsFromPortGroup removes the given dir/port ids from a port group
+func (c *Client) RemovePortsFromPortGroup(symID string, portGroupID string, ports []types.PortKey) (_ *types.PortGroup, err error) {
+	defer c.TimeSpent("RemovePortsFromPortGroup", time.Now())
+	c, endSpan := c.traceOperation("RemovePortsFromPortGroup", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
+	edit := &types.EditPortGroupActionParam{
+		RemovePortParam: &types.RemovePortParam{
+			Ports: toSymmetrixPortKeys(ports),
+		},
+	}
+	pg, err := c.editPortGroup(symID, portGroupID, "RemovePortsFromPortGroup", edit)
+	if err != nil {
+		return nil, err
+	}
+	log.Info(fmt.Sprintf("Successfully removed ports %v from Port Group: %s", ports, portGroupID))
+	return pg, nil
+}
+
+// editPortGroup sends a single edit action for a port group
+func (c *Client) editPortGroup(symID string, portGroupID string, functionName string, edit *types.EditPortGroupActionParam) (*types.PortGroup, error) {
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	payload := &types.EditPortGroup{
+		EditPortGroupActionParam: edit,
+		ExecutionOption:          types.ExecutionOptionSynchronous,
+	}
+	ifDebugLogPayload(payload)
+	pg := &types.PortGroup{}
+
+	URL := c.urlPrefix() + SLOProvisioningX + SymmetrixX + symID + XPortGroup + "/" + portGroupID
+	fields := map[string]interface{}{
+		http.MethodPut: URL,
+		"PortGroupID":  portGroupID,
+	}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err := c.api.Put(ctx, URL, c.getDefaultHeaders(), payload, pg)
+	if err != nil {
+		log.WithFields(fields).Error(functionName + " failed: " + err.Error())
+		return nil, err
+	}
+	return pg, nil
+}
+
+// GetPortGroupDetails returns a port group along with the port details (type, identifier,
+// status) of each of its member ports.
+func (c *Client) GetPortGroupDetails(symID string, portGroupID string) (_ *types.PortGroupDetails, err error) {
+	defer c.TimeSpent("GetPortGroupDetails", time.Now())
+	c, endSpan := c.traceOperation("GetPortGroupDetails", AttributeSymmetrixID, symID)
+	defer endSpan(&err)
+	pg, err := c.GetPortGroupByID(symID, portGroupID)
+	if err != nil {
+		return nil, err
+	}
+	details := &types.PortGroupDetails{
+		PortGroup: pg,
+		Ports:     make([]*types.Port, 0),
+	}
+	for _, key := range pg.SymmetrixPortKey {
+		port, err := c.GetPort(symID, key.DirectorID, portNumber(key.PortID))
+		if err != nil {
+			log.Error(fmt.Sprintf("GetPortGroupDetails failed for port %s:%s: %s", key.DirectorID, key.PortID, err.Error()))
+			return nil, err
+		}
+		details.Ports = append(details.Ports, port)
+	}
+	return details, nil
+}
+
+// toSymmetrixPortKeys converts PortKeys to the SymmetrixPortKeyType used by the port group edit payloads
+func toSymmetrixPortKeys(ports []types.PortKey) []types.SymmetrixPortKeyType {
+	keys := make([]types.SymmetrixPortKeyType, 0)
+	for _, port := range ports {
+		keys = append(keys, types.SymmetrixPortKeyType{
+			DirectorID: strings.ToUpper(port.DirectorID),
+			PortID:     portNumber(strings.ToLower(port.PortID)),
+		})
+	}
+	return keys
+}
+
+// portIDRegex matches a PortID given as a combination of director + port_number
+var portIDRegex = regexp.MustCompile("\\w+:(\\d+)")
+
+// portNumber returns just the port_number part of a PortID, as the PortID
+// string may come as a combination of director + port_number
+func portNumber(portID string) string {
+	submatch := portIDRegex.FindAllStringSubmatch(portID, -1)
+	if len(submatch) > 0 {
+		return submatch[0][1]
+	}
+	return portID
+}
@@ -0,0 +1,182 @@
+/*
+ Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+package pmax
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	types "github.com/dell/gopowermax/types/v90"
+	log "github.com/sirupsen/logrus"
+)
+
+// CollectSnapshots finds the snapshot generations of the volumes of the array matching the policy and deletes them,
+// or only reports them in dry run mode. The generations are deleted oldest first, so that the generation numbers of
+// the remaining generations of a snapshot do not change, and their links in copy mode which finished copying are
+// unlinked first. Generations with links still depending on the snapshot data and secure generations which have not
+// expired are reported as skipped. If the snapshots cannot be listed, the report of the volumes listed so far is
+// returned with the error
+func (c *Client) CollectSnapshots(symID string, policy *types.SnapshotGCPolicy) (*types.SnapshotGCReport, error) {
+	defer c.TimeSpent("CollectSnapshots", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if policy == nil || !(policy.Expired || policy.MaxAge > 0 || policy.Orphaned || policy.DeletedVolumePrefix != "") {
+		return nil, fmt.Errorf("a snapshot collection policy selecting expired, old, orphaned or deleted volume snapshots is required")
+	}
+	if policy.Orphaned && policy.NamePrefix == "" && policy.MinAge <= 0 {
+		return nil, fmt.Errorf("orphaned snapshots can only be collected with a name prefix or a minimum age")
+	}
+	report := &types.SnapshotGCReport{DryRun: policy.DryRun}
+	snapVolumeList, err := c.GetSnapVolumeList(symID, nil)
+	if err != nil {
+		return report, err
+	}
+	var policySnapshots []string
+	if policy.Orphaned {
+		if policySnapshots, err = c.getPolicySnapshotNames(symID); err != nil {
+			return report, err
+		}
+	}
+	now := time.Now()
+	for _, volumeID := range snapVolumeList.Name {
+		candidates, err := c.findSnapshotGCCandidates(symID, volumeID, policy, policySnapshots, now)
+		if err != nil {
+			return report, err
+		}
+		report.Candidates = append(report.Candidates, candidates...)
+	}
+	sort.SliceStable(report.Candidates, func(i, j int) bool {
+		a, b := report.Candidates[i], report.Candidates[j]
+		if a.VolumeID != b.VolumeID {
+			return a.VolumeID < b.VolumeID
+		}
+		if a.SnapshotName != b.SnapshotName {
+			return a.SnapshotName < b.SnapshotName
+		}
+		return a.Generation > b.Generation
+	})
+	if policy.DryRun {
+		log.Info(fmt.Sprintf("Snapshot collection dry run found %d snapshot generations", len(report.Candidates)))
+		return report, nil
+	}
+	deletions := 0
+	for i := range report.Candidates {
+		candidate := &report.Candidates[i]
+		if candidate.Skipped != "" {
+			continue
+		}
+		if policy.MaxDeletions > 0 && deletions == policy.MaxDeletions {
+			candidate.Skipped = types.SnapshotGCReasonRateLimited
+			continue
+		}
+		if deletions > 0 && policy.DeleteInterval > 0 {
+			time.Sleep(policy.DeleteInterval)
+		}
+		deletions++
+		if err := c.deleteSnapshotGCCandidate(symID, candidate); err != nil {
+			candidate.Error = err.Error()
+			continue
+		}
+		candidate.Deleted = true
+	}
+	log.Info(fmt.Sprintf("Snapshot collection deleted %d snapshot generations, %d failed", report.DeletedCount(), report.FailedCount()))
+	return report, nil
+}
+
+// getPolicySnapshotNames returns the names of the snapshots taken by the snapshot policies of the array,
+// which are named after their policy, or none if the API version of the client predates snapshot policies
+func (c *Client) getPolicySnapshotNames(symID string) ([]string, error) {
+	if c.supportsSnapshotPolicies() != nil {
+		return []string{}, nil
+	}
+	snapshotPolicyList, err := c.GetSnapshotPolicyList(symID)
+	if err != nil {
+		return nil, err
+	}
+	return snapshotPolicyList.SnapshotPolicyIDs, nil
+}
+
+// findSnapshotGCCandidates returns the snapshot generations of a volume matching the policy.
+// A generation is orphaned when no volume is linked to it and it was not taken by a snapshot policy
+func (c *Client) findSnapshotGCCandidates(symID, volumeID string, policy *types.SnapshotGCPolicy, policySnapshots []string, now time.Time) ([]types.SnapshotGCCandidate, error) {
+	generations, err := c.GetVolumeSnapshotGenerations(symID, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	deletedVolume := false
+	if policy.DeletedVolumePrefix != "" {
+		volume, err := c.GetVolumeByID(symID, volumeID)
+		if err != nil {
+			return nil, err
+		}
+		deletedVolume = strings.HasPrefix(volume.VolumeIdentifier, policy.DeletedVolumePrefix)
+	}
+	candidates := make([]types.SnapshotGCCandidate, 0)
+	for _, generation := range generations {
+		if !strings.HasPrefix(generation.SnapshotName, policy.NamePrefix) {
+			continue
+		}
+		known := !generation.Timestamp.IsZero()
+		age := now.Sub(generation.Timestamp)
+		if policy.MinAge > 0 && (!known || age < policy.MinAge) {
+			continue
+		}
+		reason := ""
+		switch {
+		case policy.Expired && generation.Expired:
+			reason = types.SnapshotGCReasonExpired
+		case policy.MaxAge > 0 && known && age >= policy.MaxAge:
+			reason = types.SnapshotGCReasonTooOld
+		case deletedVolume:
+			reason = types.SnapshotGCReasonDeletedVolume
+		case policy.Orphaned && !generation.IsLinked() && !stringInSlice(generation.SnapshotName, policySnapshots):
+			reason = types.SnapshotGCReasonOrphaned
+		default:
+			continue
+		}
+		candidate := types.SnapshotGCCandidate{
+			VolumeID:     volumeID,
+			SnapshotName: generation.SnapshotName,
+			Generation:   generation.Generation,
+			CreationTime: generation.Timestamp,
+			Reason:       reason,
+		}
+		for _, target := range generation.Targets {
+			candidate.LinkedVolumes = append(candidate.LinkedVolumes, target.VolumeID)
+			if target.State != types.SnapshotLinkStateCopied {
+				candidate.Skipped = types.SnapshotGCReasonLinked
+			}
+		}
+		if generation.Secured && !generation.Expired {
+			candidate.Skipped = types.SnapshotGCReasonSecured
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+// deleteSnapshotGCCandidate unlinks the targets of a snapshot generation, then deletes it
+func (c *Client) deleteSnapshotGCCandidate(symID string, candidate *types.SnapshotGCCandidate) error {
+	sourceVolumes := []types.VolumeList{{Name: candidate.VolumeID}}
+	for _, target := range candidate.LinkedVolumes {
+		targetVolumes := []types.VolumeList{{Name: target}}
+		if err := c.ModifySnapshot(symID, sourceVolumes, targetVolumes, candidate.SnapshotName, "Unlink", "", candidate.Generation); err != nil {
+			return err
+		}
+	}
+	return c.DeleteSnapshot(symID, candidate.SnapshotName, sourceVolumes, candidate.Generation)
+}
@@ -0,0 +1,243 @@
+/*
+ Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+package pmax
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	types "github.com/dell/gopowermax/types/v90"
+	log "github.com/sirupsen/logrus"
+)
+
+// The following constants are for internal use within the pmax library.
+const (
+	XSnapshotPolicy     = "/snapshot_policy"
+	XSnapshotCompliance = "/compliance/snapshot"
+)
+
+// supportsSnapshotPolicies returns an error if the API version of the client predates snapshot policies
+func (c *Client) supportsSnapshotPolicies() error {
+	if c.version == APIVersion90 || c.version == APIVersion91 {
+		return fmt.Errorf("snapshot policies require API version %s or later, the client uses version %s", APIVersion92, c.version)
+	}
+	return nil
+}
+
+func (c *Client) snapshotPolicyURL(symID, snapshotPolicyID string) string {
+	URL := c.urlPrefix() + ReplicationX + SymmetrixX + symID + XSnapshotPolicy
+	if snapshotPolicyID != "" {
+		URL += "/" + snapshotPolicyID
+	}
+	return URL
+}
+
+// GetSnapshotPolicyList returns the names of the snapshot policies of the array
+func (c *Client) GetSnapshotPolicyList(symID string) (*types.SnapshotPolicyList, error) {
+	defer c.TimeSpent("GetSnapshotPolicyList", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if err := c.supportsSnapshotPolicies(); err != nil {
+		return nil, err
+	}
+	URL := c.snapshotPolicyURL(symID, "")
+	snapshotPolicyList := &types.SnapshotPolicyList{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), snapshotPolicyList)
+	if err != nil {
+		log.Error("GetSnapshotPolicyList failed: " + err.Error())
+		return nil, err
+	}
+	return snapshotPolicyList, nil
+}
+
+// GetSnapshotPolicy returns a snapshot policy
+func (c *Client) GetSnapshotPolicy(symID, snapshotPolicyID string) (*types.SnapshotPolicy, error) {
+	defer c.TimeSpent("GetSnapshotPolicy", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if err := c.supportsSnapshotPolicies(); err != nil {
+		return nil, err
+	}
+	URL := c.snapshotPolicyURL(symID, snapshotPolicyID)
+	snapshotPolicy := &types.SnapshotPolicy{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), snapshotPolicy)
+	if err != nil {
+		log.Error("GetSnapshotPolicy failed: " + err.Error())
+		return nil, err
+	}
+	return snapshotPolicy, nil
+}
+
+// CreateSnapshotPolicy creates a snapshot policy taking a snapshot every interval and keeping the last snapshotCount
+// snapshots. A secure snapshot policy takes secure snapshots, which cannot be deleted before they expire
+func (c *Client) CreateSnapshotPolicy(symID string, createParam *types.CreateSnapshotPolicyParam) (*types.SnapshotPolicy, error) {
+	defer c.TimeSpent("CreateSnapshotPolicy", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if err := c.supportsSnapshotPolicies(); err != nil {
+		return nil, err
+	}
+	ifDebugLogPayload(createParam)
+	URL := c.snapshotPolicyURL(symID, "")
+	snapshotPolicy := &types.SnapshotPolicy{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err := c.api.Post(ctx, URL, c.getDefaultHeaders(), createParam, snapshotPolicy)
+	if err != nil {
+		log.Error("CreateSnapshotPolicy failed: " + err.Error())
+		return nil, err
+	}
+	log.Info(fmt.Sprintf("Successfully created SnapshotPolicy: %s", createParam.SnapshotPolicyName))
+	return snapshotPolicy, nil
+}
+
+// ModifySnapshotPolicy changes the interval, offset, snapshot count, compliance thresholds or name of a snapshot policy
+func (c *Client) ModifySnapshotPolicy(symID, snapshotPolicyID string, modifyParam *types.ModifySnapshotPolicyParam) (*types.SnapshotPolicy, error) {
+	defer c.TimeSpent("ModifySnapshotPolicy", time.Now())
+	return c.updateSnapshotPolicy(symID, snapshotPolicyID, "ModifySnapshotPolicy", &types.UpdateSnapshotPolicyParam{
+		Action: types.SnapshotPolicyActionModify,
+		Modify: modifyParam,
+	})
+}
+
+// SuspendSnapshotPolicy stops a snapshot policy from taking snapshots of its storage groups
+func (c *Client) SuspendSnapshotPolicy(symID, snapshotPolicyID string) (*types.SnapshotPolicy, error) {
+	defer c.TimeSpent("SuspendSnapshotPolicy", time.Now())
+	return c.updateSnapshotPolicy(symID, snapshotPolicyID, "SuspendSnapshotPolicy", &types.UpdateSnapshotPolicyParam{
+		Action: types.SnapshotPolicyActionSuspend,
+	})
+}
+
+// ResumeSnapshotPolicy resumes taking snapshots with a suspended snapshot policy
+func (c *Client) ResumeSnapshotPolicy(symID, snapshotPolicyID string) (*types.SnapshotPolicy, error) {
+	defer c.TimeSpent("ResumeSnapshotPolicy", time.Now())
+	return c.updateSnapshotPolicy(symID, snapshotPolicyID, "ResumeSnapshotPolicy", &types.UpdateSnapshotPolicyParam{
+		Action: types.SnapshotPolicyActionResume,
+	})
+}
+
+// AssociateSnapshotPolicy associates storage groups to a snapshot policy, which then takes snapshots of them
+func (c *Client) AssociateSnapshotPolicy(symID, snapshotPolicyID string, storageGroupIDs ...string) (*types.SnapshotPolicy, error) {
+	defer c.TimeSpent("AssociateSnapshotPolicy", time.Now())
+	return c.updateSnapshotPolicy(symID, snapshotPolicyID, "AssociateSnapshotPolicy", &types.UpdateSnapshotPolicyParam{
+		Action:    types.SnapshotPolicyActionAssociate,
+		Associate: &types.SnapshotPolicyStorageGroupParam{StorageGroupName: storageGroupIDs},
+	})
+}
+
+// DisassociateSnapshotPolicy disassociates storage groups from a snapshot policy.
+// The snapshots already taken by the policy are kept
+func (c *Client) DisassociateSnapshotPolicy(symID, snapshotPolicyID string, storageGroupIDs ...string) (*types.SnapshotPolicy, error) {
+	defer c.TimeSpent("DisassociateSnapshotPolicy", time.Now())
+	return c.updateSnapshotPolicy(symID, snapshotPolicyID, "DisassociateSnapshotPolicy", &types.UpdateSnapshotPolicyParam{
+		Action:       types.SnapshotPolicyActionDisassociate,
+		Disassociate: &types.SnapshotPolicyStorageGroupParam{StorageGroupName: storageGroupIDs},
+	})
+}
+
+func (c *Client) updateSnapshotPolicy(symID, snapshotPolicyID, functionName string, updateParam *types.UpdateSnapshotPolicyParam) (*types.SnapshotPolicy, error) {
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if err := c.supportsSnapshotPolicies(); err != nil {
+		return nil, err
+	}
+	ifDebugLogPayload(updateParam)
+	URL := c.snapshotPolicyURL(symID, snapshotPolicyID)
+	fields := map[string]interface{}{
+		http.MethodPut: URL,
+		"Action":       updateParam.Action,
+	}
+	snapshotPolicy := &types.SnapshotPolicy{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err := c.api.Put(ctx, URL, c.getDefaultHeaders(), updateParam, snapshotPolicy)
+	if err != nil {
+		log.WithFields(fields).Error("Error in " + functionName + ": " + err.Error())
+		return nil, err
+	}
+	log.Info(fmt.Sprintf("Action (%s) on SnapshotPolicy (%s) is successful", updateParam.Action, snapshotPolicyID))
+	return snapshotPolicy, nil
+}
+
+// DeleteSnapshotPolicy deletes a snapshot policy which has no storage groups associated
+func (c *Client) DeleteSnapshotPolicy(symID, snapshotPolicyID string) error {
+	defer c.TimeSpent("DeleteSnapshotPolicy", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return err
+	}
+	if err := c.supportsSnapshotPolicies(); err != nil {
+		return err
+	}
+	URL := c.snapshotPolicyURL(symID, snapshotPolicyID)
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err := c.api.Delete(ctx, URL, c.getDefaultHeaders(), nil)
+	if err != nil {
+		log.Error("DeleteSnapshotPolicy failed: " + err.Error())
+		return err
+	}
+	log.Info(fmt.Sprintf("Successfully deleted SnapshotPolicy: %s", snapshotPolicyID))
+	return nil
+}
+
+// GetSnapshotPolicyStorageGroups returns the names of the storage groups associated to a snapshot policy
+func (c *Client) GetSnapshotPolicyStorageGroups(symID, snapshotPolicyID string) (*types.SnapshotPolicyStorageGroupList, error) {
+	defer c.TimeSpent("GetSnapshotPolicyStorageGroups", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if err := c.supportsSnapshotPolicies(); err != nil {
+		return nil, err
+	}
+	URL := c.snapshotPolicyURL(symID, snapshotPolicyID) + XStorageGroup
+	sgList := &types.SnapshotPolicyStorageGroupList{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), sgList)
+	if err != nil {
+		log.Error("GetSnapshotPolicyStorageGroups failed: " + err.Error())
+		return nil, err
+	}
+	return sgList, nil
+}
+
+// GetStorageGroupSnapshotCompliance returns the compliance of the snapshots of a storage group with its snapshot policies
+func (c *Client) GetStorageGroupSnapshotCompliance(symID, sgID string) (*types.StorageGroupSnapshotCompliance, error) {
+	defer c.TimeSpent("GetStorageGroupSnapshotCompliance", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if err := c.supportsSnapshotPolicies(); err != nil {
+		return nil, err
+	}
+	URL := c.urlPrefix() + ReplicationX + SymmetrixX + symID + XStorageGroup + "/" + sgID + XSnapshotCompliance
+	compliance := &types.StorageGroupSnapshotCompliance{}
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), compliance)
+	if err != nil {
+		log.Error("GetStorageGroupSnapshotCompliance failed: " + err.Error())
+		return nil, err
+	}
+	return compliance, nil
+}
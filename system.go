@@ -17,7 +17,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	types "github.com/dell/gopowermax/types/v90"
@@ -36,6 +38,8 @@ var (
 	MAXJobRetryCount = 30
 	// JobRetrySleepDuration is the amount of time between retries.
 	JobRetrySleepDuration = 3 * time.Second
+	// DirectorWorkers is the maximum number of directors whose details are fetched concurrently.
+	DirectorWorkers = 8
 )
 
 func (c *Client) urlPrefix() string {
@@ -153,8 +157,11 @@ func (c *Client) GetJobByID(symID string, jobID string) (*types.Job, error) {
 		err := c.api.Get(ctx, url, c.getDefaultHeaders(), job)
 		if err != nil {
 			if strings.Contains(err.Error(), "Cannot find role for user") {
-				log.Debug(fmt.Sprintf("Retrying GetJobs: %s", err.Error()))
-				time.Sleep(10 * time.Second)
+				if i+1 < maxRetry {
+					log.Debug(fmt.Sprintf("Retrying GetJobs: %s", err.Error()))
+					time.Sleep(10 * time.Second)
+					c.notifyRetry("GetJobByID", i+2, err)
+				}
 				continue
 			}
 			log.Error("GetJobs failed: " + err.Error())
@@ -167,15 +174,25 @@ func (c *Client) GetJobByID(symID string, jobID string) (*types.Job, error) {
 
 // WaitOnJobCompletion waits until a Job reaches a terminal state.
 // The state may be JobStatusSucceeded or JobStatusFailed (it is the caller's responsibility to check.)
-func (c *Client) WaitOnJobCompletion(symID string, jobID string) (*types.Job, error) {
+func (c *Client) WaitOnJobCompletion(symID string, jobID string) (job *types.Job, err error) {
+	c, endSpan := c.traceOperation("WaitOnJobCompletion", AttributeSymmetrixID, symID, AttributeJobID, jobID)
+	defer endSpan(&err)
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
+	wait := JobWaitEvent{SymmetrixID: symID, JobID: jobID}
+	defer func(start time.Time) {
+		wait.Duration = time.Since(start)
+		wait.Err = err
+		c.notifyJobWait(wait)
+	}(time.Now())
 	for i := 0; i < MAXJobRetryCount; i++ {
 		job, err := c.GetJobByID(symID, jobID)
 		if err != nil {
 			return nil, err
 		}
+		wait.Polls++
+		wait.Status = job.Status
 		log.Debug(c.JobToString(job))
 		switch job.Status {
 		case types.JobStatusSucceeded:
@@ -222,6 +239,79 @@ func (c *Client) GetDirectorIDList(symID string) (*types.DirectorIDList, error)
 	return directorList, nil
 }
 
+// GetDirector returns the details of a director
+func (c *Client) GetDirector(symID string, directorID string) (*types.Director, error) {
+	defer c.TimeSpent("GetDirector", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	director := &types.Director{}
+	URL := c.getSymmetrixIDListURL() + "/" + symID + "/director/" + directorID
+	ctx, cancel := GetTimeoutContext()
+	defer cancel()
+	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), director)
+	if err != nil {
+		log.Error("GetDirector failed: " + err.Error())
+		return nil, err
+	}
+	return director, nil
+}
+
+// ListDirectors returns the details of the directors matching the filter. A nil filter returns
+// all the directors. The type filters are applied to the director IDs first, so only the
+// details of the matching directors are fetched, at most DirectorWorkers at a time.
+func (c *Client) ListDirectors(symID string, filter *types.DirectorFilter) ([]*types.Director, error) {
+	defer c.TimeSpent("ListDirectors", time.Now())
+	if filter == nil {
+		filter = &types.DirectorFilter{}
+	}
+	directorList, err := c.GetDirectorIDList(symID)
+	if err != nil {
+		return nil, err
+	}
+	directorIDs := make([]string, 0)
+	for _, d := range directorList.DirectorIDs {
+		directorType := types.DirectorType(d)
+		if filter.FrontEndOnly && !types.IsFrontEndDirectorType(directorType) {
+			continue
+		}
+		if len(filter.Types) > 0 && !stringInSlice(directorType, filter.Types) {
+			continue
+		}
+		directorIDs = append(directorIDs, d)
+	}
+
+	type directorResult struct {
+		director *types.Director
+		err      error
+	}
+	results := make([]directorResult, len(directorIDs))
+	workers := make(chan struct{}, DirectorWorkers)
+	var wg sync.WaitGroup
+	for i, d := range directorIDs {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(i int, directorID string) {
+			defer wg.Done()
+			defer func() { <-workers }()
+			results[i].director, results[i].err = c.GetDirector(symID, directorID)
+		}(i, d)
+	}
+	wg.Wait()
+
+	directors := make([]*types.Director, 0)
+	for _, result := range results {
+		if result.err != nil {
+			return nil, result.err
+		}
+		if filter.OnlineOnly && !result.director.IsOnline() {
+			continue
+		}
+		directors = append(directors, result.director)
+	}
+	return directors, nil
+}
+
 // GetPortList returns a list of all the ports on a specified director/array.
 func (c *Client) GetPortList(symID string, directorID string, query string) (*types.PortList, error) {
 	if _, err := c.IsAllowedArray(symID); err != nil {
@@ -263,37 +353,202 @@ func (c *Client) GetPort(symID string, directorID string, portID string) (*types
 
 // GetListOfTargetAddresses returns list of target addresses
 func (c *Client) GetListOfTargetAddresses(symID string) ([]string, error) {
+	ipAddr := []string{}
+	targets, err := c.GetISCSITargets(symID)
+	if err != nil {
+		return []string{}, err
+	}
+	for _, target := range targets {
+		if len(target.PortalIPs) > 0 {
+			ipAddr = append(ipAddr, target.PortalIPs...)
+		}
+	}
+	return ipAddr, nil
+}
+
+// GetISCSITargets returns the iSCSI targets exposed by all the directors of an array.
+// The directors are scanned concurrently; the targets are returned in director order.
+func (c *Client) GetISCSITargets(symID string) ([]types.ISCSITarget, error) {
+	defer c.TimeSpent("GetISCSITargets", time.Now())
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
-	ipAddr := []string{}
 	// Get list of all directors
 	directors, err := c.GetDirectorIDList(symID)
 	if err != nil {
-		return []string{}, err
+		return nil, err
+	}
+	// get the details of all the ports with iscsi_target=true
+	ports, err := c.getPortsOnDirectors(symID, directors.DirectorIDs, "iscsi_target=true")
+	if err != nil {
+		return nil, err
 	}
+	targets := make([]types.ISCSITarget, 0)
+	for _, port := range ports {
+		key := port.SymmetrixPort.SymmetrixPortKey
+		targets = append(targets, newISCSITarget(key.DirectorID, key.PortID, port))
+	}
+	return targets, nil
+}
 
-	// for each director, get list of ports with iscsi_target=true
+// GetFrontEndPorts returns the details of the ports on the front end directors of an array
+// (FA, SE, EF, FN and OR directors) which match the filter. A nil filter returns all of them.
+// The directors are scanned concurrently.
+func (c *Client) GetFrontEndPorts(symID string, filter *types.PortFilter) ([]*types.Port, error) {
+	defer c.TimeSpent("GetFrontEndPorts", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	directors, err := c.GetDirectorIDList(symID)
+	if err != nil {
+		return nil, err
+	}
+	frontEndDirectors := make([]string, 0)
 	for _, d := range directors.DirectorIDs {
-		ports, err := c.GetPortList(symID, d, "iscsi_target=true")
-		if err != nil {
-			return []string{}, err
+		if types.IsFrontEndDirectorType(types.DirectorType(d)) {
+			frontEndDirectors = append(frontEndDirectors, d)
+		}
+	}
+	ports, err := c.getPortsOnDirectors(symID, frontEndDirectors, "")
+	if err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		return ports, nil
+	}
+	filtered := make([]*types.Port, 0)
+	for _, port := range ports {
+		if filter.Type != "" && !strings.EqualFold(port.SymmetrixPort.Type, filter.Type) {
+			continue
+		}
+		if filter.OnlineOnly && !port.SymmetrixPort.IsOnline() {
+			continue
 		}
+		filtered = append(filtered, port)
+	}
+	return filtered, nil
+}
 
-		// for each port, get the details
-		for _, p := range ports.SymmetrixPortKey {
-			port, err := c.GetPort(symID, d, p.PortID)
-			if err != nil {
-				return []string{}, err
-			}
-			if len(port.SymmetrixPort.IPAddresses) > 0 {
-				ipAddr = append(ipAddr, port.SymmetrixPort.IPAddresses...)
-			}
+// SelectLeastLoadedFCPorts returns up to count online Fibre Channel ports, least loaded first.
+// The load of a port is its number of mapped volumes, then its number of masking views.
+// Ports on different directors are preferred so the selection can be used to build a
+// highly available port group.
+func (c *Client) SelectLeastLoadedFCPorts(symID string, count int) ([]types.PortKey, error) {
+	defer c.TimeSpent("SelectLeastLoadedFCPorts", time.Now())
+	if count <= 0 {
+		return nil, fmt.Errorf("The number of ports to select must be positive, got %d", count)
+	}
+	ports, err := c.GetFrontEndPorts(symID, &types.PortFilter{
+		Type:       types.PortTypeFibreChannel,
+		OnlineOnly: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ports) == 0 {
+		return nil, fmt.Errorf("No online Fibre Channel ports found on array %s", symID)
+	}
+	sort.SliceStable(ports, func(i, j int) bool {
+		pi, pj := ports[i].SymmetrixPort, ports[j].SymmetrixPort
+		if pi.NumOfMappedVols != pj.NumOfMappedVols {
+			return pi.NumOfMappedVols < pj.NumOfMappedVols
+		}
+		return pi.NumOfMaskingViews < pj.NumOfMaskingViews
+	})
+
+	selected := make([]types.PortKey, 0)
+	used := make(map[int]bool)
+	usedDirectors := make(map[string]bool)
+	// first pass: at most one port per director
+	for i, port := range ports {
+		if len(selected) == count {
+			break
+		}
+		key := port.SymmetrixPort.SymmetrixPortKey
+		if usedDirectors[key.DirectorID] {
+			continue
+		}
+		selected = append(selected, key)
+		used[i] = true
+		usedDirectors[key.DirectorID] = true
+	}
+	// second pass: fill up with the remaining least loaded ports
+	for i, port := range ports {
+		if len(selected) == count {
+			break
+		}
+		if !used[i] {
+			selected = append(selected, port.SymmetrixPort.SymmetrixPortKey)
+		}
+	}
+	return selected, nil
+}
+
+// getPortsOnDirectors returns the details of the ports on the given directors, optionally
+// narrowed down by a port list query. At most DirectorWorkers directors are scanned concurrently
+// and the ports are returned in director order.
+func (c *Client) getPortsOnDirectors(symID string, directorIDs []string, query string) ([]*types.Port, error) {
+	type directorScan struct {
+		ports []*types.Port
+		err   error
+	}
+	scans := make([]directorScan, len(directorIDs))
+	workers := make(chan struct{}, DirectorWorkers)
+	var wg sync.WaitGroup
+	for i, d := range directorIDs {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(i int, directorID string) {
+			defer wg.Done()
+			defer func() { <-workers }()
+			scans[i].ports, scans[i].err = c.getPortsOnDirector(symID, directorID, query)
+		}(i, d)
+	}
+	wg.Wait()
 
+	ports := make([]*types.Port, 0)
+	for _, scan := range scans {
+		if scan.err != nil {
+			return nil, scan.err
 		}
+		ports = append(ports, scan.ports...)
 	}
+	return ports, nil
+}
 
-	return ipAddr, nil
+// getPortsOnDirector returns the details of the ports of a single director
+func (c *Client) getPortsOnDirector(symID string, directorID string, query string) ([]*types.Port, error) {
+	portList, err := c.GetPortList(symID, directorID, query)
+	if err != nil {
+		return nil, err
+	}
+	ports := make([]*types.Port, 0)
+	// for each port, get the details
+	for _, p := range portList.SymmetrixPortKey {
+		port, err := c.GetPort(symID, directorID, p.PortID)
+		if err != nil {
+			return nil, err
+		}
+		port.SymmetrixPort.SymmetrixPortKey = types.PortKey{
+			DirectorID: directorID,
+			PortID:     p.PortID,
+		}
+		ports = append(ports, port)
+	}
+	return ports, nil
+}
+
+// newISCSITarget builds an ISCSITarget from the port details
+func newISCSITarget(directorID string, portID string, port *types.Port) types.ISCSITarget {
+	return types.ISCSITarget{
+		IQN:       port.SymmetrixPort.Identifier,
+		PortalIPs: port.SymmetrixPort.IPAddresses,
+		NetworkID: port.SymmetrixPort.NetworkID,
+		DirectorPort: types.PortKey{
+			DirectorID: directorID,
+			PortID:     portID,
+		},
+	}
 }
 
 // SetAllowedArrays sets the list of arrays which can be manipulated
@@ -0,0 +1,201 @@
+/*
+ Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+package pmax
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dell/gopowermax/api"
+)
+
+// TraceParentHeader is the header of the W3C trace context sent with each request of a traced client
+const TraceParentHeader = "traceparent"
+
+// Attributes set on the spans of the client
+const (
+	AttributeSymmetrixID    = "pmax.symmetrix_id"
+	AttributeStorageGroupID = "pmax.storage_group_id"
+	AttributeVolumeID       = "pmax.volume_id"
+	AttributeSnapshotID     = "pmax.snapshot_id"
+	AttributeJobID          = "pmax.job_id"
+	AttributeHTTPMethod     = "http.method"
+	AttributeHTTPURL        = "http.url"
+	AttributeHTTPRoute      = "http.route"
+	AttributeHTTPStatusCode = "http.status_code"
+)
+
+// Tracer starts the spans of a client. It can be implemented on top of OpenTelemetry,
+// OpenTracing or any other tracing library, and must be safe for concurrent use
+type Tracer interface {
+	// StartSpan starts a span, child of the span of the context if any,
+	// and returns a context carrying the new span
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is an operation traced by a Tracer
+type Span interface {
+	// SetAttribute sets an attribute of the span
+	SetAttribute(key string, value interface{})
+	// TraceParent returns the W3C traceparent header value identifying the span,
+	// or an empty string if the trace context must not be propagated
+	TraceParent() string
+	// End ends the span, err is the error of the operation if it failed
+	End(err error)
+}
+
+// SetTracer sets the tracer of the spans of the client, nil disables the tracing.
+// Each function of the client is traced as a span, parent of the spans of its API requests
+func (c *Client) SetTracer(tracer Tracer) {
+	c.hooks.mutex.Lock()
+	defer c.hooks.mutex.Unlock()
+	c.hooks.tracer = tracer
+}
+
+// WithContext returns a copy of the client whose spans are children of the span of the context,
+// the spans of a client not returned by WithContext are root spans. The copy shares the connection,
+// the observers and the tracer of the client, and can be used concurrently like the client
+func (c *Client) WithContext(ctx context.Context) Pmax {
+	return c.withTraceContext(ctx)
+}
+
+// withTraceContext returns a copy of the client whose spans and requests are children of the span of the context
+func (c *Client) withTraceContext(ctx context.Context) *Client {
+	bound := *c
+	bound.traceContext = ctx
+	rawAPI := c.api
+	if traced, ok := rawAPI.(*tracingAPI); ok {
+		rawAPI = traced.Client
+	}
+	bound.api = &tracingAPI{Client: rawAPI, hooks: c.hooks, parent: ctx}
+	return &bound
+}
+
+// traceParent returns the context of the parent of the spans of the client
+func (c *Client) traceParent() context.Context {
+	if c.traceContext == nil {
+		return context.Background()
+	}
+	return c.traceContext
+}
+
+// traceOperation starts the span of a function of the client with the attributes given as key value pairs.
+// It returns the client the function must use from then on, so that the functions and requests it calls
+// are children of its span, and the function ending the span with the error returned by the function:
+//
+//	c, endSpan := c.traceOperation(...)
+//	defer endSpan(&err)
+func (c *Client) traceOperation(name string, attributes ...string) (*Client, func(*error)) {
+	tracer := c.hooks.getTracer()
+	if tracer == nil {
+		return c, func(*error) {}
+	}
+	ctx, span := tracer.StartSpan(c.traceParent(), name)
+	for i := 0; i+1 < len(attributes); i += 2 {
+		if attributes[i+1] != "" {
+			span.SetAttribute(attributes[i], attributes[i+1])
+		}
+	}
+	return c.withTraceContext(ctx), func(err *error) {
+		span.End(*err)
+	}
+}
+
+type requestSpanKey struct{}
+
+// requestSpanFromContext returns the span of the request of the context, nil if the request is not traced
+func requestSpanFromContext(ctx context.Context) Span {
+	span, _ := ctx.Value(requestSpanKey{}).(Span)
+	return span
+}
+
+// tracingAPI traces the requests of an API client, as children of the span of the function in progress
+type tracingAPI struct {
+	api.Client
+	hooks *clientHooks
+	// parent is the context of the span of the function in progress, nil if there is none
+	parent context.Context
+}
+
+// startRequest starts the span of a request and returns the context of the request carrying it
+func (t *tracingAPI) startRequest(ctx context.Context, method, path string) (context.Context, Span) {
+	tracer := t.hooks.getTracer()
+	if tracer == nil {
+		return ctx, nil
+	}
+	parent := t.parent
+	if parent == nil {
+		parent = context.Background()
+	}
+	_, span := tracer.StartSpan(parent, "HTTP "+method)
+	span.SetAttribute(AttributeHTTPMethod, method)
+	span.SetAttribute(AttributeHTTPURL, path)
+	span.SetAttribute(AttributeHTTPRoute, URLTemplate(path))
+	return context.WithValue(ctx, requestSpanKey{}, span), span
+}
+
+func endRequest(span Span, err error) {
+	if span != nil {
+		span.End(err)
+	}
+}
+
+func (t *tracingAPI) Do(ctx context.Context, method, path string, body, resp interface{}) error {
+	ctx, span := t.startRequest(ctx, method, path)
+	err := t.Client.Do(ctx, method, path, body, resp)
+	endRequest(span, err)
+	return err
+}
+
+func (t *tracingAPI) DoWithHeaders(ctx context.Context, method, path string, headers map[string]string, body, resp interface{}) error {
+	ctx, span := t.startRequest(ctx, method, path)
+	err := t.Client.DoWithHeaders(ctx, method, path, headers, body, resp)
+	endRequest(span, err)
+	return err
+}
+
+func (t *tracingAPI) DoAndGetResponseBody(ctx context.Context, method, path string, headers map[string]string, body interface{}) (*http.Response, error) {
+	ctx, span := t.startRequest(ctx, method, path)
+	res, err := t.Client.DoAndGetResponseBody(ctx, method, path, headers, body)
+	endRequest(span, err)
+	return res, err
+}
+
+func (t *tracingAPI) Get(ctx context.Context, path string, headers map[string]string, resp interface{}) error {
+	ctx, span := t.startRequest(ctx, http.MethodGet, path)
+	err := t.Client.Get(ctx, path, headers, resp)
+	endRequest(span, err)
+	return err
+}
+
+func (t *tracingAPI) Post(ctx context.Context, path string, headers map[string]string, body, resp interface{}) error {
+	ctx, span := t.startRequest(ctx, http.MethodPost, path)
+	err := t.Client.Post(ctx, path, headers, body, resp)
+	endRequest(span, err)
+	return err
+}
+
+func (t *tracingAPI) Put(ctx context.Context, path string, headers map[string]string, body, resp interface{}) error {
+	ctx, span := t.startRequest(ctx, http.MethodPut, path)
+	err := t.Client.Put(ctx, path, headers, body, resp)
+	endRequest(span, err)
+	return err
+}
+
+func (t *tracingAPI) Delete(ctx context.Context, path string, headers map[string]string, resp interface{}) error {
+	ctx, span := t.startRequest(ctx, http.MethodDelete, path)
+	err := t.Client.Delete(ctx, path, headers, resp)
+	endRequest(span, err)
+	return err
+}
@@ -27,6 +27,22 @@ type MaskingView struct {
 	StorageGroupID string `json:"storageGroupId"`
 }
 
+// RenameMaskingViewParam holds the new name of the masking view
+type RenameMaskingViewParam struct {
+	NewMaskingViewName string `json:"new_masking_view_name"`
+}
+
+// EditMaskingViewActionParam holds the action to perform on a masking view
+type EditMaskingViewActionParam struct {
+	RenameMaskingViewParam *RenameMaskingViewParam `json:"renameMaskingViewParam,omitempty"`
+}
+
+// EditMaskingViewParam contains action and option to update a masking view
+type EditMaskingViewParam struct {
+	EditMaskingViewActionParam *EditMaskingViewActionParam `json:"editMaskingViewActionParam"`
+	ExecutionOption            string                      `json:"executionOption,omitempty"`
+}
+
 // HostFlag holds the host flags
 type HostFlag struct {
 	Enabled  bool `json:"enabled"`
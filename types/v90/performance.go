@@ -0,0 +1,162 @@
+/*
+ Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+package types
+
+import "time"
+
+// Categories of performance data
+const (
+	PerformanceCategoryArray        = "Array"
+	PerformanceCategoryStorageGroup = "StorageGroup"
+	PerformanceCategoryVolume       = "Volume"
+	PerformanceCategoryFEDirector   = "FEDirector"
+	PerformanceCategoryFEPort       = "FEPort"
+)
+
+// Formats in which the performance data is aggregated over a sampling period
+const (
+	PerformanceDataFormatAverage = "Average"
+	PerformanceDataFormatMaximum = "Maximum"
+)
+
+// PerformanceSampleInterval is the interval at which the performance data is sampled
+const PerformanceSampleInterval = 5 * time.Minute
+
+// PerformanceKeysParam holds the parameters to list the keys of a performance category
+type PerformanceKeysParam struct {
+	SymmetrixID string `json:"symmetrixId,omitempty"`
+	DirectorID  string `json:"directorId,omitempty"`
+}
+
+// PerformanceKeysResult holds the keys of a performance category, in the list matching the category
+type PerformanceKeysResult struct {
+	ArrayInfo        []PerformanceKeyInfo `json:"arrayInfo,omitempty"`
+	StorageGroupInfo []PerformanceKeyInfo `json:"storageGroupInfo,omitempty"`
+	FEDirectorInfo   []PerformanceKeyInfo `json:"feDirectorInfo,omitempty"`
+	FEPortInfo       []PerformanceKeyInfo `json:"fePortInfo,omitempty"`
+}
+
+// PerformanceKeyInfo holds a performance key and the dates, in milliseconds since the epoch, between which its data is available
+type PerformanceKeyInfo struct {
+	SymmetrixID        string `json:"symmetrixId,omitempty"`
+	StorageGroupID     string `json:"storageGroupId,omitempty"`
+	DirectorID         string `json:"directorId,omitempty"`
+	PortID             string `json:"portId,omitempty"`
+	FirstAvailableDate int64  `json:"firstAvailableDate"`
+	LastAvailableDate  int64  `json:"lastAvailableDate"`
+}
+
+// PerformanceKey is an array, storage group, director or port with performance data available between two times
+type PerformanceKey struct {
+	ID             string
+	FirstAvailable time.Time
+	LastAvailable  time.Time
+}
+
+// PerformanceMetricsParam holds the parameters to query the metrics of a performance category.
+// The dates are in milliseconds since the epoch. The volumes are queried by SystemID and volume range
+type PerformanceMetricsParam struct {
+	SymmetrixID      string   `json:"symmetrixId,omitempty"`
+	SystemID         string   `json:"systemId,omitempty"`
+	StorageGroupID   string   `json:"storageGroupId,omitempty"`
+	DirectorID       string   `json:"directorId,omitempty"`
+	PortID           string   `json:"portId,omitempty"`
+	VolumeStartRange string   `json:"volumeStartRange,omitempty"`
+	VolumeEndRange   string   `json:"volumeEndRange,omitempty"`
+	StartDate        int64    `json:"startDate"`
+	EndDate          int64    `json:"endDate"`
+	DataFormat       string   `json:"dataFormat"`
+	Metrics          []string `json:"metrics"`
+}
+
+// PerformanceMetricsIterator holds the metrics of an array, storage group, director or port.
+// Each result holds the timestamp, in milliseconds since the epoch, and the value of each metric
+type PerformanceMetricsIterator struct {
+	ResultList     PerformanceMetricsResultList `json:"resultList"`
+	ID             string                       `json:"id"`
+	Count          int                          `json:"count"`
+	ExpirationTime int64                        `json:"expirationTime"`
+	MaxPageSize    int                          `json:"maxPageSize"`
+}
+
+// PerformanceMetricsResultList holds a page of performance metrics
+type PerformanceMetricsResultList struct {
+	Result []map[string]float64 `json:"result"`
+	From   int                  `json:"from"`
+	To     int                  `json:"to"`
+}
+
+// VolumePerformanceIterator holds the metrics of a range of volumes
+type VolumePerformanceIterator struct {
+	ResultList     VolumePerformanceResultList `json:"resultList"`
+	ID             string                      `json:"id"`
+	Count          int                         `json:"count"`
+	ExpirationTime int64                       `json:"expirationTime"`
+	MaxPageSize    int                         `json:"maxPageSize"`
+}
+
+// VolumePerformanceResultList holds a page of volume metrics
+type VolumePerformanceResultList struct {
+	Result []VolumePerformanceResult `json:"result"`
+	From   int                       `json:"from"`
+	To     int                       `json:"to"`
+}
+
+// VolumePerformanceResult holds the metrics of a volume
+type VolumePerformanceResult struct {
+	VolumeID     string               `json:"volumeId"`
+	VolumeResult []map[string]float64 `json:"volumeResult"`
+}
+
+// PerformanceQuery holds the time range and aggregation of a performance query
+type PerformanceQuery struct {
+	StartTime time.Time
+	EndTime   time.Time
+	// Interval aggregates the samples into intervals of this duration starting at StartTime, 0 keeps every sample
+	Interval time.Duration
+	// DataFormat is Average or Maximum, Average if not set. It also aggregates the samples into intervals
+	DataFormat string
+	// Metrics are additional metrics to query, returned in the Metrics of the samples
+	Metrics []string
+}
+
+// PerformanceSample holds the key performance metrics at a point in time.
+// The metrics which do not apply to the performance category are zero
+type PerformanceSample struct {
+	Timestamp time.Time
+	IOPS      float64
+	MBPerSec  float64
+	// ResponseTime is in milliseconds
+	ResponseTime       float64
+	CacheHitPercent    float64
+	UtilizationPercent float64
+	// Metrics holds the values of all the queried metrics by metric name
+	Metrics map[string]float64
+}
+
+// PerformanceResult holds the performance samples of an array, storage group, volume, director or port, oldest first
+type PerformanceResult struct {
+	SymmetrixID string
+	Category    string
+	ID          string
+	Samples     []PerformanceSample
+}
+
+// Latest returns the newest sample, or nil if there is none
+func (r *PerformanceResult) Latest() *PerformanceSample {
+	if len(r.Samples) == 0 {
+		return nil
+	}
+	return &r.Samples[len(r.Samples)-1]
+}
@@ -13,19 +13,37 @@
 */
 package types
 
+// AddPortParam holds the ports to add to a port group
 type AddPortParam struct {
 	Ports []SymmetrixPortKeyType `json:"port"`
 }
 
+// RemovePortParam holds the ports to remove from a port group
 type RemovePortParam struct {
 	Ports []SymmetrixPortKeyType `json:"port"`
 }
 
+// RenamePortGroupParam holds the new name of a port group
+type RenamePortGroupParam struct {
+	NewPortGroupName string `json:"new_port_group_name"`
+}
+
+// EditPortGroupActionParam holds the action to perform on a port group
 type EditPortGroupActionParam struct {
-	AddPortParam    *AddPortParam    `json:"addPortParam,omitempty"`
-	RemovePortParam *RemovePortParam `json:"removePortParam,omitempty"`
+	AddPortParam         *AddPortParam         `json:"addPortParam,omitempty"`
+	RemovePortParam      *RemovePortParam      `json:"removePortParam,omitempty"`
+	RenamePortGroupParam *RenamePortGroupParam `json:"renamePortGroupParam,omitempty"`
 }
 
+// EditPortGroup contains the action and option to update a port group
 type EditPortGroup struct {
 	EditPortGroupActionParam *EditPortGroupActionParam `json:"editPortGroupActionParam"`
+	ExecutionOption          string                    `json:"executionOption,omitempty"`
+}
+
+// PortGroupDetails holds a port group along with the details of each
+// of its member ports, in the order of PortGroup.SymmetrixPortKey
+type PortGroupDetails struct {
+	PortGroup *PortGroup `json:"portGroup"`
+	Ports     []*Port    `json:"ports"`
 }
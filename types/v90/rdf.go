@@ -0,0 +1,255 @@
+/*
+ Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+package types
+
+// SRDF replication modes
+const (
+	RDFModeSynchronous  = "Synchronous"
+	RDFModeAsynchronous = "Asynchronous"
+	RDFModeAdaptiveCopy = "Adaptive Copy"
+	RDFModeActive       = "Active" // SRDF/Metro
+)
+
+// Link states of an RDF group, derived from its online director ports
+const (
+	RDFLinkStateOnline   = "Online"
+	RDFLinkStateDegraded = "Degraded"
+	RDFLinkStateOffline  = "Offline"
+)
+
+// RDFGroupIDL holds the number and label of an RDF group
+type RDFGroupIDL struct {
+	RDFGroupNumber int    `json:"rdfgNumber"`
+	Label          string `json:"label"`
+}
+
+// RDFGroupList holds the RDF groups of a Symmetrix
+type RDFGroupList struct {
+	RDFGroupCount int           `json:"rdfGroupCount"`
+	RDFGroupIDs   []RDFGroupIDL `json:"rdfGroupID"`
+}
+
+// RDFGroup holds the details of an RDF group: the local and remote arrays,
+// the SRDF modes in use, the director ports of the links and the number of devices
+type RDFGroup struct {
+	SymmetrixID              string   `json:"symmetrixId,omitempty"`
+	RDFGroupNumber           int      `json:"rdfgNumber"`
+	Label                    string   `json:"label"`
+	RemoteRDFGroupNumber     int      `json:"remoteRdfgNumber"`
+	RemoteSymmetrix          string   `json:"remoteSymmetrix"`
+	NumDevices               int      `json:"numDevices"`
+	TotalDeviceCapacity      float64  `json:"totalDeviceCapacity"`
+	LocalPorts               []string `json:"localPorts"`
+	RemotePorts              []string `json:"remotePorts"`
+	LocalOnlinePorts         []string `json:"localOnlinePorts"`
+	RemoteOnlinePorts        []string `json:"remoteOnlinePorts"`
+	Modes                    []string `json:"modes"`
+	Type                     string   `json:"type"`
+	Metro                    bool     `json:"metro"`
+	Async                    bool     `json:"async"`
+	Offline                  bool     `json:"offline"`
+	Witness                  bool     `json:"witness"`
+	WitnessConfigured        bool     `json:"witnessConfigured"`
+	WitnessEffective         bool     `json:"witnessEffective"`
+	BiasConfigured           bool     `json:"biasConfigured"`
+	BiasEffective            bool     `json:"biasEffective"`
+	WitnessProtectedPhysical bool     `json:"witnessProtectedPhysical"`
+	WitnessProtectedVirtual  bool     `json:"witnessProtectedVirtual"`
+}
+
+// LinkState returns the state of the SRDF links of the group. The group is
+// Offline when it is flagged offline or has no online ports on either side,
+// and Degraded when only some of its ports are online.
+func (g *RDFGroup) LinkState() string {
+	if g.Offline || len(g.LocalOnlinePorts) == 0 || len(g.RemoteOnlinePorts) == 0 {
+		return RDFLinkStateOffline
+	}
+	if len(g.LocalOnlinePorts) < len(g.LocalPorts) || len(g.RemoteOnlinePorts) < len(g.RemotePorts) {
+		return RDFLinkStateDegraded
+	}
+	return RDFLinkStateOnline
+}
+
+// RDFGroupVolumeList holds the ids of the local volumes in an RDF group
+type RDFGroupVolumeList struct {
+	VolumeIDs []string `json:"name"`
+}
+
+// RDFDevicePair holds the SRDF relationship of a local volume with its remote mirror
+type RDFDevicePair struct {
+	LocalSymmetrixID     string `json:"localSymmetrixId"`
+	RemoteSymmetrixID    string `json:"remoteSymmetrixId"`
+	LocalRDFGroupNumber  int    `json:"localRdfGroupNumber"`
+	RemoteRDFGroupNumber int    `json:"remoteRdfGroupNumber"`
+	LocalVolumeName      string `json:"localVolumeName"`
+	RemoteVolumeName     string `json:"remoteVolumeName"`
+	LocalVolumeState     string `json:"localVolumeState"`
+	RemoteVolumeState    string `json:"remoteVolumeState"`
+	VolumeConfig         string `json:"volumeConfig"`
+	RDFMode              string `json:"rdfMode"`
+	RDFPairState         string `json:"rdfpairState"`
+	LargerRDFSide        string `json:"largerRdfSide"`
+}
+
+// Actions that can be performed on the SRDF pairs of a storage group
+const (
+	RDFActionEstablish = "Establish"
+	RDFActionSplit     = "Split"
+	RDFActionSuspend   = "Suspend"
+	RDFActionResume    = "Resume"
+	RDFActionFailover  = "Failover"
+	RDFActionFailback  = "Failback"
+	RDFActionSwap      = "Swap"
+)
+
+// SRDF pair states
+const (
+	RDFPairStateSynchronized = "Synchronized"
+	RDFPairStateConsistent   = "Consistent"
+	RDFPairStateSyncInProg   = "SyncInProg"
+	RDFPairStateSplit        = "Split"
+	RDFPairStateSuspended    = "Suspended"
+	RDFPairStateFailedOver   = "Failed Over"
+	RDFPairStatePartitioned  = "Partitioned"
+	RDFPairStateActiveActive = "ActiveActive"
+	RDFPairStateActiveBias   = "ActiveBias"
+)
+
+// CreateSGSRDF holds the parameters to protect a storage group with SRDF
+type CreateSGSRDF struct {
+	ReplicationMode        string `json:"replicationMode"`
+	RemoteSymmID           string `json:"remoteSymmId"`
+	RemoteStorageGroupName string `json:"remoteStorageGroupName"`
+	RemoteSLO              string `json:"remoteSLO,omitempty"`
+	RemoteSRP              string `json:"remoteSRP,omitempty"`
+	RDFGroupNumber         int    `json:"rdfgNumber"`
+	Establish              bool   `json:"establish"`
+	ExecutionOption        string `json:"executionOption"`
+}
+
+// SGRDFGroupList holds the RDF groups protecting a storage group
+type SGRDFGroupList struct {
+	RDFGroupNumbers []int `json:"rdfgs"`
+}
+
+// SGRDFInfo holds the SRDF state of a storage group in an RDF group.
+// Each of the slices holds the distinct values across the volumes of the storage group.
+type SGRDFInfo struct {
+	SymmetrixID      string   `json:"symmetrixId"`
+	StorageGroupName string   `json:"storageGroupName"`
+	RDFGroupNumber   int      `json:"rdfGroupNumber"`
+	VolumeRDFTypes   []string `json:"volumeRdfTypes"`
+	States           []string `json:"states"`
+	Modes            []string `json:"modes"`
+	LargerRDFSides   []string `json:"largerRdfSides"`
+}
+
+// InState returns true if all the SRDF pairs of the storage group are in the given state
+func (i *SGRDFInfo) InState(state string) bool {
+	if len(i.States) == 0 {
+		return false
+	}
+	for _, s := range i.States {
+		if s != state {
+			return false
+		}
+	}
+	return true
+}
+
+// RDFActionParams holds the options of an SRDF action
+type RDFActionParams struct {
+	Force    bool `json:"force"`
+	SymForce bool `json:"symForce"`
+	Star     bool `json:"star"`
+	Hop2     bool `json:"hop2"`
+	Bypass   bool `json:"bypass"`
+}
+
+// ModifySGRDFGroup holds the SRDF action to perform on the pairs of a storage group
+type ModifySGRDFGroup struct {
+	Action          string           `json:"action"`
+	Establish       *RDFActionParams `json:"establish,omitempty"`
+	Split           *RDFActionParams `json:"split,omitempty"`
+	Suspend         *RDFActionParams `json:"suspend,omitempty"`
+	Resume          *RDFActionParams `json:"resume,omitempty"`
+	Failover        *RDFActionParams `json:"failover,omitempty"`
+	Failback        *RDFActionParams `json:"failback,omitempty"`
+	Swap            *RDFActionParams `json:"swap,omitempty"`
+	SetBias         *SetBiasParam    `json:"setBias,omitempty"`
+	ExecutionOption string           `json:"executionOption"`
+}
+
+// DeleteSGRDFPair holds the options to delete the SRDF pairs of a storage group
+type DeleteSGRDFPair struct {
+	Force           bool   `json:"force"`
+	ExecutionOption string `json:"executionOption"`
+}
+
+// RDFActionSetBias is the action to move the bias of the SRDF/Metro pairs of a storage group
+const RDFActionSetBias = "SetBias"
+
+// SRDF/Metro bias sides and witness types
+const (
+	MetroBiasR1          = "R1"
+	MetroBiasR2          = "R2"
+	MetroBiasUnknown     = "Unknown"
+	MetroWitnessPhysical = "Physical"
+	MetroWitnessVirtual  = "Virtual"
+)
+
+// SetBiasParam holds the side (R1 or R2) to which the bias of SRDF/Metro pairs is moved
+type SetBiasParam struct {
+	Bias string `json:"bias"`
+}
+
+// MetroStatus holds the SRDF/Metro state of a storage group: the pair states,
+// the array holding the bias and the configuration and state of the witness.
+// The bias follows the R1 personality, so BiasSymmetrixID is the array whose volumes are R1.
+// Without SRDF pairs, or while the pairs have different personalities, LocalRDFType is MetroBiasUnknown
+// and BiasSymmetrixID is empty
+type MetroStatus struct {
+	SymmetrixID       string   `json:"symmetrixId"`
+	RemoteSymmetrixID string   `json:"remoteSymmetrixId"`
+	StorageGroupName  string   `json:"storageGroupName"`
+	RDFGroupNumber    int      `json:"rdfGroupNumber"`
+	States            []string `json:"states"`
+	LocalRDFType      string   `json:"localRdfType"`
+	BiasSymmetrixID   string   `json:"biasSymmetrixId"`
+	BiasConfigured    bool     `json:"biasConfigured"`
+	BiasEffective     bool     `json:"biasEffective"`
+	WitnessConfigured bool     `json:"witnessConfigured"`
+	WitnessEffective  bool     `json:"witnessEffective"`
+	WitnessType       string   `json:"witnessType,omitempty"`
+}
+
+// IsActiveActive returns true if all the pairs are ActiveActive, i.e. the witness protects the pairs
+func (m *MetroStatus) IsActiveActive() bool {
+	return m.inState(RDFPairStateActiveActive)
+}
+
+// IsActiveBias returns true if all the pairs are ActiveBias, i.e. only the bias side survives a link failure
+func (m *MetroStatus) IsActiveBias() bool {
+	return m.inState(RDFPairStateActiveBias)
+}
+
+// IsWitnessDegraded returns true if a witness is configured but not in effect
+func (m *MetroStatus) IsWitnessDegraded() bool {
+	return m.WitnessConfigured && !m.WitnessEffective
+}
+
+func (m *MetroStatus) inState(state string) bool {
+	info := &SGRDFInfo{States: m.States}
+	return info.InState(state)
+}
@@ -0,0 +1,208 @@
+/*
+ Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+package types
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ReplicationGraph holds the local replication relationships of a set of volumes: the snapshots of the
+// source volumes, their generations and the targets linked to them, and the clone and mirror sessions.
+// Volumes holds the volumes of the graph by volume ID
+type ReplicationGraph struct {
+	SymmetrixID string
+	Volumes     map[string]*ReplicationGraphVolume
+}
+
+// ReplicationGraphVolume is a volume of a replication graph
+type ReplicationGraphVolume struct {
+	VolumeID string
+	// Snapshots are the snapshots of the volume
+	Snapshots []ReplicationGraphSnapshot
+	// LinkedSnapshots are the snapshot generations of other volumes linked to the volume
+	LinkedSnapshots SnapshotGenerations
+	CloneSessions   []CloneSession
+	MirrorSessions  []MirrorSession
+}
+
+// ReplicationGraphSnapshot is a snapshot of a volume of a replication graph with its generations, newest first
+type ReplicationGraphSnapshot struct {
+	SnapshotName string
+	Generations  SnapshotGenerations
+}
+
+// NewReplicationGraphVolume returns the volume of a replication graph from the TimeFinder information of the volume
+func NewReplicationGraphVolume(volumeID string, info *TimeFinderInfo) *ReplicationGraphVolume {
+	volume := &ReplicationGraphVolume{
+		VolumeID:        volumeID,
+		LinkedSnapshots: make(SnapshotGenerations, 0),
+		CloneSessions:   info.CloneSession,
+		MirrorSessions:  info.MirrorSession,
+	}
+	for _, session := range info.SnapVXSession {
+		for i := range session.SourceSnapshotGenInfo {
+			generation := NewSnapshotGenerationFromHeader(&session.SourceSnapshotGenInfo[i])
+			generation.VolumeID = volumeID
+			snapshot := volume.Snapshot(generation.SnapshotName)
+			if snapshot == nil {
+				volume.Snapshots = append(volume.Snapshots, ReplicationGraphSnapshot{SnapshotName: generation.SnapshotName})
+				snapshot = &volume.Snapshots[len(volume.Snapshots)-1]
+			}
+			snapshot.Generations = append(snapshot.Generations, *generation)
+		}
+		if session.TargetSourceSnapshotGenInfo != nil {
+			volume.LinkedSnapshots = append(volume.LinkedSnapshots, *NewSnapshotGenerationFromTarget(session.TargetSourceSnapshotGenInfo))
+		}
+	}
+	sort.SliceStable(volume.Snapshots, func(i, j int) bool {
+		return volume.Snapshots[i].SnapshotName < volume.Snapshots[j].SnapshotName
+	})
+	for i := range volume.Snapshots {
+		volume.Snapshots[i].Generations.SortNewestFirst()
+	}
+	return volume
+}
+
+// Snapshot returns the snapshot of the volume named snapshotName, or nil if there is none
+func (v *ReplicationGraphVolume) Snapshot(snapshotName string) *ReplicationGraphSnapshot {
+	for i := range v.Snapshots {
+		if v.Snapshots[i].SnapshotName == snapshotName {
+			return &v.Snapshots[i]
+		}
+	}
+	return nil
+}
+
+// RelatedVolumes returns the IDs of the volumes the volume has a replication relationship with
+func (v *ReplicationGraphVolume) RelatedVolumes() []string {
+	related := make([]string, 0)
+	for _, snapshot := range v.Snapshots {
+		for _, generation := range snapshot.Generations {
+			for _, target := range generation.Targets {
+				related = append(related, target.VolumeID)
+			}
+		}
+	}
+	for _, generation := range v.LinkedSnapshots {
+		related = append(related, generation.VolumeID)
+	}
+	for _, session := range v.CloneSessions {
+		related = append(related, session.SourceVolume, session.TargetVolume)
+	}
+	for _, session := range v.MirrorSessions {
+		related = append(related, session.SourceVolume, session.TargetVolume)
+	}
+	return uniqueVolumeIDs(related, v.VolumeID)
+}
+
+// dependents returns the IDs of the volumes holding a copy of the data of the volume:
+// the targets of its snapshots and the targets of its clone and mirror sessions
+func (v *ReplicationGraphVolume) dependents() []string {
+	dependents := make([]string, 0)
+	for _, snapshot := range v.Snapshots {
+		dependents = append(dependents, snapshot.targets()...)
+	}
+	for _, session := range v.CloneSessions {
+		if session.SourceVolume == v.VolumeID {
+			dependents = append(dependents, session.TargetVolume)
+		}
+	}
+	for _, session := range v.MirrorSessions {
+		if session.SourceVolume == v.VolumeID {
+			dependents = append(dependents, session.TargetVolume)
+		}
+	}
+	return dependents
+}
+
+// targets returns the IDs of the volumes linked to any generation of the snapshot
+func (s *ReplicationGraphSnapshot) targets() []string {
+	targets := make([]string, 0)
+	for _, generation := range s.Generations {
+		for _, target := range generation.Targets {
+			targets = append(targets, target.VolumeID)
+		}
+	}
+	return targets
+}
+
+// CanDeleteVolume returns true if the volume has no replication relationship preventing its deletion,
+// otherwise it returns false with the reasons the volume cannot be deleted
+func (g *ReplicationGraph) CanDeleteVolume(volumeID string) (bool, []string) {
+	volume, ok := g.Volumes[volumeID]
+	if !ok {
+		return false, []string{fmt.Sprintf("volume %s is not in the replication graph", volumeID)}
+	}
+	reasons := make([]string, 0)
+	for _, snapshot := range volume.Snapshots {
+		reasons = append(reasons, fmt.Sprintf("volume %s has %d generations of snapshot %s", volumeID, len(snapshot.Generations), snapshot.SnapshotName))
+	}
+	for _, generation := range volume.LinkedSnapshots {
+		reasons = append(reasons, fmt.Sprintf("volume %s is linked to generation %d of snapshot %s of volume %s",
+			volumeID, generation.Generation, generation.SnapshotName, generation.VolumeID))
+	}
+	for _, session := range volume.CloneSessions {
+		reasons = append(reasons, fmt.Sprintf("volume %s is in a clone session from volume %s to volume %s", volumeID, session.SourceVolume, session.TargetVolume))
+	}
+	for _, session := range volume.MirrorSessions {
+		reasons = append(reasons, fmt.Sprintf("volume %s is in a mirror session from volume %s to volume %s", volumeID, session.SourceVolume, session.TargetVolume))
+	}
+	return len(reasons) == 0, reasons
+}
+
+// SnapshotDependents returns the IDs of the volumes depending on the snapshot of the volume: the volumes linked
+// to its generations and, down the chain, the volumes depending on those through their own snapshots, clone
+// and mirror sessions. Volumes outside the graph are returned but their dependents are not
+func (g *ReplicationGraph) SnapshotDependents(volumeID, snapshotName string) []string {
+	volume, ok := g.Volumes[volumeID]
+	if !ok {
+		return []string{}
+	}
+	snapshot := volume.Snapshot(snapshotName)
+	if snapshot == nil {
+		return []string{}
+	}
+	visited := map[string]bool{volumeID: true}
+	dependents := make([]string, 0)
+	queue := snapshot.targets()
+	for len(queue) > 0 {
+		dependent := queue[0]
+		queue = queue[1:]
+		if visited[dependent] {
+			continue
+		}
+		visited[dependent] = true
+		dependents = append(dependents, dependent)
+		if next, ok := g.Volumes[dependent]; ok {
+			queue = append(queue, next.dependents()...)
+		}
+	}
+	sort.Strings(dependents)
+	return dependents
+}
+
+// uniqueVolumeIDs returns the sorted volume IDs without duplicates, leaving out exclude
+func uniqueVolumeIDs(volumeIDs []string, exclude string) []string {
+	seen := map[string]bool{exclude: true}
+	unique := make([]string, 0)
+	for _, volumeID := range volumeIDs {
+		if volumeID != "" && !seen[volumeID] {
+			seen[volumeID] = true
+			unique = append(unique, volumeID)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
@@ -13,6 +13,12 @@
 */
 package types
 
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
 // QueryParams is a map of key value pairs that can be
 // appended to any url as query parameters.
 type QueryParams map[string]interface{}
@@ -49,6 +55,7 @@ type CreateVolumesSnapshot struct {
 	TimeToLive       int64        `json:"timeToLive"`
 	TTL              int64        `json:"ttl,omitempty"`
 	Securettl        int64        `json:"securettl,omitempty"`
+	Consistent       bool         `json:"consistent,omitempty"`
 	ExecutionOption  string       `json:"executionOption"`
 }
 
@@ -364,3 +371,471 @@ type SnapTarget struct {
 	Defined bool
 	CpMode  bool
 }
+
+// Actions that can be performed on a storage group snapshot generation
+const (
+	SnapshotActionLink    = "Link"
+	SnapshotActionUnlink  = "Unlink"
+	SnapshotActionRestore = "Restore"
+	SnapshotActionRename  = "Rename"
+)
+
+// CreateStorageGroupSnapshot contains parameters to create a snapshot of all the volumes of a storage group
+type CreateStorageGroupSnapshot struct {
+	SnapshotName    string `json:"snapshotName"`
+	TimeToLive      int64  `json:"timeToLive,omitempty"`
+	Securettl       int64  `json:"securettl,omitempty"`
+	TimeInHours     bool   `json:"timeInHours"`
+	BothSides       bool   `json:"bothSides"`
+	Star            bool   `json:"star"`
+	Force           bool   `json:"force"`
+	ExecutionOption string `json:"executionOption"`
+}
+
+// StorageGroupSnapshotList contains the names of the snapshots of a storage group
+type StorageGroupSnapshotList struct {
+	Name []string `json:"name"`
+}
+
+// StorageGroupSnapshotGenerationList contains the generations of a storage group snapshot
+type StorageGroupSnapshotGenerationList struct {
+	Generations []int64 `json:"generations"`
+}
+
+// StorageGroupSnapshot contains information on a generation of a storage group snapshot
+type StorageGroupSnapshot struct {
+	Name                string                 `json:"name"`
+	Generation          int64                  `json:"generation"`
+	Timestamp           string                 `json:"timestamp"`
+	TimestampUTC        string                 `json:"timestamp_utc"`
+	State               []string               `json:"state"`
+	NumSourceVolumes    int                    `json:"numSourceVolumes"`
+	SourceVolumes       []SnapshotSourceVolume `json:"sourceVolume"`
+	LinkedStorageGroups []LinkedStorageGroup   `json:"linkedStorageGroup,omitempty"`
+	Linked              bool                   `json:"linked"`
+	Restored            bool                   `json:"restored"`
+	Expired             bool                   `json:"expired"`
+	Secured             bool                   `json:"secured"`
+	TimeToLive          int64                  `json:"timeToLive"`
+}
+
+// SnapshotSourceVolume contains a source volume of a storage group snapshot
+type SnapshotSourceVolume struct {
+	Name     string  `json:"name"`
+	Capacity float64 `json:"capacity"`
+}
+
+// LinkedStorageGroup contains the link of a source volume of a storage group snapshot to a volume of the linked storage group
+type LinkedStorageGroup struct {
+	Name             string `json:"name"`
+	SourceVolumeName string `json:"sourceVolumeName"`
+	LinkedVolumeName string `json:"linkedVolumeName"`
+	Tracks           int64  `json:"tracks"`
+	TrackSize        int64  `json:"track_size"`
+	PercentageCopied int64  `json:"percentageCopied"`
+	Defined          bool   `json:"defined"`
+	Linked           bool   `json:"linked"`
+	Copy             bool   `json:"copy"`
+}
+
+// ModifyStorageGroupSnapshot contains the action to perform on a storage group snapshot generation
+type ModifyStorageGroupSnapshot struct {
+	Action          string                            `json:"action"`
+	Link            *LinkStorageGroupSnapshotParam    `json:"link,omitempty"`
+	Unlink          *UnlinkStorageGroupSnapshotParam  `json:"unlink,omitempty"`
+	Restore         *RestoreStorageGroupSnapshotParam `json:"restore,omitempty"`
+	Rename          *RenameStorageGroupSnapshotParam  `json:"rename,omitempty"`
+	ExecutionOption string                            `json:"executionOption"`
+}
+
+// LinkStorageGroupSnapshotParam contains the storage group to which a snapshot is linked
+type LinkStorageGroupSnapshotParam struct {
+	LinkStorageGroupName string `json:"linkStorageGroupName"`
+	Copy                 bool   `json:"copy"`
+	Remote               bool   `json:"remote"`
+}
+
+// UnlinkStorageGroupSnapshotParam contains the storage group from which a snapshot is unlinked
+type UnlinkStorageGroupSnapshotParam struct {
+	UnlinkStorageGroupName string `json:"unlinkStorageGroupName"`
+	Symforce               bool   `json:"symforce"`
+}
+
+// RestoreStorageGroupSnapshotParam contains the options to restore a snapshot to its source volumes
+type RestoreStorageGroupSnapshotParam struct {
+	Remote bool `json:"remote"`
+}
+
+// RenameStorageGroupSnapshotParam contains the new name of a storage group snapshot
+type RenameStorageGroupSnapshotParam struct {
+	NewStorageGroupSnapshotName string `json:"newStorageGroupSnapshotName"`
+}
+
+// DeleteStorageGroupSnapshot contains input parameters to delete a storage group snapshot generation
+type DeleteStorageGroupSnapshot struct {
+	Symforce        bool   `json:"symforce,omitempty"`
+	Force           bool   `json:"force,omitempty"`
+	ExecutionOption string `json:"executionOption"`
+}
+
+// Link states of a snapshot target
+const (
+	SnapshotLinkStateLinked         = "Linked"
+	SnapshotLinkStateCopied         = "Copied"
+	SnapshotLinkStateCopyInProgress = "CopyInProg"
+)
+
+// LinkSnapshotOptions contains the options to link a snapshot to target volumes
+type LinkSnapshotOptions struct {
+	// Copy copies all the data of the snapshot to the targets, otherwise the targets reference the snapshot data
+	Copy bool
+	// Exact pairs the source and target volumes in their ordinal positions, otherwise they are paired by best match
+	Exact bool
+	// Relink moves targets already linked to another snapshot or generation of the sources to the snapshot
+	Relink bool
+	// Remote propagates the data to the remote mirrors of SRDF protected targets
+	Remote bool
+}
+
+// Actions that set the time to live of a snapshot generation
+const (
+	SnapshotActionSetTimeToLive = "SetTimeToLive"
+	SnapshotActionSetSecure     = "SetSecure"
+)
+
+// SnapshotOptions contains the retention options of a snapshot
+type SnapshotOptions struct {
+	// TimeToLive after which the snapshot is deleted once it is no longer linked, in days unless TimeInHours is set. 0 for no time to live
+	TimeToLive int64
+	// TimeInHours sets the unit of TimeToLive to hours
+	TimeInHours bool
+	// Secure makes the snapshot a secure snapshot, which cannot be deleted before its time to live expires
+	Secure bool
+}
+
+// SnapshotTimeToLive returns a snapshot time to live as a duration. The time to live given when a snapshot
+// is created or modified is in days unless timeInHours is set, the one reported by Unisphere is in hours
+func SnapshotTimeToLive(ttl int64, timeInHours bool) time.Duration {
+	if timeInHours {
+		return time.Duration(ttl) * time.Hour
+	}
+	return time.Duration(ttl) * 24 * time.Hour
+}
+
+// reportedTimeToLive returns the time to live reported in the snapshot resources as a duration
+func reportedTimeToLive(ttl int64) time.Duration {
+	return SnapshotTimeToLive(ttl, true)
+}
+
+// CloneVolumeOptions contains the options of a volume clone
+type CloneVolumeOptions struct {
+	// Copy creates a fully copied clone, independent of the source, instead of a linked clone referencing a snapshot of the source
+	Copy bool
+	// SnapshotName is the name of the snapshot linked to the clone, by default the name of the clone prefixed with clone-
+	SnapshotName string
+	// Timeout is how long to wait for the link to be defined, or for the data to be copied in copy mode,
+	// 5 minutes if it is not positive
+	Timeout time.Duration
+}
+
+// Reasons for which a snapshot generation is collected or skipped by the snapshot garbage collector
+const (
+	SnapshotGCReasonExpired       = "Expired"
+	SnapshotGCReasonTooOld        = "TooOld"
+	SnapshotGCReasonOrphaned      = "Orphaned"
+	SnapshotGCReasonDeletedVolume = "DeletedVolume"
+	SnapshotGCReasonSecured       = "Secured"
+	SnapshotGCReasonLinked        = "Linked"
+	SnapshotGCReasonRateLimited   = "RateLimited"
+)
+
+// SnapshotGCPolicy selects the snapshot generations collected by the snapshot garbage collector.
+// A generation is collected when its name matches NamePrefix, it is at least MinAge old and it is
+// expired, older than MaxAge, orphaned or of a deleted volume, as enabled in the policy
+type SnapshotGCPolicy struct {
+	// NamePrefix restricts the collection to the snapshots whose name starts with it, all the snapshots if empty
+	NamePrefix string
+	// MinAge protects the generations younger than it from collection
+	MinAge time.Duration
+	// Expired collects the generations whose time to live has expired
+	Expired bool
+	// MaxAge collects the generations older than it, 0 disables it
+	MaxAge time.Duration
+	// Orphaned collects the generations no volume is linked to which were not taken by a snapshot policy,
+	// such as the temporary snapshots left by failed clones. As it selects every unlinked snapshot created
+	// outside of the snapshot policies, it requires the collection to be narrowed down by NamePrefix or MinAge
+	Orphaned bool
+	// DeletedVolumePrefix collects the generations of the volumes whose identifier starts with it, empty disables it.
+	// The array refuses to delete a volume with snapshots, so the deleted volumes are only renamed with this prefix
+	// by their owner, the CSI driver for instance, until their snapshots are gone
+	DeletedVolumePrefix string
+	// DryRun only reports the generations that would be collected
+	DryRun bool
+	// MaxDeletions limits the number of generations deleted in one run, 0 for no limit
+	MaxDeletions int
+	// DeleteInterval is the time waited between two deletions
+	DeleteInterval time.Duration
+}
+
+// SnapshotGCCandidate holds a snapshot generation matching a SnapshotGCPolicy and what was done with it
+type SnapshotGCCandidate struct {
+	VolumeID      string    `json:"volumeId"`
+	SnapshotName  string    `json:"snapshotName"`
+	Generation    int64     `json:"generation"`
+	CreationTime  time.Time `json:"creationTime"`
+	Reason        string    `json:"reason"`
+	LinkedVolumes []string  `json:"linkedVolumes,omitempty"`
+	// Skipped is the reason for which a matching generation was not deleted
+	Skipped string `json:"skipped,omitempty"`
+	Deleted bool   `json:"deleted"`
+	Error   string `json:"error,omitempty"`
+}
+
+// SnapshotGCReport holds the generations found by a run of the snapshot garbage collector, in deletion order
+type SnapshotGCReport struct {
+	DryRun     bool                  `json:"dryRun"`
+	Candidates []SnapshotGCCandidate `json:"candidates"`
+}
+
+// DeletedCount returns the number of generations deleted
+func (r *SnapshotGCReport) DeletedCount() int {
+	count := 0
+	for _, candidate := range r.Candidates {
+		if candidate.Deleted {
+			count++
+		}
+	}
+	return count
+}
+
+// FailedCount returns the number of generations which could not be unlinked or deleted
+func (r *SnapshotGCReport) FailedCount() int {
+	count := 0
+	for _, candidate := range r.Candidates {
+		if candidate.Error != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// SnapshotState is the state of a snapshot generation
+type SnapshotState string
+
+// States of a snapshot generation
+const (
+	SnapshotStateEstablished     SnapshotState = "Established"
+	SnapshotStateEstablishInProg SnapshotState = "EstablishInProg"
+	SnapshotStateRestored        SnapshotState = "Restored"
+	SnapshotStateRestoreInProg   SnapshotState = "RestoreInProg"
+	SnapshotStateFailed          SnapshotState = "Failed"
+)
+
+// SnapshotGeneration is a generation of a snapVx snapshot of a source volume. It unifies the representations of
+// the replication and the private volume resources, with the timestamps parsed and the time to live as a duration
+type SnapshotGeneration struct {
+	VolumeID     string           `json:"volumeId"`
+	SnapshotName string           `json:"snapshotName"`
+	Generation   int64            `json:"generation"`
+	Timestamp    time.Time        `json:"timestamp"`
+	State        SnapshotState    `json:"state,omitempty"`
+	TimeToLive   time.Duration    `json:"timeToLive"`
+	Secured      bool             `json:"secured"`
+	Expired      bool             `json:"expired"`
+	Restored     bool             `json:"restored"`
+	Targets      []SnapshotTarget `json:"targets,omitempty"`
+}
+
+// SnapshotTarget is a volume linked to a snapshot generation
+type SnapshotTarget struct {
+	VolumeID         string    `json:"volumeId"`
+	State            string    `json:"state"`
+	Timestamp        time.Time `json:"timestamp"`
+	Defined          bool      `json:"defined"`
+	Copy             bool      `json:"copy"`
+	Restored         bool      `json:"restored"`
+	PercentageCopied int64     `json:"percentageCopied"`
+}
+
+// ParseSnapshotTimestamp parses a snapshot timestamp given either in seconds since the epoch
+// or in the ANSI C format. It returns false if the timestamp cannot be parsed
+func ParseSnapshotTimestamp(timestamp string) (time.Time, bool) {
+	if seconds, err := strconv.ParseInt(timestamp, 10, 64); err == nil {
+		return time.Unix(seconds, 0), true
+	}
+	if t, err := time.Parse(time.ANSIC, timestamp); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// NewSnapshotGeneration returns the generation of a snapshot of a volume from the replication resources
+func NewSnapshotGeneration(volumeID string, source *VolumeSnapshotSource) *SnapshotGeneration {
+	timestamp, _ := ParseSnapshotTimestamp(source.TimeStamp)
+	generation := &SnapshotGeneration{
+		VolumeID:     volumeID,
+		SnapshotName: source.SnapshotName,
+		Generation:   source.Generation,
+		Timestamp:    timestamp,
+		State:        SnapshotState(source.State),
+		TimeToLive:   reportedTimeToLive(source.TTL),
+		Secured:      source.Secured,
+		Expired:      source.Expired,
+		Restored:     source.IsRestored,
+	}
+	for _, link := range source.LinkedVolumes {
+		linkTimestamp, _ := ParseSnapshotTimestamp(link.Timestamp)
+		generation.Targets = append(generation.Targets, SnapshotTarget{
+			VolumeID:         link.TargetDevice,
+			State:            link.State,
+			Timestamp:        linkTimestamp,
+			Defined:          link.Defined,
+			Copy:             link.Copy,
+			Restored:         link.Restored,
+			PercentageCopied: link.PercentageCopied,
+		})
+	}
+	return generation
+}
+
+// NewSnapshotGenerationFromHeader returns the generation of a snapshot of a volume from the private volume resources.
+// The timestamp of the header is in seconds since the epoch
+func NewSnapshotGenerationFromHeader(info *SourceSnapshotGenInfo) *SnapshotGeneration {
+	header := info.SnapshotHeader
+	generation := &SnapshotGeneration{
+		VolumeID:     header.Device,
+		SnapshotName: header.SnapshotName,
+		Generation:   header.Generation,
+		Timestamp:    time.Unix(header.Timestamp, 0),
+		TimeToLive:   reportedTimeToLive(header.TimeToLive),
+		Secured:      header.Secured,
+		Expired:      header.Expired,
+	}
+	for _, link := range info.LinkSnapshotGenInfo {
+		generation.Targets = append(generation.Targets, SnapshotTarget{
+			VolumeID: link.TargetDevice,
+			State:    link.State,
+			Defined:  link.Defined,
+			Restored: link.Restored,
+		})
+	}
+	return generation
+}
+
+// NewSnapshotGenerationFromTarget returns the generation of a snapshot linked to a target volume from the private
+// volume resources of the target, with the target as its only linked volume
+func NewSnapshotGenerationFromTarget(info *TargetSourceSnapshotGenInfo) *SnapshotGeneration {
+	return &SnapshotGeneration{
+		VolumeID:     info.SourceDevice,
+		SnapshotName: info.SnapshotName,
+		Generation:   info.Generation,
+		Timestamp:    time.Unix(info.Timestamp, 0),
+		TimeToLive:   reportedTimeToLive(info.TimeToLive),
+		Secured:      info.Secured,
+		Expired:      info.Expired,
+		Targets: []SnapshotTarget{{
+			VolumeID: info.TargetDevice,
+			State:    info.Defined,
+		}},
+	}
+}
+
+// ExpiryTime returns the time at which the generation expires, or the zero time if it has no time to live
+func (g *SnapshotGeneration) ExpiryTime() time.Time {
+	if g.TimeToLive == 0 || g.Timestamp.IsZero() {
+		return time.Time{}
+	}
+	return g.Timestamp.Add(g.TimeToLive)
+}
+
+// IsLinked returns true if volumes are linked to the generation
+func (g *SnapshotGeneration) IsLinked() bool {
+	return len(g.Targets) > 0
+}
+
+// SnapshotGenerations is a list of snapshot generations
+type SnapshotGenerations []SnapshotGeneration
+
+// SortNewestFirst sorts the generations by timestamp, newest first, then by generation number
+func (g SnapshotGenerations) SortNewestFirst() {
+	sort.SliceStable(g, func(i, j int) bool {
+		if !g[i].Timestamp.Equal(g[j].Timestamp) {
+			return g[i].Timestamp.After(g[j].Timestamp)
+		}
+		return g[i].Generation < g[j].Generation
+	})
+}
+
+// Latest returns the newest generation, or nil if there is none
+func (g SnapshotGenerations) Latest() *SnapshotGeneration {
+	var latest *SnapshotGeneration
+	for i := range g {
+		if latest == nil || g[i].Timestamp.After(latest.Timestamp) ||
+			(g[i].Timestamp.Equal(latest.Timestamp) && g[i].Generation < latest.Generation) {
+			latest = &g[i]
+		}
+	}
+	return latest
+}
+
+// Named returns the generations of the snapshot named name
+func (g SnapshotGenerations) Named(name string) SnapshotGenerations {
+	return g.Filter(func(generation *SnapshotGeneration) bool {
+		return generation.SnapshotName == name
+	})
+}
+
+// OlderThan returns the generations taken before t. Generations without a timestamp are left out
+func (g SnapshotGenerations) OlderThan(t time.Time) SnapshotGenerations {
+	return g.Filter(func(generation *SnapshotGeneration) bool {
+		return !generation.Timestamp.IsZero() && generation.Timestamp.Before(t)
+	})
+}
+
+// Filter returns the generations for which keep returns true
+func (g SnapshotGenerations) Filter(keep func(*SnapshotGeneration) bool) SnapshotGenerations {
+	filtered := make(SnapshotGenerations, 0)
+	for i := range g {
+		if keep(&g[i]) {
+			filtered = append(filtered, g[i])
+		}
+	}
+	return filtered
+}
+
+// ConsistentSnapshot holds a snapshot taken of a set of volumes at the same point in time
+// and the generation of the snapshot on each of the volumes
+type ConsistentSnapshot struct {
+	SymmetrixID  string
+	SnapshotName string
+	Timestamp    time.Time
+	Volumes      []ConsistentSnapshotVolume
+}
+
+// ConsistentSnapshotVolume holds the generation of a consistent snapshot on one of its source volumes.
+// The generation number is the one at creation time, it is incremented each time the snapshot is taken again,
+// while the time the generation was taken identifies it
+type ConsistentSnapshotVolume struct {
+	VolumeID   string
+	Generation int64
+	Timestamp  time.Time
+}
+
+// SourceVolumes returns the source volumes of the snapshot in the order they were given
+func (s *ConsistentSnapshot) SourceVolumes() []VolumeList {
+	sourceVolumes := make([]VolumeList, 0, len(s.Volumes))
+	for _, volume := range s.Volumes {
+		sourceVolumes = append(sourceVolumes, VolumeList{Name: volume.VolumeID})
+	}
+	return sourceVolumes
+}
+
+// Generation returns the generation of the snapshot on the volume at creation time, and false if the volume is not a source of the snapshot
+func (s *ConsistentSnapshot) Generation(volumeID string) (int64, bool) {
+	for _, volume := range s.Volumes {
+		if volume.VolumeID == volumeID {
+			return volume.Generation, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,128 @@
+/*
+ Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+package types
+
+// Intervals between the snapshots of a snapshot policy
+const (
+	SnapshotPolicyInterval10Minutes = "10 Minutes"
+	SnapshotPolicyInterval12Minutes = "12 Minutes"
+	SnapshotPolicyInterval15Minutes = "15 Minutes"
+	SnapshotPolicyInterval20Minutes = "20 Minutes"
+	SnapshotPolicyInterval30Minutes = "30 Minutes"
+	SnapshotPolicyInterval1Hour     = "1 Hour"
+	SnapshotPolicyInterval2Hours    = "2 Hours"
+	SnapshotPolicyInterval3Hours    = "3 Hours"
+	SnapshotPolicyInterval4Hours    = "4 Hours"
+	SnapshotPolicyInterval6Hours    = "6 Hours"
+	SnapshotPolicyInterval8Hours    = "8 Hours"
+	SnapshotPolicyInterval12Hours   = "12 Hours"
+	SnapshotPolicyInterval1Day      = "1 Day"
+	SnapshotPolicyInterval7Days     = "7 Days"
+)
+
+// Actions that can be performed on a snapshot policy
+const (
+	SnapshotPolicyActionModify       = "Modify"
+	SnapshotPolicyActionSuspend      = "Suspend"
+	SnapshotPolicyActionResume       = "Resume"
+	SnapshotPolicyActionAssociate    = "AssociateToStorageGroups"
+	SnapshotPolicyActionDisassociate = "DisassociateFromStorageGroups"
+)
+
+// Snapshot compliance states of a storage group
+const (
+	SnapshotComplianceGreen  = "GREEN"
+	SnapshotComplianceYellow = "YELLOW"
+	SnapshotComplianceRed    = "RED"
+	SnapshotComplianceNone   = "NONE"
+)
+
+// SnapshotPolicyList holds the names of the snapshot policies of a Symmetrix
+type SnapshotPolicyList struct {
+	SnapshotPolicyIDs []string `json:"name"`
+}
+
+// SnapshotPolicy holds a snapshot policy, which takes a snapshot of its storage groups every interval,
+// offset by OffsetMinutes, and keeps the last SnapshotCount snapshots. The snapshot compliance of a storage
+// group is yellow when its number of snapshots falls to ComplianceCountWarning and red when it falls to
+// ComplianceCountCritical
+type SnapshotPolicy struct {
+	SymmetrixID             string `json:"symmetrixID"`
+	SnapshotPolicyName      string `json:"snapshot_policy_name"`
+	SnapshotCount           int    `json:"snapshot_count"`
+	IntervalMinutes         int    `json:"interval_minutes"`
+	OffsetMinutes           int    `json:"offset_minutes"`
+	Suspended               bool   `json:"suspended"`
+	Secure                  bool   `json:"secure"`
+	LastTimeUsed            string `json:"last_time_used,omitempty"`
+	StorageGroupCount       int    `json:"storage_group_count"`
+	ComplianceCountWarning  int    `json:"compliance_count_warning"`
+	ComplianceCountCritical int    `json:"compliance_count_critical"`
+	Type                    string `json:"type"`
+}
+
+// CreateSnapshotPolicyParam holds the parameters to create a snapshot policy.
+// Interval is one of the SnapshotPolicyInterval values
+type CreateSnapshotPolicyParam struct {
+	SnapshotPolicyName      string `json:"snapshot_policy_name"`
+	Interval                string `json:"interval"`
+	OffsetMins              int    `json:"offset_mins,omitempty"`
+	SnapshotCount           int    `json:"snapshot_count"`
+	Secure                  bool   `json:"secure"`
+	ComplianceCountWarning  int    `json:"compliance_count_warning,omitempty"`
+	ComplianceCountCritical int    `json:"compliance_count_critical,omitempty"`
+}
+
+// ModifySnapshotPolicyParam holds the settings of a snapshot policy to change, the zero values are left unchanged
+type ModifySnapshotPolicyParam struct {
+	SnapshotPolicyName      string `json:"snapshot_policy_name,omitempty"`
+	Interval                string `json:"interval,omitempty"`
+	OffsetMins              int    `json:"offset_mins,omitempty"`
+	SnapshotCount           int    `json:"snapshot_count,omitempty"`
+	ComplianceCountWarning  int    `json:"compliance_count_warning,omitempty"`
+	ComplianceCountCritical int    `json:"compliance_count_critical,omitempty"`
+}
+
+// SnapshotPolicyStorageGroupParam holds the storage groups to associate to or disassociate from a snapshot policy
+type SnapshotPolicyStorageGroupParam struct {
+	StorageGroupName []string `json:"storage_group_name"`
+}
+
+// UpdateSnapshotPolicyParam holds the action to perform on a snapshot policy
+type UpdateSnapshotPolicyParam struct {
+	Action       string                           `json:"action"`
+	Modify       *ModifySnapshotPolicyParam       `json:"modify,omitempty"`
+	Associate    *SnapshotPolicyStorageGroupParam `json:"associate_to_storage_group_param,omitempty"`
+	Disassociate *SnapshotPolicyStorageGroupParam `json:"disassociate_from_storage_group_param,omitempty"`
+}
+
+// SnapshotPolicyStorageGroupList holds the names of the storage groups associated to a snapshot policy
+type SnapshotPolicyStorageGroupList struct {
+	StorageGroupIDs []string `json:"name"`
+}
+
+// SnapshotPolicyCompliance holds the compliance of the snapshots of a storage group with one of its snapshot policies
+type SnapshotPolicyCompliance struct {
+	SnapshotPolicyName string `json:"snapshot_policy_name"`
+	SnapshotCount      int    `json:"snapshot_count"`
+	Compliance         string `json:"compliance"`
+}
+
+// StorageGroupSnapshotCompliance holds the snapshot compliance of a storage group, which is the worst compliance
+// with its snapshot policies, or NONE if it has no snapshot policy
+type StorageGroupSnapshotCompliance struct {
+	StorageGroupName string                     `json:"storage_group_name"`
+	Compliance       string                     `json:"compliance"`
+	PolicyCompliance []SnapshotPolicyCompliance `json:"sl_compliance"`
+}
@@ -161,6 +161,16 @@ type AddVolumeParam struct {
 	VolumeAttribute  VolumeAttributeType  `json:"volumeAttribute,omitempty"`
 	Emulation        string               `json:"emulation,omitempty"`
 	VolumeIdentifier VolumeIdentifierType `json:"volumeIdentifier,omitempty"`
+	// RemoteSymmSGInfoParam is required to add volumes to an SRDF protected storage group
+	RemoteSymmSGInfoParam *RemoteSymmSGInfoParam `json:"remoteSymmSGInfoParam,omitempty"`
+}
+
+// RemoteSymmSGInfoParam holds the remote array and storage group in which the
+// remote mirrors of new volumes are created
+type RemoteSymmSGInfoParam struct {
+	RemoteSymmetrix1ID  string   `json:"remote_symmetrix_1_id,omitempty"`
+	RemoteSymmetrix1SGs []string `json:"remote_symmetrix_1_sgs,omitempty"`
+	Force               bool     `json:"force,omitempty"`
 }
 
 // ExpandStorageGroupParam holds params related to expanding size of an SG
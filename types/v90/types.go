@@ -222,6 +222,37 @@ type Initiator struct {
 	NumberHostGroups     int64     `json:"num_of_host_groups"`
 	NumberMaskingViews   int64     `json:"number_of_masking_views"`
 	NumberPowerPathHosts int64     `json:"num_of_powerpath_hosts"`
+	Alias                string    `json:"alias,omitempty"`
+}
+
+// InitiatorFilter holds the optional filters for GetInitiatorListWithFilter.
+// LoggedIn and OnFabric are only applied when set. DirectorPort is a director
+// (e.g. "FA-1D") or a director port (e.g. "FA-1D:4").
+type InitiatorFilter struct {
+	InitiatorHBA string
+	ISCSI        bool
+	InHost       bool
+	LoggedIn     *bool
+	OnFabric     *bool
+	DirectorPort string
+	HostGroupID  string
+}
+
+// RenameAliasParam holds the node and port names making up an initiator alias (node_name/port_name)
+type RenameAliasParam struct {
+	NodeName string `json:"node_name"`
+	PortName string `json:"port_name"`
+}
+
+// EditInitiatorActionParam holds the action to perform on an initiator
+type EditInitiatorActionParam struct {
+	RenameAliasParam *RenameAliasParam `json:"renameAliasParam,omitempty"`
+}
+
+// EditInitiatorParam contains action and option to update an initiator
+type EditInitiatorParam struct {
+	EditInitiatorActionParam *EditInitiatorActionParam `json:"editInitiatorActionParam"`
+	ExecutionOption          string                    `json:"executionOption,omitempty"`
 }
 
 // HostList : list of hosts
@@ -250,20 +281,129 @@ type DirectorIDList struct {
 	DirectorIDs []string `json:"directorId"`
 }
 
+// Director : information about a director
+type Director struct {
+	DirectorID         string `json:"directorId"`
+	DirectorNumber     int64  `json:"director_number"`
+	DirectorSlotNumber int64  `json:"director_slot_number"`
+	Availability       string `json:"availability"`
+	DirectorStatus     string `json:"director_status,omitempty"`
+	NumberOfPorts      int64  `json:"num_of_ports"`
+	NumberOfCores      int64  `json:"num_of_cores"`
+}
+
+// Director types, which are the prefix of the director ID (e.g. FA-1D)
+const (
+	DirectorTypeFA = "FA" // Fibre Channel front end
+	DirectorTypeSE = "SE" // iSCSI front end
+	DirectorTypeEF = "EF" // FICON front end
+	DirectorTypeFN = "FN" // NVMe over Fibre Channel front end
+	DirectorTypeOR = "OR" // multi protocol front end
+	DirectorTypeRF = "RF" // SRDF over Fibre Channel
+	DirectorTypeRE = "RE" // SRDF over GigE
+	DirectorTypeDF = "DF" // back end
+	DirectorTypeIM = "IM" // infrastructure manager
+	DirectorTypeED = "ED" // enginuity data services
+)
+
+// DirectorType returns the type of a director given its ID, e.g. "FA" for "FA-1D"
+func DirectorType(directorID string) string {
+	return strings.ToUpper(strings.SplitN(directorID, "-", 2)[0])
+}
+
+// IsFrontEndDirectorType returns true if directors of the given type have host facing ports
+func IsFrontEndDirectorType(directorType string) bool {
+	switch directorType {
+	case DirectorTypeFA, DirectorTypeSE, DirectorTypeEF, DirectorTypeFN, DirectorTypeOR:
+		return true
+	}
+	return false
+}
+
+// Type returns the director type, e.g. "FA" or "SE"
+func (d *Director) Type() string {
+	return DirectorType(d.DirectorID)
+}
+
+// IsFrontEnd returns true if the director has host facing ports
+func (d *Director) IsFrontEnd() bool {
+	return IsFrontEndDirectorType(d.Type())
+}
+
+// IsOnline returns true if the director is available
+func (d *Director) IsOnline() bool {
+	return strings.EqualFold(d.Availability, DirectorStatusOnline)
+}
+
+// DirectorFilter selects the directors returned by ListDirectors.
+// Types is a list of director types (e.g. DirectorTypeFA); empty matches all types.
+type DirectorFilter struct {
+	Types        []string
+	FrontEndOnly bool
+	OnlineOnly   bool
+}
+
 // PortList : list of ports
 type PortList struct {
 	SymmetrixPortKey []PortKey `json:"symmetrixPortKey"`
 }
 
 // SymmetrixPortType : type of symmetrix port
+// For Fibre Channel ports the Identifier is the port WWN.
 type SymmetrixPortType struct {
-	ISCSITarget bool     `json:"iscsi_target,omitempty"`
-	IPAddresses []string `json:"ip_addresses,omitempty"`
-	Identifier  string   `json:"identifier,omitempty"`
-	Type        string   `json:"type,omitempty"`
+	SymmetrixPortKey  PortKey  `json:"symmetrixPortKey"`
+	ISCSITarget       bool     `json:"iscsi_target,omitempty"`
+	IPAddresses       []string `json:"ip_addresses,omitempty"`
+	Identifier        string   `json:"identifier,omitempty"`
+	Type              string   `json:"type,omitempty"`
+	NetworkID         int64    `json:"network_id,omitempty"`
+	PortStatus        string   `json:"port_status,omitempty"`
+	DirectorStatus    string   `json:"director_status,omitempty"`
+	WWNNode           string   `json:"wwn_node,omitempty"`
+	MaxSpeed          string   `json:"max_speed,omitempty"`
+	NegotiatedSpeed   string   `json:"negotiated_speed,omitempty"`
+	MaxIOPS           int64    `json:"max_iops,omitempty"`
+	NumberOfCores     int64    `json:"num_of_cores,omitempty"`
+	NumOfPortGroups   int64    `json:"num_of_port_groups"`
+	NumOfMaskingViews int64    `json:"num_of_masking_views"`
+	NumOfMappedVols   int64    `json:"num_of_mapped_vols"`
+	VCMState          string   `json:"vcm_state,omitempty"`
+	ACLX              bool     `json:"aclx,omitempty"`
+	PortGroups        []string `json:"portgroup,omitempty"`
+	MaskingViews      []string `json:"maskingview,omitempty"`
 }
 
-// Port is a minimal represation of a Symmetrix Port for iSCSI target purpose
+// IsOnline returns true if both the port and its director are online
+func (p *SymmetrixPortType) IsOnline() bool {
+	return strings.EqualFold(p.PortStatus, PortStatusOn) && strings.EqualFold(p.DirectorStatus, DirectorStatusOnline)
+}
+
+// Port is the represation of a Symmetrix front end Port
 type Port struct {
 	SymmetrixPort SymmetrixPortType `json:"symmetrixPort"`
 }
+
+// Port types and statuses reported by Unisphere
+const (
+	PortTypeFibreChannel = "FibreChannel"
+	PortTypeGigE         = "GigE"
+	PortStatusOn         = "ON"
+	DirectorStatusOnline = "Online"
+)
+
+// PortFilter selects the front end ports returned by GetFrontEndPorts.
+// An empty Type matches all port types.
+type PortFilter struct {
+	Type       string
+	OnlineOnly bool
+}
+
+// ISCSITarget : an iSCSI target exposed by a Symmetrix port.
+// IQN is the target name, PortalIPs are the IP interfaces the target
+// can be reached on and DirectorPort identifies the port exposing it.
+type ISCSITarget struct {
+	IQN          string   `json:"iqn"`
+	PortalIPs    []string `json:"portal_ips"`
+	NetworkID    int64    `json:"network_id"`
+	DirectorPort PortKey  `json:"director_port"`
+}
@@ -63,6 +63,13 @@ type Volume struct {
 	Message          string                 `json:"message"`
 	SnapSource       bool                   `json:"snapvx_source"`
 	SnapTarget       bool                   `json:"snapvx_target"`
+	RDFGroupIDList   []RDFGroupID           `json:"rdfGroupId,omitempty"`
+}
+
+// RDFGroupID : number and label of an RDF group the volume is paired in
+type RDFGroupID struct {
+	RDFGroupNumber int    `json:"rdf_group_number"`
+	Label          string `json:"label,omitempty"`
 }
 
 // FreeVolumeParam : boolean value representing data to be freed
@@ -14,16 +14,24 @@
 package pmax
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/DATA-DOG/godog"
+	"github.com/dell/gopowermax/api"
 	"github.com/dell/gopowermax/mock"
 	types "github.com/dell/gopowermax/types/v90"
+	log "github.com/sirupsen/logrus"
 )
 
 const (
@@ -41,6 +49,10 @@ const (
 	mvID                   = "12se0042_mv"
 	testFCInitiatorWWN     = "10000090fa66060a"
 	testFCInitiator        = "FA-1D:4:10000090fa66060a"
+	testTargetIQNPrefix    = "iqn.1992-04.com.emc:600009700bcbb70e3287017400000001."
+	linkStorageGroup       = "CSI-Test-SG-Link"
+	// linkStateTimeout lets WaitForLinkState check a link 5 times with the 10ms sleep of the unit tests
+	linkStateTimeout = 40 * time.Millisecond
 )
 
 type uMV struct {
@@ -55,6 +67,8 @@ type unitContext struct {
 	nGoRoutines int
 	client      Pmax
 	err         error // First error observed
+	// defaultClient holds the client replaced by a client using another API version for the scenario
+	defaultClient Pmax
 
 	symIDList          *types.SymmetrixIDList
 	sym                *types.Symmetrix
@@ -67,14 +81,21 @@ type unitContext struct {
 	storagePoolList    *types.StoragePoolList
 	portGroupList      *types.PortGroupList
 	portGroup          *types.PortGroup
+	portGroupDetails   *types.PortGroupDetails
 	initiatorList      *types.InitiatorList
 	initiator          *types.Initiator
+	initiators         []*types.Initiator
 	hostList           *types.HostList
 	host               *types.Host
 	maskingViewList    *types.MaskingViewList
 	maskingView        *types.MaskingView
 	uMaskingView       *uMV
 	addressList        []string
+	iscsiTargets       []types.ISCSITarget
+	ports              []*types.Port
+	portKeys           []types.PortKey
+	director           *types.Director
+	directors          []*types.Director
 	storagePool        *types.StoragePool
 	volIDList          []string
 	hostID             string
@@ -89,6 +110,30 @@ type unitContext struct {
 	volSnapGenerationList *types.VolumeSnapshotGenerations
 	volSnapGenerationInfo *types.VolumeSnapshotGeneration
 	volResultPrivate      *types.VolumeResultPrivate
+	rdfGroupList          *types.RDFGroupList
+	rdfGroup              *types.RDFGroup
+	rdfGroupVolumeList    *types.RDFGroupVolumeList
+	rdfDevicePair         *types.RDFDevicePair
+	sgRDFInfo             *types.SGRDFInfo
+	metroStatus           *types.MetroStatus
+	sgSnapshot            *types.StorageGroupSnapshot
+	sgSnapshotList        *types.StorageGroupSnapshotList
+	sgSnapshotGenList     *types.StorageGroupSnapshotGenerationList
+	snapshotLink          *types.LinkSnapshotGenInfo
+	snapshotRetention     types.SnapshotGenerations
+	snapshotGCReport      *types.SnapshotGCReport
+	snapshotGenerations   types.SnapshotGenerations
+	consistentSnapshot    *types.ConsistentSnapshot
+	replicationGraph      *types.ReplicationGraph
+	snapshotPolicy        *types.SnapshotPolicy
+	snapshotPolicyList    *types.SnapshotPolicyList
+	sgSnapshotCompliance  *types.StorageGroupSnapshotCompliance
+	performanceKeys       []types.PerformanceKey
+	performanceResult     *types.PerformanceResult
+	observer              *recordingObserver
+	tracer                *recordingTracer
+	wireLog               *bytes.Buffer
+	redactedJSON          string
 
 	inducedErrors struct {
 		badCredentials bool
@@ -108,8 +153,10 @@ func (c *unitContext) reset() {
 	c.storageGroupIDList = nil
 	c.portGroupList = nil
 	c.portGroup = nil
+	c.portGroupDetails = nil
 	c.initiatorList = nil
 	c.initiator = nil
+	c.initiators = nil
 	c.hostList = nil
 	c.host = nil
 	c.jobIDList = nil
@@ -119,7 +166,14 @@ func (c *unitContext) reset() {
 	c.uMaskingView = nil
 	c.maskingView = nil
 	c.storagePool = nil
+	c.iscsiTargets = nil
+	c.ports = nil
+	c.portKeys = nil
+	c.director = nil
+	c.directors = nil
 	MAXJobRetryCount = 5
+	MAXSnapshotStateRetryCount = 5
+	SnapshotStateRetrySleepDuration = 10 * time.Millisecond
 	c.volIDList = make([]string, 0)
 	c.hostID = ""
 	c.hostGroupID = ""
@@ -133,6 +187,34 @@ func (c *unitContext) reset() {
 	c.volSnapGenerationList = nil
 	c.volSnapGenerationInfo = nil
 	c.volResultPrivate = nil
+	c.rdfGroupList = nil
+	c.rdfGroup = nil
+	c.rdfGroupVolumeList = nil
+	c.rdfDevicePair = nil
+	c.sgRDFInfo = nil
+	c.metroStatus = nil
+	c.sgSnapshot = nil
+	c.sgSnapshotList = nil
+	c.sgSnapshotGenList = nil
+	c.snapshotLink = nil
+	c.snapshotRetention = nil
+	c.snapshotGCReport = nil
+	c.snapshotGenerations = nil
+	c.consistentSnapshot = nil
+	c.replicationGraph = nil
+	c.snapshotPolicy = nil
+	c.snapshotPolicyList = nil
+	c.sgSnapshotCompliance = nil
+	c.performanceKeys = nil
+	c.performanceResult = nil
+	c.observer = nil
+	c.tracer = nil
+	c.wireLog = nil
+	c.redactedJSON = ""
+	if c.defaultClient != nil {
+		c.client = c.defaultClient
+		c.defaultClient = nil
+	}
 
 }
 
@@ -163,6 +245,7 @@ func (c *unitContext) iInduceError(errorType string) error {
 	mock.InducedErrors.MaskingViewAlreadyExists = false
 	mock.InducedErrors.DeleteMaskingViewError = false
 	mock.InducedErrors.CreateMaskingViewError = false
+	mock.InducedErrors.RenameMaskingViewError = false
 	mock.InducedErrors.PortGroupNotFoundError = false
 	mock.InducedErrors.InitiatorGroupNotFoundError = false
 	mock.InducedErrors.StorageGroupNotFoundError = false
@@ -172,6 +255,31 @@ func (c *unitContext) iInduceError(errorType string) error {
 	mock.InducedErrors.UpdateHostError = false
 	mock.InducedErrors.GetPortError = false
 	mock.InducedErrors.GetDirectorError = false
+	mock.InducedErrors.GetInitiatorByIDError = false
+	mock.InducedErrors.UpdateInitiatorError = false
+	mock.InducedErrors.GetRDFGroupError = false
+	mock.InducedErrors.CreateSGReplicaError = false
+	mock.InducedErrors.ModifySGReplicaError = false
+	mock.InducedErrors.DeleteSGReplicaError = false
+	mock.InducedErrors.CreateSGSnapshotError = false
+	mock.InducedErrors.GetSGSnapshotError = false
+	mock.InducedErrors.ModifySGSnapshotError = false
+	mock.InducedErrors.DeleteSGSnapshotError = false
+	mock.InducedErrors.RestoreSnapshotError = false
+	mock.InducedErrors.RestoreInProgressError = false
+	mock.InducedErrors.LinkSnapshotError = false
+	mock.InducedErrors.DeleteSnapshotError = false
+	mock.InducedErrors.TargetNotDefinedError = false
+	mock.InducedErrors.SnapshotCopyInProgressError = false
+	mock.InducedErrors.SetSnapshotTTLError = false
+	mock.InducedErrors.CreateSnapshotError = false
+	mock.InducedErrors.SnapshotExpired = false
+	mock.InducedErrors.GetSnapshotPolicyError = false
+	mock.InducedErrors.CreateSnapshotPolicyError = false
+	mock.InducedErrors.ModifySnapshotPolicyError = false
+	mock.InducedErrors.DeleteSnapshotPolicyError = false
+	mock.InducedErrors.GetPerformanceKeysError = false
+	mock.InducedErrors.GetPerformanceMetricsError = false
 	mock.InducedErrors.GetStoragePoolError = false
 
 	switch errorType {
@@ -227,6 +335,8 @@ func (c *unitContext) iInduceError(errorType string) error {
 		mock.InducedErrors.MaskingViewAlreadyExists = true
 	case "DeleteMaskingViewError":
 		mock.InducedErrors.DeleteMaskingViewError = true
+	case "RenameMaskingViewError":
+		mock.InducedErrors.RenameMaskingViewError = true
 	case "PortGroupNotFoundError":
 		mock.InducedErrors.PortGroupNotFoundError = true
 	case "InitiatorGroupNotFoundError":
@@ -245,6 +355,58 @@ func (c *unitContext) iInduceError(errorType string) error {
 		mock.InducedErrors.GetPortError = true
 	case "GetDirectorError":
 		mock.InducedErrors.GetDirectorError = true
+	case "GetInitiatorByIDError":
+		mock.InducedErrors.GetInitiatorByIDError = true
+	case "UpdateInitiatorError":
+		mock.InducedErrors.UpdateInitiatorError = true
+	case "GetRDFGroupError":
+		mock.InducedErrors.GetRDFGroupError = true
+	case "CreateSGReplicaError":
+		mock.InducedErrors.CreateSGReplicaError = true
+	case "ModifySGReplicaError":
+		mock.InducedErrors.ModifySGReplicaError = true
+	case "DeleteSGReplicaError":
+		mock.InducedErrors.DeleteSGReplicaError = true
+	case "CreateSGSnapshotError":
+		mock.InducedErrors.CreateSGSnapshotError = true
+	case "GetSGSnapshotError":
+		mock.InducedErrors.GetSGSnapshotError = true
+	case "ModifySGSnapshotError":
+		mock.InducedErrors.ModifySGSnapshotError = true
+	case "DeleteSGSnapshotError":
+		mock.InducedErrors.DeleteSGSnapshotError = true
+	case "RestoreSnapshotError":
+		mock.InducedErrors.RestoreSnapshotError = true
+	case "RestoreInProgressError":
+		mock.InducedErrors.RestoreInProgressError = true
+	case "LinkSnapshotError":
+		mock.InducedErrors.LinkSnapshotError = true
+	case "DeleteSnapshotError":
+		mock.InducedErrors.DeleteSnapshotError = true
+	case "TargetNotDefinedError":
+		mock.InducedErrors.TargetNotDefinedError = true
+	case "SnapshotCopyInProgressError":
+		mock.InducedErrors.SnapshotCopyInProgressError = true
+	case "CreateSnapshotError":
+		mock.InducedErrors.CreateSnapshotError = true
+	case "SetSnapshotTTLError":
+		mock.InducedErrors.SetSnapshotTTLError = true
+	case "SnapshotExpired":
+		mock.InducedErrors.SnapshotExpired = true
+	case "GetSnapshotPolicyError":
+		mock.InducedErrors.GetSnapshotPolicyError = true
+	case "CreateSnapshotPolicyError":
+		mock.InducedErrors.CreateSnapshotPolicyError = true
+	case "ModifySnapshotPolicyError":
+		mock.InducedErrors.ModifySnapshotPolicyError = true
+	case "DeleteSnapshotPolicyError":
+		mock.InducedErrors.DeleteSnapshotPolicyError = true
+	case "GetPerformanceKeysError":
+		mock.InducedErrors.GetPerformanceKeysError = true
+	case "GetPerformanceMetricsError":
+		mock.InducedErrors.GetPerformanceMetricsError = true
+	case "GetPerformancePageError":
+		mock.InducedErrors.GetPerformancePageError = true
 	case "GetStoragePoolError":
 		mock.InducedErrors.GetStoragePoolError = true
 	case "GetSymVolumeError":
@@ -286,6 +448,26 @@ func (c *unitContext) aValidConnection() error {
 	return nil
 }
 
+func (c *unitContext) aValidConnectionWithAPIVersion(version string) error {
+	if err := c.aValidConnection(); err != nil {
+		return err
+	}
+	client, err := NewClientWithArgs(mockServer.URL, version, "", true, false)
+	if err != nil {
+		return err
+	}
+	err = client.Authenticate(&ConfigConnect{
+		Username: defaultUsername,
+		Password: defaultPassword,
+	})
+	if err != nil {
+		return err
+	}
+	c.defaultClient = c.client
+	c.client = client
+	return nil
+}
+
 func (c *unitContext) checkGoRoutines(tag string) {
 	goroutines := runtime.NumGoroutine()
 	fmt.Printf("goroutines %s new %d old groutines %d\n", tag, goroutines, c.nGoRoutines)
@@ -446,11 +628,8 @@ func (c *unitContext) iValidateVolumeSize(volumeID string, sizeStr string) error
 	size, err := strconv.Atoi(sizeStr)
 	if err == nil && float64(size) != c.vol.CapacityGB {
 		return fmt.Errorf("Expected volume %s to be size %s, but was %d", volumeID, sizeStr, size)
-	} else {
-		return err
 	}
-
-	return nil
+	return err
 }
 
 func (c *unitContext) iCallGetStorageGroupIDList() error {
@@ -544,6 +723,407 @@ func (c *unitContext) iGetAValidJobWithStateIfNoError(expectedState string) erro
 	return nil
 }
 
+// recordingObserver records the notifications of a client
+type recordingObserver struct {
+	NoopObserver
+	mutex    sync.Mutex
+	started  []RequestEvent
+	requests []RequestEvent
+	retries  []RetryEvent
+	jobWaits []JobWaitEvent
+	calls    []CallEvent
+}
+
+func (o *recordingObserver) RequestStart(event RequestEvent) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.started = append(o.started, event)
+}
+
+func (o *recordingObserver) RequestEnd(event RequestEvent) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.requests = append(o.requests, event)
+}
+
+func (o *recordingObserver) Retry(event RetryEvent) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.retries = append(o.retries, event)
+}
+
+func (o *recordingObserver) JobWait(event JobWaitEvent) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.jobWaits = append(o.jobWaits, event)
+}
+
+func (o *recordingObserver) CallEnd(event CallEvent) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.calls = append(o.calls, event)
+}
+
+func (c *unitContext) aValidConnectionWithAnObserver() error {
+	if err := c.aValidConnection(); err != nil {
+		return err
+	}
+	// the observer is added to a client of the scenario, which reset() replaces by the default client
+	if err := c.aValidConnectionWithAPIVersion(c.client.(*Client).version); err != nil {
+		return err
+	}
+	c.observer = &recordingObserver{}
+	c.client.AddObserver(c.observer)
+	return nil
+}
+
+func (c *unitContext) theObserverSawRequestsEndingWithWithStatus(count int, method, urlTemplate string, status int) error {
+	if len(c.observer.started) != len(c.observer.requests) {
+		return fmt.Errorf("Expected as many requests started as ended but got %d and %d", len(c.observer.started), len(c.observer.requests))
+	}
+	seen := 0
+	for _, request := range c.observer.requests {
+		if request.Method == method && strings.HasSuffix(request.URLTemplate, urlTemplate) && request.StatusCode == status {
+			if request.Duration <= 0 || request.Context == nil {
+				return fmt.Errorf("Expected the duration and context of request %v", request)
+			}
+			seen++
+		}
+	}
+	if seen != count {
+		return fmt.Errorf("Expected %d %s requests to %s with status %d but got %v", count, method, urlTemplate, status, c.observer.requests)
+	}
+	return nil
+}
+
+func (c *unitContext) theObserverSawJobWaitsWithStatusAfterPolls(count int, status string, polls int) error {
+	if len(c.observer.jobWaits) != count {
+		return fmt.Errorf("Expected %d job waits but got %v", count, c.observer.jobWaits)
+	}
+	for _, jobWait := range c.observer.jobWaits {
+		if jobWait.Status != status || jobWait.Polls != polls || jobWait.JobID != "myjob" || (jobWait.Err != nil) != (c.err != nil) {
+			return fmt.Errorf("Expected a job wait with status %s after %d polls but got %v", status, polls, jobWait)
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) theObserverSawRetriesOf(count int, operation string) error {
+	seen := 0
+	for _, retry := range c.observer.retries {
+		if retry.Operation == operation {
+			seen++
+			if retry.Attempt != seen+1 {
+				return fmt.Errorf("Expected retry %d of %s to be attempt %d but got %d", seen, operation, seen+1, retry.Attempt)
+			}
+		}
+	}
+	if seen != count {
+		return fmt.Errorf("Expected %d retries of %s but got %v", count, operation, c.observer.retries)
+	}
+	return nil
+}
+
+func (c *unitContext) theObserverSawACallTo(functionName string) error {
+	for _, call := range c.observer.calls {
+		if call.Function == functionName && call.Duration > 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("Expected a call to %s but got %v", functionName, c.observer.calls)
+}
+
+type recordingSpanKey struct{}
+
+// recordingSpan is a span of a recordingTracer
+type recordingSpan struct {
+	mutex      sync.Mutex
+	name       string
+	traceID    string
+	spanID     string
+	parent     *recordingSpan
+	attributes map[string]interface{}
+	ended      bool
+	err        error
+}
+
+func (s *recordingSpan) SetAttribute(key string, value interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.attributes[key] = value
+}
+
+func (s *recordingSpan) TraceParent() string {
+	return "00-" + s.traceID + "-" + s.spanID + "-01"
+}
+
+func (s *recordingSpan) End(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ended = true
+	s.err = err
+}
+
+// recordingTracer records the spans of a client
+type recordingTracer struct {
+	mutex sync.Mutex
+	spans []*recordingSpan
+}
+
+func (t *recordingTracer) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	span := &recordingSpan{
+		name:       name,
+		spanID:     fmt.Sprintf("%016x", len(t.spans)+1),
+		attributes: make(map[string]interface{}),
+	}
+	if parent, ok := ctx.Value(recordingSpanKey{}).(*recordingSpan); ok {
+		span.parent = parent
+		span.traceID = parent.traceID
+	} else {
+		span.traceID = fmt.Sprintf("%032x", len(t.spans)+1)
+	}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, recordingSpanKey{}, span), span
+}
+
+func (t *recordingTracer) getSpans(name string) []*recordingSpan {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	spans := make([]*recordingSpan, 0)
+	for _, span := range t.spans {
+		if span.name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func (c *unitContext) aValidConnectionWithATracerAndCallerSpan(callerSpan string) error {
+	if err := c.aValidConnection(); err != nil {
+		return err
+	}
+	// the tracer is set on a client of the scenario, which reset() replaces by the default client
+	if err := c.aValidConnectionWithAPIVersion(c.client.(*Client).version); err != nil {
+		return err
+	}
+	c.tracer = &recordingTracer{}
+	c.client.SetTracer(c.tracer)
+	if callerSpan != "" {
+		ctx, _ := c.tracer.StartSpan(context.Background(), callerSpan)
+		c.client = c.client.WithContext(ctx)
+	}
+	return nil
+}
+
+func (c *unitContext) theTracerRecordedSpansChildrenOf(count int, name, parentName string) error {
+	spans := c.tracer.getSpans(name)
+	if len(spans) != count {
+		return fmt.Errorf("Expected %d %s spans but got %d", count, name, len(spans))
+	}
+	for _, span := range spans {
+		if !span.ended {
+			return fmt.Errorf("Expected span %s to be ended", name)
+		}
+		if parentName == "" && span.parent != nil {
+			return fmt.Errorf("Expected span %s to be a root span but its parent is %s", name, span.parent.name)
+		}
+		if parentName != "" && (span.parent == nil || span.parent.name != parentName) {
+			return fmt.Errorf("Expected span %s to be a child of %s", name, parentName)
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) theSpanHasAttributeWithValue(name, key, value string) error {
+	for _, span := range c.tracer.getSpans(name) {
+		if fmt.Sprint(span.attributes[key]) != value {
+			return fmt.Errorf("Expected attribute %s of span %s to be %s but got %v", key, name, value, span.attributes)
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) theSpanEndedWithError(name, errormsg string) error {
+	for _, span := range c.tracer.getSpans(name) {
+		if errormsg == "none" && span.err != nil {
+			return fmt.Errorf("Expected span %s to end without error but got %s", name, span.err.Error())
+		}
+		if errormsg != "none" && (span.err == nil || !strings.Contains(span.err.Error(), errormsg)) {
+			return fmt.Errorf("Expected span %s to end with error %s but got %v", name, errormsg, span.err)
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) iCallGetVolumeByIDConcurrentlyTimes(volID string, count int) error {
+	errs := make([]error, count)
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = c.client.GetVolumeByID(symID, volID)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			c.err = err
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) eachSpanHasChild(name string, count int, childName string) error {
+	for _, span := range c.tracer.getSpans(name) {
+		children := 0
+		for _, child := range c.tracer.getSpans(childName) {
+			if child.parent == span {
+				children++
+			}
+		}
+		if children != count {
+			return fmt.Errorf("Expected each %s span to have %d %s children but one has %d", name, count, childName, children)
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) theRequestsCarriedTheTraceContextOfTheirSpan() error {
+	traceParents := make([]string, 0)
+	for _, method := range []string{"GET", "POST", "PUT", "DELETE"} {
+		for _, span := range c.tracer.getSpans("HTTP " + method) {
+			if span.attributes[AttributeHTTPRoute] != URLTemplate(fmt.Sprint(span.attributes[AttributeHTTPURL])) {
+				return fmt.Errorf("Expected the route of request span %v", span.attributes)
+			}
+			traceParents = append(traceParents, span.TraceParent())
+		}
+	}
+	if len(traceParents) != len(mock.Data.TraceParents) {
+		return fmt.Errorf("Expected a traceparent for each of the %d request spans but got %v", len(traceParents), mock.Data.TraceParents)
+	}
+	for _, traceParent := range traceParents {
+		if !stringInSlice(traceParent, mock.Data.TraceParents) {
+			return fmt.Errorf("Expected a request with traceparent %s but got %v", traceParent, mock.Data.TraceParents)
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) aValidConnectionWithWireLoggingOfAtMostBodyBytes(maxBodySize int) error {
+	if err := c.aValidConnection(); err != nil {
+		return err
+	}
+	// the wire logging is enabled on a client of the scenario, which reset() replaces by the default client
+	if err := c.aValidConnectionWithAPIVersion(c.client.(*Client).version); err != nil {
+		return err
+	}
+	c.wireLog = &bytes.Buffer{}
+	logger := log.New()
+	logger.Out = c.wireLog
+	logger.Formatter = &log.JSONFormatter{}
+	logger.Level = log.DebugLevel
+	c.client.SetWireLogging(&api.WireLogConfig{Logger: logger, MaxBodySize: maxBodySize})
+	return nil
+}
+
+func (c *unitContext) getWireLogEntries() ([]map[string]interface{}, error) {
+	entries := make([]map[string]interface{}, 0)
+	decoder := json.NewDecoder(bytes.NewReader(c.wireLog.Bytes()))
+	for decoder.More() {
+		entry := make(map[string]interface{})
+		if err := decoder.Decode(&entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (c *unitContext) theWireLogHasRequestsAndResponsesSharingTheirCorrelationID(count int) error {
+	entries, err := c.getWireLogEntries()
+	if err != nil {
+		return err
+	}
+	requests := make(map[string]bool)
+	responses := make(map[string]bool)
+	for _, entry := range entries {
+		correlationID := fmt.Sprint(entry["correlation_id"])
+		switch entry["msg"] {
+		case "POWERMAX HTTP REQUEST":
+			requests[correlationID] = true
+		case "POWERMAX HTTP RESPONSE":
+			if entry["status"] == nil {
+				return fmt.Errorf("Expected the status of response %v", entry)
+			}
+			responses[correlationID] = true
+		}
+		if entry["level"] != "debug" {
+			return fmt.Errorf("Expected a debug entry but got %v", entry)
+		}
+	}
+	if len(requests) != count || len(responses) != count {
+		return fmt.Errorf("Expected %d requests and responses but got %d and %d", count, len(requests), len(responses))
+	}
+	for correlationID := range requests {
+		if !responses[correlationID] {
+			return fmt.Errorf("Expected a response with the correlation ID %s", correlationID)
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) theWireLogHasNoCredentials() error {
+	wireLog := c.wireLog.String()
+	basicAuthString := base64.StdEncoding.EncodeToString([]byte(defaultUsername + ":" + defaultPassword))
+	if strings.Contains(wireLog, basicAuthString) || strings.Contains(wireLog, defaultPassword) {
+		return fmt.Errorf("Expected the credentials to be redacted from the wire log %s", wireLog)
+	}
+	if c.wireLog.Len() > 0 && !strings.Contains(wireLog, "Authorization: "+api.Redacted) {
+		return fmt.Errorf("Expected the redacted Authorization header in the wire log %s", wireLog)
+	}
+	return nil
+}
+
+func (c *unitContext) theWireLogBodiesAreTruncatedToBytes(maxBodySize int) error {
+	entries, err := c.getWireLogEntries()
+	if err != nil {
+		return err
+	}
+	bodies := 0
+	for _, entry := range entries {
+		body, ok := entry["body"].(string)
+		if !ok {
+			continue
+		}
+		bodies++
+		if i := strings.Index(body, "... ("); i >= 0 {
+			body = body[:i]
+		}
+		if len(body) > maxBodySize {
+			return fmt.Errorf("Expected a body of at most %d bytes but got %s", maxBodySize, body)
+		}
+	}
+	if bodies == 0 && c.wireLog.Len() > 0 {
+		return fmt.Errorf("Expected bodies in the wire log %s", c.wireLog.String())
+	}
+	return nil
+}
+
+func (c *unitContext) iRedactTheJSON(document string) error {
+	c.redactedJSON = string(api.RedactJSON([]byte(document)))
+	return nil
+}
+
+func (c *unitContext) theRedactedJSONIs(expected string) error {
+	if c.redactedJSON != expected {
+		return fmt.Errorf("Expected the redacted JSON %s but got %s", expected, c.redactedJSON)
+	}
+	return nil
+}
+
 func (c *unitContext) iCallWaitOnJobCompletion() error {
 	c.job, c.err = c.client.WaitOnJobCompletion(symID, "myjob")
 	return nil
@@ -715,6 +1295,53 @@ func (c *unitContext) iCallDeleteMaskingView() error {
 	return nil
 }
 
+func (c *unitContext) iCallRenameMaskingViewWith(newName string) error {
+	c.maskingView, c.err = c.client.RenameMaskingView(symID, c.uMaskingView.maskingViewID, newName)
+	return nil
+}
+
+func (c *unitContext) iGetAValidMaskingViewWithNameIfNoError(mvName string) error {
+	if c.err != nil {
+		return nil
+	}
+	if c.maskingView == nil || c.maskingView.MaskingViewID != mvName {
+		return fmt.Errorf("Expected masking view %s but got %v", mvName, c.maskingView)
+	}
+	c.uMaskingView.maskingViewID = mvName
+	return c.iGetAValidMaskingViewIfNoError()
+}
+
+func (c *unitContext) iCallGetMaskingViewsFor(resourceType string) error {
+	switch resourceType {
+	case "Host":
+		c.maskingViewList, c.err = c.client.GetMaskingViewsForHost(symID, c.uMaskingView.hostID)
+	case "StorageGroup":
+		c.maskingViewList, c.err = c.client.GetMaskingViewsForStorageGroup(symID, c.uMaskingView.storageGroupID)
+	case "PortGroup":
+		c.maskingViewList, c.err = c.client.GetMaskingViewsForPortGroup(symID, c.uMaskingView.portGroupID)
+	default:
+		return fmt.Errorf("unknown resource type: %s", resourceType)
+	}
+	return nil
+}
+
+func (c *unitContext) iCallGetMaskingViewsForHostWith(hostOrHostGroupID string) error {
+	c.maskingViewList, c.err = c.client.GetMaskingViewsForHost(symID, hostOrHostGroupID)
+	return nil
+}
+
+func (c *unitContext) theMaskingViewListDoesNotContainIfNoError(mvID string) error {
+	if c.err != nil {
+		return nil
+	}
+	for _, id := range c.maskingViewList.MaskingViewIDs {
+		if id == mvID {
+			return fmt.Errorf("Expected %s to be filtered out of MaskingViewList but it wasn't", mvID)
+		}
+	}
+	return nil
+}
+
 func (c *unitContext) iHaveAPortGroup() error {
 	mock.AddPortGroup(testPortGroup, "ISCSI", []string{"SE-1E:000"})
 	return nil
@@ -820,6 +1447,53 @@ func (c *unitContext) thePortGroupShouldNotExist(groupName string) error {
 	return nil
 }
 
+func (c *unitContext) iCallRenamePortGroupTo(groupName string, newName string) error {
+	c.portGroup, c.err = c.client.RenamePortGroup(symID, groupName, newName)
+	return nil
+}
+
+func (c *unitContext) iCallAddPortsToPortGroup(groupName string, strSliceOfPorts string) error {
+	if c.err != nil {
+		return nil
+	}
+	ports := convertStringSliceOfPortsToPortKeys(strSliceOfPorts)
+	c.portGroup, c.err = c.client.AddPortsToPortGroup(symID, groupName, ports)
+	return nil
+}
+
+func (c *unitContext) iCallRemovePortsFromPortGroup(groupName string, strSliceOfPorts string) error {
+	if c.err != nil {
+		return nil
+	}
+	ports := convertStringSliceOfPortsToPortKeys(strSliceOfPorts)
+	c.portGroup, c.err = c.client.RemovePortsFromPortGroup(symID, groupName, ports)
+	return nil
+}
+
+func (c *unitContext) iCallGetPortGroupDetails() error {
+	c.portGroupDetails, c.err = c.client.GetPortGroupDetails(symID, testPortGroup)
+	return nil
+}
+
+func (c *unitContext) iGetValidPortGroupDetailsIfNoError() error {
+	if c.err != nil {
+		return nil
+	}
+	if c.portGroupDetails == nil || c.portGroupDetails.PortGroup == nil {
+		return fmt.Errorf("Expected PortGroupDetails but received none")
+	}
+	if len(c.portGroupDetails.Ports) != len(c.portGroupDetails.PortGroup.SymmetrixPortKey) {
+		return fmt.Errorf("Expected details for %d ports but received %d",
+			len(c.portGroupDetails.PortGroup.SymmetrixPortKey), len(c.portGroupDetails.Ports))
+	}
+	for _, port := range c.portGroupDetails.Ports {
+		if port.SymmetrixPort.Identifier == "" || port.SymmetrixPort.Type == "" || port.SymmetrixPort.PortStatus == "" {
+			return fmt.Errorf("Expected complete port details but received %#v", port.SymmetrixPort)
+		}
+	}
+	return nil
+}
+
 func (c *unitContext) iGetPortGroupIfNoError(groupName string) error {
 	if c.err != nil {
 		return nil
@@ -938,26 +1612,114 @@ func (c *unitContext) iGetAValidInitiatorListIfNoError() error {
 	return nil
 }
 
-func (c *unitContext) iCallGetInitiatorByID() error {
+func (c *unitContext) iHaveALoggedOutFCInitiator() error {
+	mock.AddInitiator(testFCInitiator, testFCInitiatorWWN, "Fibre", []string{"FA-1D:4"}, "")
+	return mock.SetInitiatorLoginStatus(testFCInitiator, false, false)
+}
+
+func (c *unitContext) iCallGetInitiatorListWithFilter(filterName string) error {
+	loggedIn := true
+	loggedOut := false
+	filter := &types.InitiatorFilter{}
+	switch filterName {
+	case "iscsi":
+		filter.ISCSI = true
+	case "in_host":
+		filter.InHost = true
+	case "logged_in":
+		filter.LoggedIn = &loggedIn
+	case "logged_out":
+		filter.LoggedIn = &loggedOut
+	case "off_fabric":
+		filter.OnFabric = &loggedOut
+	case "director":
+		filter.DirectorPort = "FA-1D"
+	case "director_port":
+		filter.DirectorPort = "FA-2D:1"
+	case "host_group":
+		filter.HostGroupID = "CSI-Test-HG"
+	case "escaped_host_group":
+		filter.HostGroupID = "#CSI Test&HG"
+	case "hba":
+		filter.InitiatorHBA = testFCInitiatorWWN
+	}
+	c.initiatorList, c.err = c.client.GetInitiatorListWithFilter(symID, filter)
+	return nil
+}
+
+func (c *unitContext) iGetInitiatorsInTheListIfNoError(count int) error {
+	if c.err != nil {
+		return nil
+	}
+	if c.initiatorList == nil || len(c.initiatorList.InitiatorIDs) != count {
+		return fmt.Errorf("Expected %d initiators in the list but got %v", count, c.initiatorList)
+	}
+	return nil
+}
+
+func (c *unitContext) iCallSetInitiatorAlias(nodeName, portName string) error {
 	mock.AddInitiator(testInitiator, testInitiatorIQN, "GigE", []string{"SE-1E:000"}, "")
-	c.initiator, c.err = c.client.GetInitiatorByID(symID, testInitiator)
+	c.initiator, c.err = c.client.SetInitiatorAlias(symID, testInitiator, nodeName, portName)
 	return nil
 }
 
-func (c *unitContext) iGetAValidInitiatorIfNoError() error {
+func (c *unitContext) theInitiatorAliasIsIfNoError(alias string) error {
 	if c.err != nil {
 		return nil
 	}
-
-	if c.initiator.InitiatorID != testInitiatorIQN {
-		return fmt.Errorf("Expected to get initiator %s, but received %s",
-			c.initiator.InitiatorID, testInitiator)
+	if c.initiator == nil || c.initiator.Alias != alias {
+		return fmt.Errorf("Expected initiator alias %s but got %v", alias, c.initiator)
+	}
+	initiator, err := c.client.GetInitiatorByID(symID, testInitiator)
+	if err != nil {
+		return err
+	}
+	if initiator.Alias != alias {
+		return fmt.Errorf("Expected alias %s to be persisted but got %s", alias, initiator.Alias)
 	}
 	return nil
 }
 
-func (c *unitContext) iHaveAStorageGroup(sgID string) error {
-	c.sgID = sgID
+func (c *unitContext) iCallGetInitiatorsForHost(hostID string) error {
+	c.initiators, c.err = c.client.GetInitiatorsForHost(symID, hostID)
+	return nil
+}
+
+func (c *unitContext) iGetInitiatorsForTheHostIfNoError(count int) error {
+	if c.err != nil {
+		return nil
+	}
+	if len(c.initiators) != count {
+		return fmt.Errorf("Expected %d initiators for the host but got %d", count, len(c.initiators))
+	}
+	for _, initiator := range c.initiators {
+		if initiator.HostID == "" || !initiator.LoggedIn {
+			return fmt.Errorf("Expected a logged in host initiator but got %v", initiator)
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) iCallGetInitiatorByID() error {
+	mock.AddInitiator(testInitiator, testInitiatorIQN, "GigE", []string{"SE-1E:000"}, "")
+	c.initiator, c.err = c.client.GetInitiatorByID(symID, testInitiator)
+	return nil
+}
+
+func (c *unitContext) iGetAValidInitiatorIfNoError() error {
+	if c.err != nil {
+		return nil
+	}
+
+	if c.initiator.InitiatorID != testInitiatorIQN {
+		return fmt.Errorf("Expected to get initiator %s, but received %s",
+			c.initiator.InitiatorID, testInitiator)
+	}
+	return nil
+}
+
+func (c *unitContext) iHaveAStorageGroup(sgID string) error {
+	c.sgID = sgID
 	mock.AddStorageGroup(sgID, "SRP_1", "Diamond")
 	return nil
 }
@@ -1038,6 +1800,139 @@ func (c *unitContext) iRecieveIPAddresses(count int) error {
 	return nil
 }
 
+func (c *unitContext) iCallGetISCSITargets() error {
+	c.iscsiTargets, c.err = c.client.GetISCSITargets(symID)
+	return nil
+}
+
+func (c *unitContext) iCallGetISCSITargetsForPortGroup() error {
+	c.iscsiTargets, c.err = c.client.GetISCSITargetsForPortGroup(symID, testPortGroup)
+	return nil
+}
+
+func (c *unitContext) iRecieveISCSITargets(count int) error {
+	if len(c.iscsiTargets) != count {
+		return fmt.Errorf("Expected to get %d iSCSI targets but recieved %d", count, len(c.iscsiTargets))
+	}
+	for _, target := range c.iscsiTargets {
+		if target.IQN == "" || target.DirectorPort.DirectorID == "" || target.DirectorPort.PortID == "" {
+			return fmt.Errorf("Expected a complete iSCSI target but recieved %#v", target)
+		}
+		if len(target.PortalIPs) == 0 {
+			return fmt.Errorf("Expected iSCSI target %s to have portal IPs", target.IQN)
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) iCallCreateISCSIPortGroupWithTargets(groupName string, targets string) error {
+	iqns := make([]string, 0)
+	for _, dirPort := range convertStringToSlice(targets) {
+		iqns = append(iqns, testTargetIQNPrefix+strings.Replace(dirPort, ":", ".", 1))
+	}
+	c.portGroup, c.err = c.client.CreateISCSIPortGroup(symID, groupName, iqns)
+	return nil
+}
+
+func (c *unitContext) iHaveAFCPortWithStatusAndMappedVolumes(portID, status string, mappedVols int) error {
+	mock.AddFCPort(portID, "5000097300000"+strings.Replace(portID, ":", "", -1), status, int64(mappedVols), 1)
+	return nil
+}
+
+func (c *unitContext) iCallGetFrontEndPortsWithTypeAndOnlineOnly(portType string, onlineOnly string) error {
+	filter := &types.PortFilter{
+		Type:       portType,
+		OnlineOnly: onlineOnly == "true",
+	}
+	c.ports, c.err = c.client.GetFrontEndPorts(symID, filter)
+	return nil
+}
+
+func (c *unitContext) iRecieveFrontEndPorts(count int) error {
+	if c.err != nil {
+		return nil
+	}
+	if len(c.ports) != count {
+		return fmt.Errorf("Expected to get %d front end ports but recieved %d", count, len(c.ports))
+	}
+	for _, port := range c.ports {
+		if port.SymmetrixPort.Identifier == "" || port.SymmetrixPort.SymmetrixPortKey.DirectorID == "" {
+			return fmt.Errorf("Expected complete port details but received %#v", port.SymmetrixPort)
+		}
+		if port.SymmetrixPort.Type == types.PortTypeFibreChannel && port.SymmetrixPort.NegotiatedSpeed == "" {
+			return fmt.Errorf("Expected FC port %s to have a negotiated speed", port.SymmetrixPort.Identifier)
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) iCallSelectLeastLoadedFCPorts(count int) error {
+	c.portKeys, c.err = c.client.SelectLeastLoadedFCPorts(symID, count)
+	return nil
+}
+
+func (c *unitContext) iGetThePorts(strSliceOfPorts string) error {
+	if c.err != nil {
+		return nil
+	}
+	expected := convertStringSliceOfPortsToPortKeys(strSliceOfPorts)
+	if len(expected) != len(c.portKeys) {
+		return fmt.Errorf("Expected ports %v but got %v", expected, c.portKeys)
+	}
+	for i := range expected {
+		if expected[i] != c.portKeys[i] {
+			return fmt.Errorf("Expected ports %v but got %v", expected, c.portKeys)
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) iHaveADirectorWithAvailability(directorID, availability string) error {
+	mock.AddDirector(directorID, availability, 2)
+	return nil
+}
+
+func (c *unitContext) iCallGetDirector(directorID string) error {
+	c.director, c.err = c.client.GetDirector(symID, directorID)
+	return nil
+}
+
+func (c *unitContext) iGetAValidDirectorOfTypeIfNoError(directorID, directorType string) error {
+	if c.err != nil {
+		return nil
+	}
+	if c.director == nil || c.director.DirectorID != directorID {
+		return fmt.Errorf("Expected director %s but got %#v", directorID, c.director)
+	}
+	if c.director.Type() != directorType {
+		return fmt.Errorf("Expected director %s to be of type %s but was %s", directorID, directorType, c.director.Type())
+	}
+	if !c.director.IsOnline() || c.director.NumberOfPorts == 0 || c.director.DirectorSlotNumber == 0 {
+		return fmt.Errorf("Expected director %s to be online with ports and a slot: %#v", directorID, c.director)
+	}
+	return nil
+}
+
+func (c *unitContext) iCallListDirectorsWithTypesFrontEndOnlyOnlineOnly(directorTypes, frontEndOnly, onlineOnly string) error {
+	filter := &types.DirectorFilter{
+		Types:        convertStringToSlice(directorTypes),
+		FrontEndOnly: frontEndOnly == "true",
+		OnlineOnly:   onlineOnly == "true",
+	}
+	c.directors, c.err = c.client.ListDirectors(symID, filter)
+	return nil
+}
+
+func (c *unitContext) iRecieveDirectors(count int) error {
+	if c.err != nil {
+		return nil
+	}
+	if len(c.directors) != count {
+		return fmt.Errorf("Expected to get %d directors but recieved %d", count, len(c.directors))
+	}
+	return nil
+}
+
 func (c *unitContext) iHaveAWhitelistOf(whitelist string) error {
 	// turn the whitelist string into a slice
 	results := convertStringToSlice(whitelist)
@@ -1267,6 +2162,1142 @@ func (c *unitContext) iShouldGetAValidResponseIfNoError() error {
 	return nil
 }
 
+func (c *unitContext) iCallRestoreSnapshotWithAndSnapshot(volIDs, snapID string) error {
+	c.sourceVolumeList = c.createVolumeList(volIDs)
+	c.err = c.client.RestoreSnapshot(symID, snapID, c.sourceVolumeList, 0)
+	return nil
+}
+
+func (c *unitContext) theSnapshotOfIsEstablishedAndNotRestoredIfNoError(snapID, volIDs string) error {
+	if c.err != nil {
+		return nil
+	}
+	for _, volID := range convertStringToSlice(volIDs) {
+		generationInfo, err := c.client.GetSnapshotGenerationInfo(symID, volID, snapID, 0)
+		if err != nil {
+			return err
+		}
+		source := generationInfo.VolumeSnapshotSource
+		if source.State != "Established" || source.IsRestored {
+			return fmt.Errorf("Expected snapshot %s of volume %s to be Established and not restored but got %v", snapID, volID, source)
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) iCallCreateSnapshotWithOptionsWithAndSnapshotTTLInHoursAndSecure(volIDs, snapID string, ttl int64, inHours, secure string) error {
+	c.sourceVolumeList = c.createVolumeList(volIDs)
+	options := &types.SnapshotOptions{
+		TimeToLive:  ttl,
+		TimeInHours: inHours == "true",
+		Secure:      secure == "true",
+	}
+	c.err = c.client.CreateSnapshotWithOptions(symID, snapID, c.sourceVolumeList, options)
+	return nil
+}
+
+func (c *unitContext) iCallSetSnapshotTTLOnWithTTLInHoursAndSecure(snapID, volIDs string, ttl int64, inHours, secure string) error {
+	if c.err != nil {
+		return nil
+	}
+	options := &types.SnapshotOptions{
+		TimeToLive:  ttl,
+		TimeInHours: inHours == "true",
+		Secure:      secure == "true",
+	}
+	c.err = c.client.SetSnapshotTTL(symID, snapID, c.createVolumeList(volIDs), 0, options)
+	return nil
+}
+
+func (c *unitContext) iCallGetSnapshotRetentionWithAndSnapshot(volID, snapID string) error {
+	if c.err != nil {
+		return nil
+	}
+	c.snapshotRetention, c.err = c.client.GetSnapshotRetention(symID, volID, snapID)
+	return nil
+}
+
+func (c *unitContext) theSnapshotRetentionIsSecureWithATimeToLiveOfHoursIfNoError(secure string, hours int) error {
+	if c.err != nil {
+		return nil
+	}
+	if len(c.snapshotRetention) != 1 {
+		return fmt.Errorf("Expected the retention of 1 generation but got %v", c.snapshotRetention)
+	}
+	retention := c.snapshotRetention[0]
+	if retention.Secured != (secure == "true") || retention.TimeToLive != time.Duration(hours)*time.Hour {
+		return fmt.Errorf("Expected a retention with secure %s and a time to live of %d hours but got %v", secure, hours, retention)
+	}
+	if hours == 0 && !retention.ExpiryTime().IsZero() {
+		return fmt.Errorf("Expected no expiry time but got %v", retention.ExpiryTime())
+	}
+	if hours > 0 && !retention.ExpiryTime().Equal(retention.Timestamp.Add(retention.TimeToLive)) {
+		return fmt.Errorf("Expected an expiry time %d hours after %v but got %v", hours, retention.Timestamp, retention.ExpiryTime())
+	}
+	return nil
+}
+
+func (c *unitContext) iCallCloneVolumeToNamedWithCopy(srcVolID, sgID, name, copy string) error {
+	options := &types.CloneVolumeOptions{Copy: copy == "true", Timeout: linkStateTimeout}
+	c.vol, c.err = c.client.CloneVolume(symID, srcVolID, sgID, name, options)
+	return nil
+}
+
+func (c *unitContext) theCloneOfIsLinkedIfNoError(name, srcVolID, linked string) error {
+	if c.err != nil {
+		return nil
+	}
+	if c.vol == nil || c.vol.VolumeIdentifier != name || c.vol.CapacityCYL != mock.Data.VolumeIDToVolume[srcVolID].CapacityCYL {
+		return fmt.Errorf("Expected a clone %s of the size of volume %s but got %v", name, srcVolID, c.vol)
+	}
+	linkedVolumes := mock.Data.SnapIDToLinkedVol[CloneSnapshotPrefix+name+":"+srcVolID]
+	if (linkedVolumes[c.vol.VolumeID] != nil) != (linked == "true") {
+		return fmt.Errorf("Expected the clone %s to be linked %s to volume %s", name, linked, srcVolID)
+	}
+	if _, ok := mock.Data.VolIDToSnapshots[srcVolID][CloneSnapshotPrefix+name]; ok != (linked == "true") {
+		return fmt.Errorf("Expected the snapshot of the clone %s to exist only for a linked clone", name)
+	}
+	return nil
+}
+
+func (c *unitContext) theCloneOfIsUnlinkedFromItsLeftoverSnapshot(name, srcVolID string) error {
+	if c.vol == nil || c.vol.VolumeIdentifier != name {
+		return fmt.Errorf("Expected a clone %s but got %v", name, c.vol)
+	}
+	if mock.Data.SnapIDToLinkedVol[CloneSnapshotPrefix+name+":"+srcVolID][c.vol.VolumeID] != nil {
+		return fmt.Errorf("Expected the clone %s to be unlinked from volume %s", name, srcVolID)
+	}
+	if _, ok := mock.Data.VolIDToSnapshots[srcVolID][CloneSnapshotPrefix+name]; !ok {
+		return fmt.Errorf("Expected the snapshot of the clone %s to be left on volume %s", name, srcVolID)
+	}
+	return nil
+}
+
+func (c *unitContext) theCloneOfIsCleanedUpIfError(name, srcVolID string) error {
+	if c.err == nil {
+		return nil
+	}
+	for volID, vol := range mock.Data.VolumeIDToVolume {
+		if vol != nil && vol.VolumeIdentifier == name {
+			return fmt.Errorf("Expected the clone %s to be deleted but found volume %s", name, volID)
+		}
+	}
+	if _, ok := mock.Data.VolIDToSnapshots[srcVolID][CloneSnapshotPrefix+name]; ok {
+		return fmt.Errorf("Expected the snapshot of the clone %s to be deleted", name)
+	}
+	return nil
+}
+
+func (c *unitContext) snapshotOnWasCreatedHoursAgo(snapID, volID string, hours int) error {
+	if c.err != nil {
+		return nil
+	}
+	snapshot := mock.Data.VolIDToSnapshots[volID][snapID]
+	if snapshot == nil {
+		return fmt.Errorf("Snapshot %s not found on volume %s", snapID, volID)
+	}
+	snapshot.Timestamp = strconv.FormatInt(time.Now().Add(-time.Duration(hours)*time.Hour).Unix(), 10)
+	return nil
+}
+
+// snapshotOnWasTakenAgain shifts the generation of the snapshot, the mock keeping a single generation per snapshot name
+func (c *unitContext) snapshotOnWasTakenAgain(snapID, volID string) error {
+	if c.err != nil {
+		return nil
+	}
+	snapshot := mock.Data.VolIDToSnapshots[volID][snapID]
+	if snapshot == nil {
+		return fmt.Errorf("Snapshot %s not found on volume %s", snapID, volID)
+	}
+	snapshot.Generation++
+	return nil
+}
+
+func (c *unitContext) iCallCollectSnapshotsWithPrefixExpiredMaxAgeHoursOrphanedDryRunAndMaxDeletions(prefix, expired string, maxAge int, orphaned, dryRun string, maxDeletions int) error {
+	policy := &types.SnapshotGCPolicy{
+		NamePrefix:     prefix,
+		Expired:        expired == "true",
+		MaxAge:         time.Duration(maxAge) * time.Hour,
+		Orphaned:       orphaned == "true",
+		DryRun:         dryRun == "true",
+		MaxDeletions:   maxDeletions,
+		DeleteInterval: time.Millisecond,
+	}
+	c.snapshotGCReport, c.err = c.client.CollectSnapshots(symID, policy)
+	return nil
+}
+
+func (c *unitContext) volumeIsRenamed(volID, name string) error {
+	_, err := c.client.RenameVolume(symID, volID, name)
+	return err
+}
+
+func (c *unitContext) iCallCollectSnapshotsOfTheVolumesRenamedWithPrefix(prefix string) error {
+	policy := &types.SnapshotGCPolicy{
+		DeletedVolumePrefix: prefix,
+	}
+	c.snapshotGCReport, c.err = c.client.CollectSnapshots(symID, policy)
+	return nil
+}
+
+func (c *unitContext) theSnapshotCollectionFoundAndDeletedIfNoError(found, deleted string) error {
+	if c.err != nil {
+		return nil
+	}
+	foundSnapshots, deletedSnapshots := make([]string, 0), make([]string, 0)
+	for _, candidate := range c.snapshotGCReport.Candidates {
+		name := candidate.VolumeID + ":" + candidate.SnapshotName
+		foundSnapshots = append(foundSnapshots, name)
+		_, exists := mock.Data.VolIDToSnapshots[candidate.VolumeID][candidate.SnapshotName]
+		if candidate.Deleted == exists {
+			return fmt.Errorf("Expected snapshot %s to exist only if it was not deleted", name)
+		}
+		if candidate.Deleted {
+			deletedSnapshots = append(deletedSnapshots, name)
+		}
+	}
+	if strings.Join(foundSnapshots, ",") != found || strings.Join(deletedSnapshots, ",") != deleted {
+		return fmt.Errorf("Expected to find %s and delete %s but found %v and deleted %v", found, deleted, foundSnapshots, deletedSnapshots)
+	}
+	if c.snapshotGCReport.DeletedCount() != len(deletedSnapshots) || c.snapshotGCReport.FailedCount() != 0 {
+		return fmt.Errorf("Unexpected counts in the snapshot collection report %v", c.snapshotGCReport)
+	}
+	return nil
+}
+
+func (c *unitContext) theSnapshotCollectionSkippedIfNoError(skipped string) error {
+	if c.err != nil {
+		return nil
+	}
+	skippedReasons := make([]string, 0)
+	for _, candidate := range c.snapshotGCReport.Candidates {
+		if candidate.Skipped != "" {
+			skippedReasons = append(skippedReasons, candidate.Skipped)
+		}
+	}
+	if strings.Join(skippedReasons, ",") != skipped {
+		return fmt.Errorf("Expected the snapshot collection to skip %s but skipped %v", skipped, skippedReasons)
+	}
+	return nil
+}
+
+func (c *unitContext) iCallGetVolumeSnapshotGenerationsWith(volID string) error {
+	c.snapshotGenerations, c.err = c.client.GetVolumeSnapshotGenerations(symID, volID)
+	return nil
+}
+
+func (c *unitContext) iGetSnapshotGenerationsWithLatestAndOlderThanHoursLinkedToIfNoError(count int, latest, older string, hours int, target string) error {
+	if c.err != nil {
+		return nil
+	}
+	if len(c.snapshotGenerations) != count {
+		return fmt.Errorf("Expected %d snapshot generations but got %d", count, len(c.snapshotGenerations))
+	}
+	if c.snapshotGenerations.Latest() == nil || c.snapshotGenerations.Latest().SnapshotName != latest {
+		return fmt.Errorf("Expected the latest generation to be %s but got %v", latest, c.snapshotGenerations.Latest())
+	}
+	olderGenerations := c.snapshotGenerations.OlderThan(time.Now().Add(-time.Duration(hours) * time.Hour))
+	if len(olderGenerations) != 1 || olderGenerations[0].SnapshotName != older {
+		return fmt.Errorf("Expected %s to be the only generation older than %d hours but got %v", older, hours, olderGenerations)
+	}
+	linked := c.snapshotGenerations.Named(older)
+	if len(linked) != 1 || !linked[0].IsLinked() || linked[0].Targets[0].VolumeID != target || linked[0].Targets[0].Timestamp.IsZero() {
+		return fmt.Errorf("Expected %s to be linked to %s but got %v", older, target, linked)
+	}
+	c.snapshotGenerations.SortNewestFirst()
+	if c.snapshotGenerations[0].SnapshotName != latest {
+		return fmt.Errorf("Expected %s to be sorted first but got %v", latest, c.snapshotGenerations)
+	}
+	return nil
+}
+
+func (c *unitContext) theSnapshotGenerationsOfMatchTheirPrivateVolumeHeadersIfNoError(volID string) error {
+	if c.err != nil {
+		return nil
+	}
+	privVolume, err := c.client.GetPrivVolumeByID(symID, volID)
+	if err != nil {
+		return err
+	}
+	for _, session := range privVolume.TimeFinderInfo.SnapVXSession {
+		for i := range session.SourceSnapshotGenInfo {
+			fromHeader := types.NewSnapshotGenerationFromHeader(&session.SourceSnapshotGenInfo[i])
+			generations := c.snapshotGenerations.Named(fromHeader.SnapshotName)
+			if len(generations) != 1 {
+				return fmt.Errorf("Expected one generation of %s but got %v", fromHeader.SnapshotName, generations)
+			}
+			generation := generations[0]
+			if !generation.Timestamp.Equal(fromHeader.Timestamp) || generation.TimeToLive != fromHeader.TimeToLive ||
+				!generation.ExpiryTime().Equal(fromHeader.ExpiryTime()) || len(generation.Targets) != len(fromHeader.Targets) {
+				return fmt.Errorf("Expected the generation %v to match its private volume header %v", generation, fromHeader)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) iCallLinkSnapshotToWithCopyAndRelink(snapID, targetVolIDs, copy, relink string) error {
+	targetVolumeList := c.createVolumeList(targetVolIDs)
+	sourceVolumeList := c.createVolumeList(strings.Join(c.volIDList[:len(targetVolumeList)], ","))
+	options := &types.LinkSnapshotOptions{
+		Copy:   copy == "true",
+		Relink: relink == "true",
+	}
+	c.err = c.client.LinkSnapshot(symID, snapID, sourceVolumeList, targetVolumeList, 0, options)
+	return nil
+}
+
+func (c *unitContext) volumesAreLinkedToSnapshot(targetVolIDs, snapID string) error {
+	if snapID == "" {
+		return nil
+	}
+	if err := c.iCallLinkSnapshotToWithCopyAndRelink(snapID, targetVolIDs, "false", "false"); err != nil {
+		return err
+	}
+	return c.err
+}
+
+func (c *unitContext) iCallCreateConsistentSnapshotOf(snapID, volIDs string) error {
+	sourceVolumeList := make([]types.VolumeList, 0)
+	for _, volID := range convertStringToSlice(volIDs) {
+		sourceVolumeList = append(sourceVolumeList, types.VolumeList{Name: volID})
+	}
+	c.consistentSnapshot, c.err = c.client.CreateConsistentSnapshot(symID, snapID, sourceVolumeList, nil)
+	return nil
+}
+
+func (c *unitContext) iCallLinkConsistentSnapshotTo(targetVolIDs string) error {
+	if c.err != nil {
+		return nil
+	}
+	c.err = c.client.LinkConsistentSnapshot(symID, c.consistentSnapshot, c.createVolumeList(targetVolIDs), nil)
+	return nil
+}
+
+func (c *unitContext) theConsistentSnapshotOfIsLinkedToIfNoError(volIDs, targetVolIDs string) error {
+	if c.err != nil {
+		return nil
+	}
+	sourceVolIDs := convertStringToSlice(volIDs)
+	if len(c.consistentSnapshot.Volumes) != len(sourceVolIDs) {
+		return fmt.Errorf("Expected a consistent snapshot of volumes %v but got %v", sourceVolIDs, c.consistentSnapshot.Volumes)
+	}
+	for i, targetVolID := range convertStringToSlice(targetVolIDs) {
+		volume := c.consistentSnapshot.Volumes[i]
+		if volume.VolumeID != sourceVolIDs[i] || volume.Generation != 0 || !volume.Timestamp.Equal(c.consistentSnapshot.Timestamp) {
+			return fmt.Errorf("Expected generation 0 of volume %s taken at %v but got %v", sourceVolIDs[i], c.consistentSnapshot.Timestamp, volume)
+		}
+		generations, err := c.client.GetVolumeSnapshotGenerations(symID, volume.VolumeID)
+		if err != nil {
+			return err
+		}
+		latest := generations.Named(c.consistentSnapshot.SnapshotName).Latest()
+		if latest == nil || !latest.Timestamp.Equal(c.consistentSnapshot.Timestamp) {
+			return fmt.Errorf("Expected the snapshot of volume %s to be taken at %v but got %v", volume.VolumeID, c.consistentSnapshot.Timestamp, latest)
+		}
+		if linked := latest.Targets; len(linked) != 1 || linked[0].VolumeID != targetVolID {
+			return fmt.Errorf("Expected generation %d of the snapshot of volume %s to be linked to %s but got %v", latest.Generation, volume.VolumeID, targetVolID, linked)
+		}
+		if _, err := c.client.WaitForLinkState(symID, c.consistentSnapshot.SnapshotName, volume.VolumeID, targetVolID, types.SnapshotLinkStateLinked, linkStateTimeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) volumeHasASnapshotLinkedTo(volID, snapID, targetVolID string) error {
+	sourceVolumeList := []types.VolumeList{{Name: volID}}
+	if err := c.client.CreateSnapshot(symID, snapID, sourceVolumeList, 0); err != nil {
+		return err
+	}
+	return c.client.LinkSnapshot(symID, snapID, sourceVolumeList, []types.VolumeList{{Name: targetVolID}}, 0, nil)
+}
+
+func (c *unitContext) volumeIsClonedTo(volID, targetVolID string) error {
+	mock.AddCloneSession(volID, targetVolID)
+	return nil
+}
+
+func (c *unitContext) volumeIsMirroredTo(volID, targetVolID string) error {
+	mock.AddMirrorSession(volID, targetVolID)
+	return nil
+}
+
+func (c *unitContext) iCallGetReplicationGraphOf(volIDs string) error {
+	c.replicationGraph, c.err = c.client.GetReplicationGraph(symID, convertStringToSlice(volIDs)...)
+	return nil
+}
+
+func (c *unitContext) theReplicationGraphHasVolumesIfNoError(volIDs string) error {
+	if c.err != nil {
+		return nil
+	}
+	expected := convertStringToSlice(volIDs)
+	if len(c.replicationGraph.Volumes) != len(expected) {
+		return fmt.Errorf("Expected volumes %v in the replication graph but got %d volumes", expected, len(c.replicationGraph.Volumes))
+	}
+	for _, volID := range expected {
+		if _, ok := c.replicationGraph.Volumes[volID]; !ok {
+			return fmt.Errorf("Expected volume %s in the replication graph", volID)
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) volumeCanBeDeletedIfNoError(volID, deletable string) error {
+	if c.err != nil {
+		return nil
+	}
+	canDelete, reasons := c.replicationGraph.CanDeleteVolume(volID)
+	if strconv.FormatBool(canDelete) != deletable {
+		return fmt.Errorf("Expected volume %s can be deleted %s but got %t: %v", volID, deletable, canDelete, reasons)
+	}
+	return nil
+}
+
+func (c *unitContext) theSnapshotOfHasDependentsIfNoError(snapID, volID, dependents string) error {
+	if c.err != nil {
+		return nil
+	}
+	expected := convertStringToSlice(dependents)
+	actual := c.replicationGraph.SnapshotDependents(volID, snapID)
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		return fmt.Errorf("Expected snapshot %s of volume %s to have dependents %v but got %v", snapID, volID, expected, actual)
+	}
+	return nil
+}
+
+func (c *unitContext) iCallWaitForLinkStateForSnapshotFromTo(state, snapID, sourceVolID, targetVolID string) error {
+	if c.err != nil {
+		return nil
+	}
+	c.snapshotLink, c.err = c.client.WaitForLinkState(symID, snapID, sourceVolID, targetVolID, state, linkStateTimeout)
+	return nil
+}
+
+func (c *unitContext) theCopyOfTheLinksCompletesAfterPolls(polls int) error {
+	mock.Data.LinkCopyPolls = polls
+	return nil
+}
+
+func (c *unitContext) theLinkToIsInStateAndNoLongerLinkedToIfNoError(targetVolID, state, previousSnapID string) error {
+	if c.err != nil {
+		return nil
+	}
+	if c.snapshotLink == nil || c.snapshotLink.TargetDevice != targetVolID || c.snapshotLink.State != state || !c.snapshotLink.Defined {
+		return fmt.Errorf("Expected a defined link to %s in state %s but got %v", targetVolID, state, c.snapshotLink)
+	}
+	if previousSnapID == "" {
+		return nil
+	}
+	for key, linkedVolumes := range mock.Data.SnapIDToLinkedVol {
+		if strings.HasPrefix(key, previousSnapID+":") && linkedVolumes[targetVolID] != nil {
+			return fmt.Errorf("Expected %s to be relinked from snapshot %s", targetVolID, previousSnapID)
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) iHaveALinkStorageGroupWithVolumes(number int) error {
+	if _, err := mock.AddStorageGroup(linkStorageGroup, "SRP_1", "Diamond"); err != nil {
+		return err
+	}
+	for i := 1; i <= number; i++ {
+		id := fmt.Sprintf("1%04d", i)
+		mock.AddNewVolume(id, "LinkVol"+id, 7, linkStorageGroup)
+	}
+	return nil
+}
+
+func (c *unitContext) iHaveAStorageGroupSnapshotTakenTimes(snapID string, count int) error {
+	for i := 0; i < count; i++ {
+		if _, err := mock.AddStorageGroupSnapshot(mock.DefaultStorageGroup, snapID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) iCallCreateStorageGroupSnapshotWithTTLInHoursAndSecure(snapID string, ttl int64, timeInHours, secure string) error {
+	options := &types.SnapshotOptions{
+		TimeToLive:  ttl,
+		TimeInHours: timeInHours == "true",
+		Secure:      secure == "true",
+	}
+	c.sgSnapshot, c.err = c.client.CreateStorageGroupSnapshot(symID, mock.DefaultStorageGroup, snapID, options)
+	return nil
+}
+
+func (c *unitContext) theStorageGroupSnapshotHasATimeToLiveOfHoursAndIsSecuredIfNoError(ttlHours int64, secured string) error {
+	if c.err != nil {
+		return nil
+	}
+	if c.sgSnapshot.TimeToLive != ttlHours || c.sgSnapshot.Secured != (secured == "true") {
+		return fmt.Errorf("Expected a time to live of %d hours and secured %s but got %d hours and secured %t",
+			ttlHours, secured, c.sgSnapshot.TimeToLive, c.sgSnapshot.Secured)
+	}
+	return nil
+}
+
+func (c *unitContext) iGetAValidStorageGroupSnapshotWithSourceVolumesIfNoError(snapID string, count int) error {
+	if c.err != nil {
+		return nil
+	}
+	if c.sgSnapshot.Name != snapID || c.sgSnapshot.Generation != 0 {
+		return fmt.Errorf("Expected generation 0 of snapshot %s but got %s generation %d", snapID, c.sgSnapshot.Name, c.sgSnapshot.Generation)
+	}
+	if c.sgSnapshot.NumSourceVolumes != count || len(c.sgSnapshot.SourceVolumes) != count {
+		return fmt.Errorf("Expected %d source volumes but got %v", count, c.sgSnapshot.SourceVolumes)
+	}
+	return nil
+}
+
+func (c *unitContext) iCallListStorageGroupSnapshots() error {
+	c.sgSnapshotList, c.err = c.client.ListStorageGroupSnapshots(symID, mock.DefaultStorageGroup)
+	return nil
+}
+
+func (c *unitContext) iGetStorageGroupSnapshotsIfNoError(snapIDs string) error {
+	if c.err != nil {
+		return nil
+	}
+	expected := convertStringToSlice(snapIDs)
+	if len(c.sgSnapshotList.Name) != len(expected) {
+		return fmt.Errorf("Expected snapshots %v but got %v", expected, c.sgSnapshotList.Name)
+	}
+	for i, snapID := range c.sgSnapshotList.Name {
+		if snapID != expected[i] {
+			return fmt.Errorf("Expected snapshots %v but got %v", expected, c.sgSnapshotList.Name)
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) iCallGetStorageGroupSnapshotGenerations(snapID string) error {
+	c.sgSnapshotGenList, c.err = c.client.GetStorageGroupSnapshotGenerations(symID, mock.DefaultStorageGroup, snapID)
+	return nil
+}
+
+func (c *unitContext) iGetStorageGroupSnapshotGenerationsIfNoError(count int) error {
+	if c.err != nil {
+		return nil
+	}
+	if len(c.sgSnapshotGenList.Generations) != count {
+		return fmt.Errorf("Expected %d generations but got %v", count, c.sgSnapshotGenList.Generations)
+	}
+	return nil
+}
+
+func (c *unitContext) theStorageGroupSnapshotHasGenerationsIfNoError(snapID string, count int) error {
+	if c.err != nil {
+		return nil
+	}
+	generationList, err := c.client.GetStorageGroupSnapshotGenerations(symID, mock.DefaultStorageGroup, snapID)
+	if err != nil {
+		return err
+	}
+	if len(generationList.Generations) != count {
+		return fmt.Errorf("Expected %d generations of snapshot %s but got %v", count, snapID, generationList.Generations)
+	}
+	return nil
+}
+
+func (c *unitContext) iCallOnStorageGroupSnapshotGeneration(actions string, snapID string, generation int64) error {
+	for _, action := range convertStringToSlice(actions) {
+		switch action {
+		case "Link":
+			c.err = c.client.LinkStorageGroupSnapshot(symID, mock.DefaultStorageGroup, snapID, generation, linkStorageGroup)
+		case "Unlink":
+			c.err = c.client.UnlinkStorageGroupSnapshot(symID, mock.DefaultStorageGroup, snapID, generation, linkStorageGroup)
+		case "Restore":
+			c.err = c.client.RestoreStorageGroupSnapshot(symID, mock.DefaultStorageGroup, snapID, generation)
+		case "Rename":
+			c.err = c.client.RenameStorageGroupSnapshot(symID, mock.DefaultStorageGroup, snapID, generation, snapID+"-renamed")
+		case "Delete":
+			c.err = c.client.DeleteStorageGroupSnapshot(symID, mock.DefaultStorageGroup, snapID, generation)
+		default:
+			return fmt.Errorf("Unknown storage group snapshot action %s", action)
+		}
+		if c.err != nil {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) theStorageGroupSnapshotIsLinkedAndRestoredIfNoError(snapID string, linked, restored string) error {
+	if c.err != nil {
+		return nil
+	}
+	snapshot, err := c.client.GetStorageGroupSnapshot(symID, mock.DefaultStorageGroup, snapID, 0)
+	if err != nil {
+		return err
+	}
+	if strconv.FormatBool(snapshot.Linked) != linked || strconv.FormatBool(snapshot.Restored) != restored {
+		return fmt.Errorf("Expected snapshot %s linked %s and restored %s but got %v", snapID, linked, restored, snapshot)
+	}
+	if snapshot.Linked && len(snapshot.LinkedStorageGroups) != len(snapshot.SourceVolumes) {
+		return fmt.Errorf("Expected a link for each source volume but got %v", snapshot.LinkedStorageGroups)
+	}
+	return nil
+}
+
+func (c *unitContext) iHaveASnapshotPolicy(snapshotPolicyID string) error {
+	snapshotPolicy, err := mock.AddSnapshotPolicy(snapshotPolicyID, types.SnapshotPolicyInterval1Hour, 24, false)
+	if err != nil {
+		return err
+	}
+	snapshotPolicy.ComplianceCountWarning = 2
+	snapshotPolicy.ComplianceCountCritical = 1
+	return nil
+}
+
+func (c *unitContext) iCallCreateSnapshotPolicyEveryKeepingSnapshots(snapshotPolicyID, interval string, count int) error {
+	c.snapshotPolicy, c.err = c.client.CreateSnapshotPolicy(symID, &types.CreateSnapshotPolicyParam{
+		SnapshotPolicyName: snapshotPolicyID,
+		Interval:           interval,
+		SnapshotCount:      count,
+	})
+	return nil
+}
+
+func (c *unitContext) iGetAValidSnapshotPolicyTakingASnapshotEveryMinutesIfNoError(snapshotPolicyID string, minutes int) error {
+	if c.err != nil {
+		return nil
+	}
+	if c.snapshotPolicy == nil || c.snapshotPolicy.SnapshotPolicyName != snapshotPolicyID {
+		return fmt.Errorf("Expected snapshot policy %s but got %v", snapshotPolicyID, c.snapshotPolicy)
+	}
+	if c.snapshotPolicy.IntervalMinutes != minutes {
+		return fmt.Errorf("Expected an interval of %d minutes but got %d", minutes, c.snapshotPolicy.IntervalMinutes)
+	}
+	return nil
+}
+
+func (c *unitContext) iCallGetSnapshotPolicyList() error {
+	c.snapshotPolicyList, c.err = c.client.GetSnapshotPolicyList(symID)
+	return nil
+}
+
+func (c *unitContext) iCallGetSnapshotPolicy(snapshotPolicyID string) error {
+	c.snapshotPolicy, c.err = c.client.GetSnapshotPolicy(symID, snapshotPolicyID)
+	return nil
+}
+
+func (c *unitContext) iGetSnapshotPoliciesIfNoError(snapshotPolicyIDs string) error {
+	if c.err != nil {
+		return nil
+	}
+	expected := convertStringToSlice(snapshotPolicyIDs)
+	if len(c.snapshotPolicyList.SnapshotPolicyIDs) != len(expected) {
+		return fmt.Errorf("Expected snapshot policies %v but got %v", expected, c.snapshotPolicyList.SnapshotPolicyIDs)
+	}
+	for i, snapshotPolicyID := range c.snapshotPolicyList.SnapshotPolicyIDs {
+		if snapshotPolicyID != expected[i] {
+			return fmt.Errorf("Expected snapshot policies %v but got %v", expected, c.snapshotPolicyList.SnapshotPolicyIDs)
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) iCallOnSnapshotPolicy(actions, snapshotPolicyID string) error {
+	for _, action := range convertStringToSlice(actions) {
+		switch action {
+		case "Modify":
+			c.snapshotPolicy, c.err = c.client.ModifySnapshotPolicy(symID, snapshotPolicyID, &types.ModifySnapshotPolicyParam{
+				Interval:      types.SnapshotPolicyInterval1Day,
+				SnapshotCount: 7,
+			})
+		case "Rename":
+			c.snapshotPolicy, c.err = c.client.ModifySnapshotPolicy(symID, snapshotPolicyID, &types.ModifySnapshotPolicyParam{
+				SnapshotPolicyName: snapshotPolicyID + "-renamed",
+			})
+		case "Suspend":
+			c.snapshotPolicy, c.err = c.client.SuspendSnapshotPolicy(symID, snapshotPolicyID)
+		case "Resume":
+			c.snapshotPolicy, c.err = c.client.ResumeSnapshotPolicy(symID, snapshotPolicyID)
+		case "Associate":
+			c.snapshotPolicy, c.err = c.client.AssociateSnapshotPolicy(symID, snapshotPolicyID, mock.DefaultStorageGroup)
+		case "AssociateUnknown":
+			c.snapshotPolicy, c.err = c.client.AssociateSnapshotPolicy(symID, snapshotPolicyID, "unknown-sg")
+		case "Disassociate":
+			c.snapshotPolicy, c.err = c.client.DisassociateSnapshotPolicy(symID, snapshotPolicyID, mock.DefaultStorageGroup)
+		default:
+			return fmt.Errorf("Unknown snapshot policy action %s", action)
+		}
+		if c.err != nil {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) theSnapshotPolicyKeepsSnapshotsAndIsSuspendedIfNoError(snapshotPolicyID string, count int, suspended string) error {
+	if c.err != nil {
+		return nil
+	}
+	snapshotPolicy, err := c.client.GetSnapshotPolicy(symID, snapshotPolicyID)
+	if err != nil {
+		return err
+	}
+	if snapshotPolicy.SnapshotCount != count {
+		return fmt.Errorf("Expected snapshot policy %s to keep %d snapshots but it keeps %d", snapshotPolicyID, count, snapshotPolicy.SnapshotCount)
+	}
+	if strconv.FormatBool(snapshotPolicy.Suspended) != suspended {
+		return fmt.Errorf("Expected snapshot policy %s suspended %s but got %t", snapshotPolicyID, suspended, snapshotPolicy.Suspended)
+	}
+	return nil
+}
+
+func (c *unitContext) theSnapshotPolicyHasStorageGroupsIfNoError(snapshotPolicyID string, count int) error {
+	if c.err != nil {
+		return nil
+	}
+	sgList, err := c.client.GetSnapshotPolicyStorageGroups(symID, snapshotPolicyID)
+	if err != nil {
+		return err
+	}
+	if len(sgList.StorageGroupIDs) != count || c.snapshotPolicy.StorageGroupCount != count {
+		return fmt.Errorf("Expected %d storage groups associated to snapshot policy %s but got %v", count, snapshotPolicyID, sgList.StorageGroupIDs)
+	}
+	return nil
+}
+
+func (c *unitContext) iCallDeleteSnapshotPolicy(snapshotPolicyID string) error {
+	c.err = c.client.DeleteSnapshotPolicy(symID, snapshotPolicyID)
+	return nil
+}
+
+func (c *unitContext) theSnapshotPolicyIsDeletedIfNoError(snapshotPolicyID string) error {
+	if c.err != nil {
+		return nil
+	}
+	if _, ok := mock.Data.SnapshotPolicyIDToSnapshotPolicy[snapshotPolicyID]; ok {
+		return fmt.Errorf("Expected snapshot policy %s to be deleted", snapshotPolicyID)
+	}
+	return nil
+}
+
+func (c *unitContext) iCallGetStorageGroupSnapshotCompliance() error {
+	c.sgSnapshotCompliance, c.err = c.client.GetStorageGroupSnapshotCompliance(symID, mock.DefaultStorageGroup)
+	return nil
+}
+
+func (c *unitContext) theSnapshotComplianceIsIfNoError(compliance string) error {
+	if c.err != nil {
+		return nil
+	}
+	if c.sgSnapshotCompliance.Compliance != compliance {
+		return fmt.Errorf("Expected snapshot compliance %s but got %s", compliance, c.sgSnapshotCompliance.Compliance)
+	}
+	return nil
+}
+
+func (c *unitContext) rdfGroupHasOnlineLocalPorts(rdfGroupNumber, onlinePorts int) error {
+	rdfGroup, ok := mock.Data.RDFGroupIDToRDFGroup[strconv.Itoa(rdfGroupNumber)]
+	if !ok {
+		return nil
+	}
+	if onlinePorts < len(rdfGroup.LocalPorts) {
+		rdfGroup.LocalOnlinePorts = rdfGroup.LocalPorts[:onlinePorts]
+	}
+	return nil
+}
+
+func (c *unitContext) iHaveRDFDevicePairsInRDFGroup(volIDs string, rdfGroupNumber int) error {
+	for i, volID := range convertStringToSlice(volIDs) {
+		_, err := mock.AddRDFDevicePair(rdfGroupNumber, volID, fmt.Sprintf("%05d", 101+i), "Synchronized")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) iCallGetRDFGroupList() error {
+	c.rdfGroupList, c.err = c.client.GetRDFGroupList(symID)
+	return nil
+}
+
+func (c *unitContext) iGetRDFGroupsIfNoError(count int) error {
+	if c.err != nil {
+		return nil
+	}
+	if c.rdfGroupList.RDFGroupCount != count || len(c.rdfGroupList.RDFGroupIDs) != count {
+		return fmt.Errorf("Expected %d RDF groups but got %v", count, c.rdfGroupList)
+	}
+	return nil
+}
+
+func (c *unitContext) iCallGetRDFGroup(rdfGroupNumber int) error {
+	c.rdfGroup, c.err = c.client.GetRDFGroup(symID, rdfGroupNumber)
+	return nil
+}
+
+func (c *unitContext) iGetAValidRDFGroupWithModeAndLinkStateIfNoError(mode, linkState string) error {
+	if c.err != nil {
+		return nil
+	}
+	if c.rdfGroup.SymmetrixID != symID || c.rdfGroup.RemoteSymmetrix != mock.DefaultRemoteSymID {
+		return fmt.Errorf("Expected RDF group between %s and %s but got %s and %s",
+			symID, mock.DefaultRemoteSymID, c.rdfGroup.SymmetrixID, c.rdfGroup.RemoteSymmetrix)
+	}
+	if len(c.rdfGroup.Modes) != 1 || c.rdfGroup.Modes[0] != mode {
+		return fmt.Errorf("Expected RDF group mode %s but got %v", mode, c.rdfGroup.Modes)
+	}
+	if c.rdfGroup.LinkState() != linkState {
+		return fmt.Errorf("Expected link state %s but got %s", linkState, c.rdfGroup.LinkState())
+	}
+	return nil
+}
+
+func (c *unitContext) iCallGetRDFGroupVolumes(rdfGroupNumber int) error {
+	c.rdfGroupVolumeList, c.err = c.client.GetRDFGroupVolumes(symID, rdfGroupNumber)
+	return nil
+}
+
+func (c *unitContext) iGetRDFGroupVolumesIfNoError(count int) error {
+	if c.err != nil {
+		return nil
+	}
+	if len(c.rdfGroupVolumeList.VolumeIDs) != count {
+		return fmt.Errorf("Expected %d volumes in the RDF group but got %v", count, c.rdfGroupVolumeList.VolumeIDs)
+	}
+	return nil
+}
+
+func (c *unitContext) iCallGetRDFDevicePairWithVolume(rdfGroupNumber int, volID string) error {
+	c.rdfDevicePair, c.err = c.client.GetRDFDevicePair(symID, rdfGroupNumber, volID)
+	return nil
+}
+
+func (c *unitContext) theRemoteVolumeIsIfNoError(remoteVolID string) error {
+	if c.err != nil {
+		return nil
+	}
+	if c.rdfDevicePair.RemoteVolumeName != remoteVolID || c.rdfDevicePair.RemoteSymmetrixID != mock.DefaultRemoteSymID {
+		return fmt.Errorf("Expected remote volume %s but got %v", remoteVolID, c.rdfDevicePair)
+	}
+	return nil
+}
+
+func (c *unitContext) iCallCreateSGReplicaInRDFGroupWithModeAndEstablish(rdfGroupNumber int, mode, establish string) error {
+	c.sgRDFInfo, c.err = c.client.CreateSGReplica(symID, mock.DefaultRemoteSymID, mode, rdfGroupNumber,
+		mock.DefaultStorageGroup, mock.DefaultStorageGroup+"-R", mock.DefaultServiceLevel, establish == "true")
+	return nil
+}
+
+func (c *unitContext) iHaveAnSRDFProtectedStorageGroupInRDFGroup(rdfGroupNumber int) error {
+	return mock.AddSGReplica(mock.DefaultStorageGroup, rdfGroupNumber, mock.DefaultStorageGroup+"-R", true)
+}
+
+func (c *unitContext) iCallExecuteSRDFActionOnRDFGroup(actions string, rdfGroupNumber int) error {
+	for _, action := range convertStringToSlice(actions) {
+		c.err = c.client.ExecuteSRDFAction(symID, mock.DefaultStorageGroup, rdfGroupNumber, action, false)
+		if c.err != nil {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) theSRDFStateOfTheStorageGroupInRDFGroupIsIfNoError(rdfGroupNumber int, state string) error {
+	if c.err != nil {
+		return nil
+	}
+	sgRDFInfo, err := c.client.GetStorageGroupRDFInfo(symID, mock.DefaultStorageGroup, rdfGroupNumber)
+	if err != nil {
+		return err
+	}
+	if !sgRDFInfo.InState(state) {
+		return fmt.Errorf("Expected SRDF state %s but got %v", state, sgRDFInfo.States)
+	}
+	if c.sgRDFInfo != nil && !c.sgRDFInfo.InState(state) {
+		return fmt.Errorf("Expected returned SRDF state %s but got %v", state, c.sgRDFInfo.States)
+	}
+	return nil
+}
+
+func (c *unitContext) theVolumesOfTheStorageGroupInRDFGroupAreIfNoError(rdfGroupNumber int, rdfType string) error {
+	if c.err != nil {
+		return nil
+	}
+	sgRDFInfo, err := c.client.GetStorageGroupRDFInfo(symID, mock.DefaultStorageGroup, rdfGroupNumber)
+	if err != nil {
+		return err
+	}
+	if len(sgRDFInfo.VolumeRDFTypes) != 1 || sgRDFInfo.VolumeRDFTypes[0] != rdfType {
+		return fmt.Errorf("Expected volumes of type %s but got %v", rdfType, sgRDFInfo.VolumeRDFTypes)
+	}
+	return nil
+}
+
+func (c *unitContext) iCallDeleteSGReplicaOnRDFGroupWithForce(rdfGroupNumber int, force string) error {
+	c.err = c.client.DeleteSGReplica(symID, mock.DefaultStorageGroup, rdfGroupNumber, force == "true")
+	return nil
+}
+
+func (c *unitContext) theStorageGroupIsProtectedInRDFGroupsIfNoError(rdfGroupNumbers string) error {
+	if c.err != nil {
+		return nil
+	}
+	sgRDFGroupList, err := c.client.GetStorageGroupRDFGroupList(symID, mock.DefaultStorageGroup)
+	if err != nil {
+		return err
+	}
+	expected := convertStringToSlice(rdfGroupNumbers)
+	if len(sgRDFGroupList.RDFGroupNumbers) != len(expected) {
+		return fmt.Errorf("Expected RDF groups %v but got %v", expected, sgRDFGroupList.RDFGroupNumbers)
+	}
+	for i, rdfGroupNumber := range sgRDFGroupList.RDFGroupNumbers {
+		if strconv.Itoa(rdfGroupNumber) != expected[i] {
+			return fmt.Errorf("Expected RDF groups %v but got %v", expected, sgRDFGroupList.RDFGroupNumbers)
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) iCallExpandRDFVolumeInRDFGroupToInGB(volumeID string, rdfGroupNumber int, size int) error {
+	c.rdfDevicePair, c.err = c.client.ExpandRDFVolume(symID, volumeID, rdfGroupNumber, size)
+	return nil
+}
+
+func (c *unitContext) iCallGetVolumeRDFPair(volumeID string) error {
+	c.rdfDevicePair, c.err = c.client.GetVolumeRDFPair(symID, volumeID)
+	return nil
+}
+
+func (c *unitContext) theRemoteVolumeOfHasSizeInGBIfNoError(volumeID string, size int) error {
+	if c.err != nil {
+		return nil
+	}
+	devicePair, err := c.client.GetVolumeRDFPair(symID, volumeID)
+	if err != nil {
+		return err
+	}
+	if c.rdfDevicePair != nil && c.rdfDevicePair.RemoteVolumeName != devicePair.RemoteVolumeName {
+		return fmt.Errorf("Expected remote volume %s but got %s", devicePair.RemoteVolumeName, c.rdfDevicePair.RemoteVolumeName)
+	}
+	if remoteSize := mock.Data.RemoteVolumeIDToSize[devicePair.RemoteVolumeName]; remoteSize != float64(size) {
+		return fmt.Errorf("Expected remote volume %s to be size %d, but was %f", devicePair.RemoteVolumeName, size, remoteSize)
+	}
+	return nil
+}
+
+func (c *unitContext) iCallCreateRDFVolumeInStorageGroupWithNameAndSize(volumeName string, sizeInCylinders int) error {
+	c.rdfDevicePair, c.err = c.client.CreateRDFVolumeInStorageGroup(symID, mock.DefaultStorageGroup, mock.DefaultStorageGroup+"-R", volumeName, sizeInCylinders)
+	return nil
+}
+
+func (c *unitContext) theCreatedVolumeIsPairedInStateIfNoError(state string) error {
+	if c.err != nil {
+		return nil
+	}
+	devicePair, err := c.client.GetVolumeRDFPair(symID, c.vol.VolumeID)
+	if err != nil {
+		return err
+	}
+	c.rdfDevicePair = devicePair
+	return c.iGetAValidRDFDevicePairInStateIfNoError(state)
+}
+
+func (c *unitContext) rdfGroupHasWitness(rdfGroupNumber int, witness string) error {
+	switch witness {
+	case "none":
+		return nil
+	case "degraded":
+		return mock.SetRDFGroupWitness(rdfGroupNumber, true, false, "")
+	}
+	return mock.SetRDFGroupWitness(rdfGroupNumber, true, true, witness)
+}
+
+func (c *unitContext) iCallGetMetroStatusOnRDFGroup(rdfGroupNumber int) error {
+	c.metroStatus, c.err = c.client.GetMetroStatus(symID, mock.DefaultStorageGroup, rdfGroupNumber)
+	return nil
+}
+
+func (c *unitContext) iGetAMetroStatusInStateWithBiasOnAndWitnessIfNoError(state, biasArray, witness string) error {
+	if c.err != nil {
+		return nil
+	}
+	if c.metroStatus.RemoteSymmetrixID != mock.DefaultRemoteSymID || c.metroStatus.StorageGroupName != mock.DefaultStorageGroup {
+		return fmt.Errorf("Expected Metro status of %s with remote Symmetrix %s but got %v", mock.DefaultStorageGroup, mock.DefaultRemoteSymID, c.metroStatus)
+	}
+	switch state {
+	case types.RDFPairStateActiveActive:
+		if !c.metroStatus.IsActiveActive() {
+			return fmt.Errorf("Expected ActiveActive pairs but got %v", c.metroStatus.States)
+		}
+	case types.RDFPairStateActiveBias:
+		if !c.metroStatus.IsActiveBias() {
+			return fmt.Errorf("Expected ActiveBias pairs but got %v", c.metroStatus.States)
+		}
+	}
+	if err := checkBiasArray(c.metroStatus, biasArray); err != nil {
+		return err
+	}
+	switch witness {
+	case "none":
+		if c.metroStatus.WitnessConfigured {
+			return fmt.Errorf("Expected no witness but got %v", c.metroStatus)
+		}
+	case "degraded":
+		if !c.metroStatus.IsWitnessDegraded() || c.metroStatus.WitnessType != "" {
+			return fmt.Errorf("Expected a degraded witness but got %v", c.metroStatus)
+		}
+	default:
+		if !c.metroStatus.WitnessEffective || c.metroStatus.WitnessType != witness {
+			return fmt.Errorf("Expected a %s witness in effect but got %v", witness, c.metroStatus)
+		}
+	}
+	return nil
+}
+
+func (c *unitContext) theSRDFPairOfVolumeInRDFGroupIsAnR2(volID string, rdfGroupNumber int) error {
+	if volID == "" {
+		return nil
+	}
+	devicePair, ok := mock.Data.RDFGroupIDToDevicePairs[strconv.Itoa(rdfGroupNumber)][volID]
+	if !ok {
+		return fmt.Errorf("Volume %s is not paired in RDF group %d", volID, rdfGroupNumber)
+	}
+	devicePair.VolumeConfig = "RDF2+TDEV"
+	return nil
+}
+
+func (c *unitContext) iCallSetMetroBiasOnRDFGroup(biasSide string, rdfGroupNumber int) error {
+	c.err = c.client.SetMetroBias(symID, mock.DefaultStorageGroup, rdfGroupNumber, biasSide)
+	return nil
+}
+
+func (c *unitContext) theMetroBiasOfTheStorageGroupInRDFGroupIsOnIfNoError(rdfGroupNumber int, biasArray string) error {
+	if c.err != nil {
+		return nil
+	}
+	metroStatus, err := c.client.GetMetroStatus(symID, mock.DefaultStorageGroup, rdfGroupNumber)
+	if err != nil {
+		return err
+	}
+	return checkBiasArray(metroStatus, biasArray)
+}
+
+// checkBiasArray checks that the bias of the Metro status is held by the "local" or the "remote" array, or is "unknown"
+func checkBiasArray(metroStatus *types.MetroStatus, biasArray string) error {
+	expected := symID
+	switch biasArray {
+	case "remote":
+		expected = mock.DefaultRemoteSymID
+	case "unknown":
+		if metroStatus.LocalRDFType != types.MetroBiasUnknown {
+			return fmt.Errorf("Expected an unknown local RDF type but got %s", metroStatus.LocalRDFType)
+		}
+		expected = ""
+	}
+	if metroStatus.BiasSymmetrixID != expected {
+		return fmt.Errorf("Expected bias on the %s array %s but got %s", biasArray, expected, metroStatus.BiasSymmetrixID)
+	}
+	return nil
+}
+
+func (c *unitContext) iGetAValidRDFDevicePairInStateIfNoError(state string) error {
+	if c.err != nil {
+		return nil
+	}
+	if c.rdfDevicePair == nil || c.rdfDevicePair.LocalVolumeName == "" || c.rdfDevicePair.RemoteVolumeName == "" {
+		return fmt.Errorf("Expected an RDF device pair with local and remote volumes but got %v", c.rdfDevicePair)
+	}
+	if c.rdfDevicePair.RemoteSymmetrixID != mock.DefaultRemoteSymID || c.rdfDevicePair.RDFPairState != state {
+		return fmt.Errorf("Expected a %s pair on remote Symmetrix %s but got %v", state, mock.DefaultRemoteSymID, c.rdfDevicePair)
+	}
+	vol, err := c.client.GetVolumeByID(symID, c.rdfDevicePair.LocalVolumeName)
+	if err != nil {
+		return err
+	}
+	if len(vol.StorageGroupIDList) != 1 || vol.StorageGroupIDList[0] != mock.DefaultStorageGroup {
+		return fmt.Errorf("Expected volume %s in StorageGroup %s but got %v", vol.VolumeID, mock.DefaultStorageGroup, vol.StorageGroupIDList)
+	}
+	return nil
+}
+
+func (c *unitContext) iCallGetPerformanceKeysOfCategoryWithDirector(category, directorID string) error {
+	switch category {
+	case types.PerformanceCategoryArray:
+		c.performanceKeys, c.err = c.client.GetArrayPerformanceKeys(symID)
+	case types.PerformanceCategoryStorageGroup:
+		c.performanceKeys, c.err = c.client.GetStorageGroupPerformanceKeys(symID)
+	case types.PerformanceCategoryFEDirector:
+		c.performanceKeys, c.err = c.client.GetFEDirectorPerformanceKeys(symID)
+	case types.PerformanceCategoryFEPort:
+		c.performanceKeys, c.err = c.client.GetFEPortPerformanceKeys(symID, directorID)
+	default:
+		return fmt.Errorf("Unknown performance category %s", category)
+	}
+	return nil
+}
+
+func (c *unitContext) iGetPerformanceKeysIncludingIfNoError(count int, key string) error {
+	if c.err != nil {
+		return nil
+	}
+	if len(c.performanceKeys) != count {
+		return fmt.Errorf("Expected %d performance keys but got %v", count, c.performanceKeys)
+	}
+	for _, performanceKey := range c.performanceKeys {
+		if performanceKey.ID == key {
+			if !performanceKey.FirstAvailable.Before(performanceKey.LastAvailable) {
+				return fmt.Errorf("Expected performance key %s to be available over a time range but got %v", key, performanceKey)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("Expected performance key %s in %v", key, c.performanceKeys)
+}
+
+func (c *unitContext) iCallGetPerformanceOfForMinutesStartingHoursAgo(category, id string, minutes, hours int, interval, dataFormat string) error {
+	duration, err := time.ParseDuration(interval)
+	if err != nil {
+		return err
+	}
+	query := &types.PerformanceQuery{
+		StartTime:  time.Now().Truncate(time.Hour).Add(-time.Duration(hours) * time.Hour),
+		Interval:   duration,
+		DataFormat: dataFormat,
+	}
+	query.EndTime = query.StartTime.Add(time.Duration(minutes) * time.Minute)
+	switch category {
+	case types.PerformanceCategoryArray:
+		c.performanceResult, c.err = c.client.GetArrayPerformance(symID, query)
+	case types.PerformanceCategoryStorageGroup:
+		c.performanceResult, c.err = c.client.GetStorageGroupPerformance(symID, id, query)
+	case types.PerformanceCategoryVolume:
+		c.performanceResult, c.err = c.client.GetVolumePerformance(symID, id, query)
+	case types.PerformanceCategoryFEDirector:
+		c.performanceResult, c.err = c.client.GetFEDirectorPerformance(symID, id, query)
+	case types.PerformanceCategoryFEPort:
+		port := strings.Split(id, ":")
+		c.performanceResult, c.err = c.client.GetFEPortPerformance(symID, port[0], port[len(port)-1], query)
+	default:
+		return fmt.Errorf("Unknown performance category %s", category)
+	}
+	return nil
+}
+
+func (c *unitContext) iGetPerformanceSamplesStartingWithIOPSIfNoError(count int, iops float64) error {
+	if c.err != nil {
+		return nil
+	}
+	if len(c.performanceResult.Samples) != count {
+		return fmt.Errorf("Expected %d performance samples but got %d", count, len(c.performanceResult.Samples))
+	}
+	if count > 0 && c.performanceResult.Samples[0].IOPS != iops {
+		return fmt.Errorf("Expected the first performance sample to have %f IOPS but got %v", iops, c.performanceResult.Samples[0])
+	}
+	if count > 0 && c.performanceResult.Latest() != &c.performanceResult.Samples[count-1] {
+		return fmt.Errorf("Expected the latest performance sample to be the last sample")
+	}
+	return nil
+}
+
 func (c *unitContext) thereShouldBeNoErrors() error {
 	return c.err
 }
@@ -1302,6 +3333,7 @@ func UnitTestContext(s *godog.Suite) {
 	s.Step(`^I call authenticate with endpoint "([^"]*)" credentials "([^"]*)"$`, c.iCallAuthenticateWithEndpointCredentials)
 	s.Step(`^the error message contains "([^"]*)"$`, c.theErrorMessageContains)
 	s.Step(`^a valid connection$`, c.aValidConnection)
+	s.Step(`^a valid connection with API version "([^"]*)"$`, c.aValidConnectionWithAPIVersion)
 	s.Step(`^I call GetSymmetrixIDList$`, c.iCallGetSymmetrixIDList)
 	s.Step(`^I get a valid Symmetrix ID List if no error$`, c.iGetAValidSymmetrixIDListIfNoError)
 	s.Step(`^I call GetSymmetrixByID "([^"]*)"$`, c.iCallGetSymmetrixByID)
@@ -1323,6 +3355,24 @@ func UnitTestContext(s *godog.Suite) {
 	s.Step(`^I call GetJobByID$`, c.iCallGetJobByID)
 	s.Step(`^I get a valid Job with state "([^"]*)" if no error$`, c.iGetAValidJobWithStateIfNoError)
 	s.Step(`^I call WaitOnJobCompletion$`, c.iCallWaitOnJobCompletion)
+	s.Step(`^a valid connection with an observer$`, c.aValidConnectionWithAnObserver)
+	s.Step(`^the observer saw (\d+) "([^"]*)" requests ending with "([^"]*)" with status (\d+)$`, c.theObserverSawRequestsEndingWithWithStatus)
+	s.Step(`^the observer saw (\d+) job waits with status "([^"]*)" after (\d+) polls$`, c.theObserverSawJobWaitsWithStatusAfterPolls)
+	s.Step(`^the observer saw (\d+) retries of "([^"]*)"$`, c.theObserverSawRetriesOf)
+	s.Step(`^the observer saw a call to "([^"]*)"$`, c.theObserverSawACallTo)
+	s.Step(`^a valid connection with a tracer and caller span "([^"]*)"$`, c.aValidConnectionWithATracerAndCallerSpan)
+	s.Step(`^the tracer recorded (\d+) "([^"]*)" spans children of "([^"]*)"$`, c.theTracerRecordedSpansChildrenOf)
+	s.Step(`^the span "([^"]*)" has attribute "([^"]*)" with value "([^"]*)"$`, c.theSpanHasAttributeWithValue)
+	s.Step(`^the requests carried the trace context of their span$`, c.theRequestsCarriedTheTraceContextOfTheirSpan)
+	s.Step(`^the span "([^"]*)" ended with error "([^"]*)"$`, c.theSpanEndedWithError)
+	s.Step(`^I call GetVolumeByID "([^"]*)" concurrently (\d+) times$`, c.iCallGetVolumeByIDConcurrentlyTimes)
+	s.Step(`^each "([^"]*)" span has (\d+) "([^"]*)" child$`, c.eachSpanHasChild)
+	s.Step(`^a valid connection with wire logging of at most (\d+) body bytes$`, c.aValidConnectionWithWireLoggingOfAtMostBodyBytes)
+	s.Step(`^the wire log has (\d+) requests and responses sharing their correlation ID$`, c.theWireLogHasRequestsAndResponsesSharingTheirCorrelationID)
+	s.Step(`^the wire log has no credentials$`, c.theWireLogHasNoCredentials)
+	s.Step(`^the wire log bodies are truncated to (\d+) bytes$`, c.theWireLogBodiesAreTruncatedToBytes)
+	s.Step(`^I redact the JSON '([^']*)'$`, c.iRedactTheJSON)
+	s.Step(`^the redacted JSON is '([^']*)'$`, c.theRedactedJSONIs)
 	// Volumes
 	s.Step(`^I call CreateVolumeInStorageGroup with name "([^"]*)" and size (\d+)$`, c.iCallCreateVolumeInStorageGroupWithNameAndSize)
 	s.Step(`^I get a valid Volume with name "([^"]*)" if no error$`, c.iGetAValidVolumeWithNameIfNoError)
@@ -1347,6 +3397,11 @@ func UnitTestContext(s *godog.Suite) {
 	s.Step(`^I call CreateMaskingViewWithHost "([^"]*)"$`, c.iCallCreateMaskingViewWithHost)
 	s.Step(`^I call CreateMaskingViewWithHostGroup "([^"]*)"$`, c.iCallCreateMaskingViewWithHostGroup)
 	s.Step(`^I call DeleteMaskingView$`, c.iCallDeleteMaskingView)
+	s.Step(`^I call RenameMaskingView with "([^"]*)"$`, c.iCallRenameMaskingViewWith)
+	s.Step(`^I get a valid MaskingView with name "([^"]*)" if no error$`, c.iGetAValidMaskingViewWithNameIfNoError)
+	s.Step(`^I call GetMaskingViewsFor "([^"]*)"$`, c.iCallGetMaskingViewsFor)
+	s.Step(`^the MaskingViewList does not contain "([^"]*)" if no error$`, c.theMaskingViewListDoesNotContainIfNoError)
+	s.Step(`^I call GetMaskingViewsForHost with "([^"]*)"$`, c.iCallGetMaskingViewsForHostWith)
 	// Port Group
 	s.Step(`^I have a PortGroup$`, c.iHaveAPortGroup)
 	s.Step(`^I call GetPortGroupList$`, c.iCallGetPortGroupList)
@@ -1357,6 +3412,11 @@ func UnitTestContext(s *godog.Suite) {
 	s.Step(`^I call CreatePortGroup "([^"]*)" with ports "([^"]*)"$`, c.iCallCreatePortGroup)
 	s.Step(`^I call UpdatePortGroup "([^"]*)" with ports "([^"]*)"$`, c.iCallUpdatePortGroup)
 	s.Step(`^I call DeletePortGroup "([^"]*)"$`, c.iCallDeletePortGroup)
+	s.Step(`^I call RenamePortGroup "([^"]*)" to "([^"]*)"$`, c.iCallRenamePortGroupTo)
+	s.Step(`^I call AddPortsToPortGroup "([^"]*)" with ports "([^"]*)"$`, c.iCallAddPortsToPortGroup)
+	s.Step(`^I call RemovePortsFromPortGroup "([^"]*)" with ports "([^"]*)"$`, c.iCallRemovePortsFromPortGroup)
+	s.Step(`^I call GetPortGroupDetails$`, c.iCallGetPortGroupDetails)
+	s.Step(`^I get valid PortGroupDetails if no error$`, c.iGetValidPortGroupDetailsIfNoError)
 	s.Step(`^I expect PortGroup to have these ports "([^"]*)"$`, c.iExpectedThesePortsInPortGroup)
 	s.Step(`^the PortGroup "([^"]*)" should not exist`, c.thePortGroupShouldNotExist)
 	// Host
@@ -1373,6 +3433,13 @@ func UnitTestContext(s *godog.Suite) {
 	s.Step(`^I get a valid InitiatorList if no error$`, c.iGetAValidInitiatorListIfNoError)
 	s.Step(`^I call GetInitiatorByID$`, c.iCallGetInitiatorByID)
 	s.Step(`^I get a valid Initiator if no error$`, c.iGetAValidInitiatorIfNoError)
+	s.Step(`^I have a logged out FC Initiator$`, c.iHaveALoggedOutFCInitiator)
+	s.Step(`^I call GetInitiatorListWithFilter "([^"]*)"$`, c.iCallGetInitiatorListWithFilter)
+	s.Step(`^I get (\d+) initiators in the list if no error$`, c.iGetInitiatorsInTheListIfNoError)
+	s.Step(`^I call SetInitiatorAlias "([^"]*)" "([^"]*)"$`, c.iCallSetInitiatorAlias)
+	s.Step(`^the initiator alias is "([^"]*)" if no error$`, c.theInitiatorAliasIsIfNoError)
+	s.Step(`^I call GetInitiatorsForHost "([^"]*)"$`, c.iCallGetInitiatorsForHost)
+	s.Step(`^I get (\d+) initiators for the host if no error$`, c.iGetInitiatorsForTheHostIfNoError)
 	// HostGroup
 	s.Step(`^I have a HostGroup "([^"]*)"$`, c.iHaveAHostGroup)
 	s.Step(`^I call CreateHost "([^"]*)"$`, c.iCallCreateHost)
@@ -1383,6 +3450,22 @@ func UnitTestContext(s *godog.Suite) {
 	// GetListOftargetAddresses
 	s.Step(`^I call GetListOfTargetAddresses$`, c.iCallGetListOfTargetAddresses)
 	s.Step(`^I recieve (\d+) IP addresses$`, c.iRecieveIPAddresses)
+	// Directors
+	s.Step(`^I have a director "([^"]*)" with availability "([^"]*)"$`, c.iHaveADirectorWithAvailability)
+	s.Step(`^I call GetDirector "([^"]*)"$`, c.iCallGetDirector)
+	s.Step(`^I get a valid Director "([^"]*)" of type "([^"]*)" if no error$`, c.iGetAValidDirectorOfTypeIfNoError)
+	s.Step(`^I call ListDirectors with types "([^"]*)" front end only "([^"]*)" online only "([^"]*)"$`, c.iCallListDirectorsWithTypesFrontEndOnlyOnlineOnly)
+	s.Step(`^I recieve (\d+) directors$`, c.iRecieveDirectors)
+	// Front end ports
+	s.Step(`^I have a FC port "([^"]*)" with status "([^"]*)" and (\d+) mapped volumes$`, c.iHaveAFCPortWithStatusAndMappedVolumes)
+	s.Step(`^I call GetFrontEndPorts with type "([^"]*)" and online only "([^"]*)"$`, c.iCallGetFrontEndPortsWithTypeAndOnlineOnly)
+	s.Step(`^I recieve (\d+) front end ports$`, c.iRecieveFrontEndPorts)
+	s.Step(`^I call SelectLeastLoadedFCPorts (-?\d+)$`, c.iCallSelectLeastLoadedFCPorts)
+	s.Step(`^I get the ports "([^"]*)" if no error$`, c.iGetThePorts)
+	s.Step(`^I call GetISCSITargets$`, c.iCallGetISCSITargets)
+	s.Step(`^I call GetISCSITargetsForPortGroup$`, c.iCallGetISCSITargetsForPortGroup)
+	s.Step(`^I recieve (\d+) iSCSI targets$`, c.iRecieveISCSITargets)
+	s.Step(`^I call CreateISCSIPortGroup "([^"]*)" with targets "([^"]*)"$`, c.iCallCreateISCSIPortGroupWithTargets)
 	s.Step(`^I call GetStoragePool "([^"]*)"$`, c.iCallGetStoragePool)
 	s.Step(`^I get a valid GetStoragePool if no errors$`, c.iGetAValidGetStoragePoolIfNoErrors)
 	// Whitelists
@@ -1412,6 +3495,103 @@ func UnitTestContext(s *godog.Suite) {
 	s.Step(`^I call DeleteSnapshot with "([^"]*)", snapshot "([^"]*)" and (\d+)  on it$`, c.iCallDeleteSnapshotWithSnapshotAndOnIt)
 	s.Step(`^I call GetPrivVolumeByID with "([^"]*)"$`, c.iCallGetPrivVolumeByIDWith)
 	s.Step(`^I should get a private volume information if no error$`, c.iShouldGetAPrivateVolumeInformationIfNoError)
+	s.Step(`^I call RestoreSnapshot with "([^"]*)" and snapshot "([^"]*)"$`, c.iCallRestoreSnapshotWithAndSnapshot)
+	s.Step(`^the snapshot "([^"]*)" of "([^"]*)" is established and not restored if no error$`, c.theSnapshotOfIsEstablishedAndNotRestoredIfNoError)
+	s.Step(`^I call CreateSnapshotWithOptions with "([^"]*)" and snapshot "([^"]*)" with ttl (\d+) in hours "([^"]*)" and secure "([^"]*)"$`, c.iCallCreateSnapshotWithOptionsWithAndSnapshotTTLInHoursAndSecure)
+	s.Step(`^I call SetSnapshotTTL "([^"]*)" on "([^"]*)" with ttl (\d+) in hours "([^"]*)" and secure "([^"]*)"$`, c.iCallSetSnapshotTTLOnWithTTLInHoursAndSecure)
+	s.Step(`^I call GetSnapshotRetention with "([^"]*)" and snapshot "([^"]*)"$`, c.iCallGetSnapshotRetentionWithAndSnapshot)
+	s.Step(`^the snapshot retention is secure "([^"]*)" with a time to live of (\d+) hours if no error$`, c.theSnapshotRetentionIsSecureWithATimeToLiveOfHoursIfNoError)
+	s.Step(`^I call CloneVolume "([^"]*)" to "([^"]*)" named "([^"]*)" with copy "([^"]*)"$`, c.iCallCloneVolumeToNamedWithCopy)
+	s.Step(`^the clone "([^"]*)" of "([^"]*)" is linked "([^"]*)" if no error$`, c.theCloneOfIsLinkedIfNoError)
+	s.Step(`^the clone "([^"]*)" of "([^"]*)" is unlinked from its leftover snapshot$`, c.theCloneOfIsUnlinkedFromItsLeftoverSnapshot)
+	s.Step(`^the clone "([^"]*)" of "([^"]*)" is cleaned up if error$`, c.theCloneOfIsCleanedUpIfError)
+	s.Step(`^snapshot "([^"]*)" on "([^"]*)" was created (\d+) hours ago$`, c.snapshotOnWasCreatedHoursAgo)
+	s.Step(`^snapshot "([^"]*)" on "([^"]*)" was taken again$`, c.snapshotOnWasTakenAgain)
+	s.Step(`^I call CollectSnapshots with prefix "([^"]*)" expired "([^"]*)" max age (\d+) hours orphaned "([^"]*)" dry run "([^"]*)" and max deletions (\d+)$`, c.iCallCollectSnapshotsWithPrefixExpiredMaxAgeHoursOrphanedDryRunAndMaxDeletions)
+	s.Step(`^volume "([^"]*)" is renamed "([^"]*)"$`, c.volumeIsRenamed)
+	s.Step(`^I call CollectSnapshots of the volumes renamed with prefix "([^"]*)"$`, c.iCallCollectSnapshotsOfTheVolumesRenamedWithPrefix)
+	s.Step(`^the snapshot collection found "([^"]*)" and deleted "([^"]*)" if no error$`, c.theSnapshotCollectionFoundAndDeletedIfNoError)
+	s.Step(`^the snapshot collection skipped "([^"]*)" if no error$`, c.theSnapshotCollectionSkippedIfNoError)
+	s.Step(`^I call GetVolumeSnapshotGenerations with "([^"]*)"$`, c.iCallGetVolumeSnapshotGenerationsWith)
+	s.Step(`^I get (\d+) snapshot generations with latest "([^"]*)" and "([^"]*)" older than (\d+) hours linked to "([^"]*)" if no error$`, c.iGetSnapshotGenerationsWithLatestAndOlderThanHoursLinkedToIfNoError)
+	s.Step(`^the snapshot generations of "([^"]*)" match their private volume headers if no error$`, c.theSnapshotGenerationsOfMatchTheirPrivateVolumeHeadersIfNoError)
+	s.Step(`^I call LinkSnapshot "([^"]*)" to "([^"]*)" with copy "([^"]*)" and relink "([^"]*)"$`, c.iCallLinkSnapshotToWithCopyAndRelink)
+	s.Step(`^I call CreateConsistentSnapshot "([^"]*)" of "([^"]*)"$`, c.iCallCreateConsistentSnapshotOf)
+	s.Step(`^volume "([^"]*)" has a snapshot "([^"]*)" linked to "([^"]*)"$`, c.volumeHasASnapshotLinkedTo)
+	s.Step(`^volume "([^"]*)" is cloned to "([^"]*)"$`, c.volumeIsClonedTo)
+	s.Step(`^volume "([^"]*)" is mirrored to "([^"]*)"$`, c.volumeIsMirroredTo)
+	s.Step(`^I call GetReplicationGraph of "([^"]*)"$`, c.iCallGetReplicationGraphOf)
+	s.Step(`^the replication graph has volumes "([^"]*)" if no error$`, c.theReplicationGraphHasVolumesIfNoError)
+	s.Step(`^volume "([^"]*)" can be deleted "([^"]*)" if no error$`, c.volumeCanBeDeletedIfNoError)
+	s.Step(`^the snapshot "([^"]*)" of "([^"]*)" has dependents "([^"]*)" if no error$`, c.theSnapshotOfHasDependentsIfNoError)
+	s.Step(`^I call LinkConsistentSnapshot to "([^"]*)"$`, c.iCallLinkConsistentSnapshotTo)
+	s.Step(`^the consistent snapshot of "([^"]*)" is linked to "([^"]*)" if no error$`, c.theConsistentSnapshotOfIsLinkedToIfNoError)
+	s.Step(`^volumes "([^"]*)" are linked to snapshot "([^"]*)"$`, c.volumesAreLinkedToSnapshot)
+	s.Step(`^I call WaitForLinkState "([^"]*)" for snapshot "([^"]*)" from "([^"]*)" to "([^"]*)"$`, c.iCallWaitForLinkStateForSnapshotFromTo)
+	s.Step(`^the copy of the links completes after (\d+) polls$`, c.theCopyOfTheLinksCompletesAfterPolls)
+	s.Step(`^the link to "([^"]*)" is in state "([^"]*)" and no longer linked to "([^"]*)" if no error$`, c.theLinkToIsInStateAndNoLongerLinkedToIfNoError)
+	s.Step(`^I have a link storage group with (\d+) volumes$`, c.iHaveALinkStorageGroupWithVolumes)
+	s.Step(`^I have a storage group snapshot "([^"]*)" taken (\d+) times$`, c.iHaveAStorageGroupSnapshotTakenTimes)
+	s.Step(`^I call CreateStorageGroupSnapshot "([^"]*)" with ttl (\d+) in hours "([^"]*)" and secure "([^"]*)"$`, c.iCallCreateStorageGroupSnapshotWithTTLInHoursAndSecure)
+	s.Step(`^the storage group snapshot has a time to live of (\d+) hours and is secured "([^"]*)" if no error$`, c.theStorageGroupSnapshotHasATimeToLiveOfHoursAndIsSecuredIfNoError)
+	s.Step(`^I get a valid storage group snapshot "([^"]*)" with (\d+) source volumes if no error$`, c.iGetAValidStorageGroupSnapshotWithSourceVolumesIfNoError)
+	s.Step(`^I call ListStorageGroupSnapshots$`, c.iCallListStorageGroupSnapshots)
+	s.Step(`^I get storage group snapshots "([^"]*)" if no error$`, c.iGetStorageGroupSnapshotsIfNoError)
+	s.Step(`^I call GetStorageGroupSnapshotGenerations "([^"]*)"$`, c.iCallGetStorageGroupSnapshotGenerations)
+	s.Step(`^I get (\d+) storage group snapshot generations if no error$`, c.iGetStorageGroupSnapshotGenerationsIfNoError)
+	s.Step(`^the storage group snapshot "([^"]*)" has (\d+) generations if no error$`, c.theStorageGroupSnapshotHasGenerationsIfNoError)
+	s.Step(`^I call "([^"]*)" on storage group snapshot "([^"]*)" generation (\d+)$`, c.iCallOnStorageGroupSnapshotGeneration)
+	s.Step(`^the storage group snapshot "([^"]*)" is linked "([^"]*)" and restored "([^"]*)" if no error$`, c.theStorageGroupSnapshotIsLinkedAndRestoredIfNoError)
+	s.Step(`^I have a snapshot policy "([^"]*)"$`, c.iHaveASnapshotPolicy)
+	s.Step(`^I call CreateSnapshotPolicy "([^"]*)" every "([^"]*)" keeping (\d+) snapshots$`, c.iCallCreateSnapshotPolicyEveryKeepingSnapshots)
+	s.Step(`^I get a valid snapshot policy "([^"]*)" taking a snapshot every (\d+) minutes if no error$`, c.iGetAValidSnapshotPolicyTakingASnapshotEveryMinutesIfNoError)
+	s.Step(`^I call GetSnapshotPolicyList$`, c.iCallGetSnapshotPolicyList)
+	s.Step(`^I call GetSnapshotPolicy "([^"]*)"$`, c.iCallGetSnapshotPolicy)
+	s.Step(`^I get snapshot policies "([^"]*)" if no error$`, c.iGetSnapshotPoliciesIfNoError)
+	s.Step(`^I call "([^"]*)" on snapshot policy "([^"]*)"$`, c.iCallOnSnapshotPolicy)
+	s.Step(`^the snapshot policy "([^"]*)" keeps (\d+) snapshots and is suspended "([^"]*)" if no error$`, c.theSnapshotPolicyKeepsSnapshotsAndIsSuspendedIfNoError)
+	s.Step(`^the snapshot policy "([^"]*)" has (\d+) storage groups if no error$`, c.theSnapshotPolicyHasStorageGroupsIfNoError)
+	s.Step(`^I call DeleteSnapshotPolicy "([^"]*)"$`, c.iCallDeleteSnapshotPolicy)
+	s.Step(`^the snapshot policy "([^"]*)" is deleted if no error$`, c.theSnapshotPolicyIsDeletedIfNoError)
+	s.Step(`^I call GetStorageGroupSnapshotCompliance$`, c.iCallGetStorageGroupSnapshotCompliance)
+	s.Step(`^the snapshot compliance is "([^"]*)" if no error$`, c.theSnapshotComplianceIsIfNoError)
+
+	// SRDF
+	s.Step(`^RDF group (\d+) has (\d+) online local ports$`, c.rdfGroupHasOnlineLocalPorts)
+	s.Step(`^I have RDF device pairs "([^"]*)" in RDF group (\d+)$`, c.iHaveRDFDevicePairsInRDFGroup)
+	s.Step(`^I call GetRDFGroupList$`, c.iCallGetRDFGroupList)
+	s.Step(`^I get (\d+) RDF groups if no error$`, c.iGetRDFGroupsIfNoError)
+	s.Step(`^I call GetRDFGroup (\d+)$`, c.iCallGetRDFGroup)
+	s.Step(`^I get a valid RDF group with mode "([^"]*)" and link state "([^"]*)" if no error$`, c.iGetAValidRDFGroupWithModeAndLinkStateIfNoError)
+	s.Step(`^I call GetRDFGroupVolumes (\d+)$`, c.iCallGetRDFGroupVolumes)
+	s.Step(`^I get (\d+) RDF group volumes if no error$`, c.iGetRDFGroupVolumesIfNoError)
+	s.Step(`^I call GetRDFDevicePair (\d+) with volume "([^"]*)"$`, c.iCallGetRDFDevicePairWithVolume)
+	s.Step(`^the remote volume is "([^"]*)" if no error$`, c.theRemoteVolumeIsIfNoError)
+	s.Step(`^I call CreateSGReplica in RDF group (\d+) with mode "([^"]*)" and establish "([^"]*)"$`, c.iCallCreateSGReplicaInRDFGroupWithModeAndEstablish)
+	s.Step(`^I have an SRDF protected storage group in RDF group (\d+)$`, c.iHaveAnSRDFProtectedStorageGroupInRDFGroup)
+	s.Step(`^I call ExecuteSRDFAction "([^"]*)" on RDF group (\d+)$`, c.iCallExecuteSRDFActionOnRDFGroup)
+	s.Step(`^the SRDF state of the storage group in RDF group (\d+) is "([^"]*)" if no error$`, c.theSRDFStateOfTheStorageGroupInRDFGroupIsIfNoError)
+	s.Step(`^the volumes of the storage group in RDF group (\d+) are "([^"]*)" if no error$`, c.theVolumesOfTheStorageGroupInRDFGroupAreIfNoError)
+	s.Step(`^I call DeleteSGReplica on RDF group (\d+) with force "([^"]*)"$`, c.iCallDeleteSGReplicaOnRDFGroupWithForce)
+	s.Step(`^the storage group is protected in RDF groups "([^"]*)" if no error$`, c.theStorageGroupIsProtectedInRDFGroupsIfNoError)
+	s.Step(`^I call ExpandRDFVolume "([^"]*)" in RDF group (\d+) to (\d+) in GB$`, c.iCallExpandRDFVolumeInRDFGroupToInGB)
+	s.Step(`^I call GetVolumeRDFPair "([^"]*)"$`, c.iCallGetVolumeRDFPair)
+	s.Step(`^the remote volume of "([^"]*)" has size (\d+) in GB if no error$`, c.theRemoteVolumeOfHasSizeInGBIfNoError)
+	s.Step(`^I call CreateRDFVolumeInStorageGroup with name "([^"]*)" and size (\d+)$`, c.iCallCreateRDFVolumeInStorageGroupWithNameAndSize)
+	s.Step(`^I get a valid RDF device pair in state "([^"]*)" if no error$`, c.iGetAValidRDFDevicePairInStateIfNoError)
+	s.Step(`^the created volume is paired in state "([^"]*)" if no error$`, c.theCreatedVolumeIsPairedInStateIfNoError)
+	s.Step(`^RDF group (\d+) has witness "([^"]*)"$`, c.rdfGroupHasWitness)
+	s.Step(`^the SRDF pair of volume "([^"]*)" in RDF group (\d+) is an R2$`, c.theSRDFPairOfVolumeInRDFGroupIsAnR2)
+	s.Step(`^I call GetMetroStatus on RDF group (\d+)$`, c.iCallGetMetroStatusOnRDFGroup)
+	s.Step(`^I get a Metro status in state "([^"]*)" with bias on the "([^"]*)" array and witness "([^"]*)" if no error$`, c.iGetAMetroStatusInStateWithBiasOnAndWitnessIfNoError)
+	s.Step(`^I call SetMetroBias "([^"]*)" on RDF group (\d+)$`, c.iCallSetMetroBiasOnRDFGroup)
+	s.Step(`^the Metro bias of the storage group in RDF group (\d+) is on the "([^"]*)" array if no error$`, c.theMetroBiasOfTheStorageGroupInRDFGroupIsOnIfNoError)
+
+	// Performance
+	s.Step(`^I call GetPerformanceKeys of category "([^"]*)" with director "([^"]*)"$`, c.iCallGetPerformanceKeysOfCategoryWithDirector)
+	s.Step(`^I get (\d+) performance keys including "([^"]*)" if no error$`, c.iGetPerformanceKeysIncludingIfNoError)
+	s.Step(`^I call GetPerformance of "([^"]*)" "([^"]*)" for (-?\d+) minutes starting (\d+) hours ago with interval "([^"]*)" and format "([^"]*)"$`, c.iCallGetPerformanceOfForMinutesStartingHoursAgo)
+	s.Step(`^I get (\d+) performance samples starting with ([\d.]+) IOPS if no error$`, c.iGetPerformanceSamplesStartingWithIOPSIfNoError)
 
 	s.Step(`^there should be no errors$`, c.thereShouldBeNoErrors)
 }
@@ -213,6 +213,91 @@ Feature: PMAX Client library
       | "RUNNING"      | "SUCCEEDED"      | "GetJobError"    | "induced error"           | ""        |
       | "RUNNING"      | "SUCCEEDED"      | "none"           | "ignored via a whitelist" | "ignored" |
 
+    Scenario Outline: Observe the requests and job waits of WaitOnJobCompletion
+      Given a valid connection with an observer
+      And I have a whitelist of <whitelist>
+      And I induce error <induced>
+      And I create a job with initial state <initial> and final state <final>
+      When I call WaitOnJobCompletion
+      Then the error message contains <errormsg>
+      And the observer saw <requests> "GET" requests ending with "/system/symmetrix/{id}/job/{id}" with status <status>
+      And the observer saw <waits> job waits with status <jobstatus> after <polls> polls
+
+      Examples:
+      | initial   | final       | requests | status | waits | jobstatus   | polls | induced       | errormsg                  | whitelist |
+      | "RUNNING" | "SUCCEEDED" | 2        | 200    | 1     | "SUCCEEDED" | 2     | "none"        | "none"                    | ""        |
+      | "RUNNING" | "FAILED"    | 2        | 200    | 1     | "FAILED"    | 2     | "none"        | "none"                    | ""        |
+      | "RUNNING" | "SUCCEEDED" | 1        | 408    | 1     | ""          | 0     | "GetJobError" | "induced error"           | ""        |
+      | "RUNNING" | "SUCCEEDED" | 0        | 200    | 0     | ""          | 0     | "none"        | "ignored via a whitelist" | "ignored" |
+
+    Scenario Outline: Trace the operations and requests of CreateVolumeInStorageGroup
+      Given a valid connection with a tracer and caller span <caller>
+      And I have a whitelist of <whitelist>
+      And I induce error <induced>
+      When I call CreateVolumeInStorageGroup with name "IntgTrace" and size 1
+      Then the error message contains <errormsg>
+      And the tracer recorded 1 "CreateVolumeInStorageGroup" spans children of <caller>
+      And the tracer recorded <updates> "UpdateStorageGroup" spans children of "CreateVolumeInStorageGroup"
+      And the tracer recorded <waits> "WaitOnJobCompletion" spans children of "CreateVolumeInStorageGroup"
+      And the tracer recorded <updates> "HTTP PUT" spans children of "UpdateStorageGroup"
+      And the span "CreateVolumeInStorageGroup" has attribute "pmax.symmetrix_id" with value "000197900046"
+      And the span "CreateVolumeInStorageGroup" has attribute "pmax.storage_group_id" with value "CSI-Test-SG-1"
+      And the span "HTTP PUT" has attribute "http.route" with value "univmax/restapi/90/sloprovisioning/symmetrix/{id}/storagegroup/{id}"
+      And the requests carried the trace context of their span
+      And the span "CreateVolumeInStorageGroup" ended with error <errormsg>
+      And the span "UpdateStorageGroup" ended with error <updateerror>
+
+      Examples:
+      | caller             | updates | waits | induced                   | errormsg                                         | updateerror     | whitelist |
+      | "csi.CreateVolume" | 1       | 1     | "none"                    | "none"                                           | "none"          | ""        |
+      | ""                 | 1       | 1     | "none"                    | "none"                                           | "none"          | ""        |
+      | "csi.CreateVolume" | 1       | 0     | "UpdateStorageGroupError" | "A job was not returned from UpdateStorageGroup" | "induced error" | ""        |
+      | "csi.CreateVolume" | 0       | 0     | "none"                    | "ignored via a whitelist"                        | "none"          | "ignored" |
+
+    Scenario Outline: Trace the operations of a client used concurrently
+      Given a valid connection with a tracer and caller span <caller>
+      And I have 1 volumes
+      When I call GetVolumeByID <volID> concurrently 8 times
+      Then the error message contains <errormsg>
+      And the tracer recorded 8 "GetVolumeByID" spans children of <caller>
+      And each "GetVolumeByID" span has 1 "HTTP GET" child
+      And the span "GetVolumeByID" ended with error <errormsg>
+
+      Examples:
+      | caller             | volID   | errormsg                 |
+      | "csi.CreateVolume" | "00001" | "none"                   |
+      | ""                 | "00001" | "none"                   |
+      | "csi.CreateVolume" | "00009" | "Volume cannot be found" |
+
+    Scenario Outline: Log the requests and responses of CreateVolumeInStorageGroup without credentials
+      Given a valid connection with wire logging of at most <maxbody> body bytes
+      And I have a whitelist of <whitelist>
+      And I induce error <induced>
+      When I call CreateVolumeInStorageGroup with name "IntgWireLog" and size 1
+      Then the error message contains <errormsg>
+      And the wire log has <requests> requests and responses sharing their correlation ID
+      And the wire log has no credentials
+      And the wire log bodies are truncated to <maxbody> bytes
+
+      Examples:
+      | maxbody | requests | induced                   | errormsg                                         | whitelist |
+      | 4096    | 5        | "none"                    | "none"                                           | ""        |
+      | 16      | 5        | "none"                    | "none"                                           | ""        |
+      | 4096    | 2        | "UpdateStorageGroupError" | "A job was not returned from UpdateStorageGroup" | ""        |
+      | 4096    | 0        | "none"                    | "ignored via a whitelist"                        | "ignored" |
+
+    Scenario Outline: Redact the credentials of JSON documents
+      When I redact the JSON '<document>'
+      Then the redacted JSON is '<redacted>'
+
+      Examples:
+      | document                                          | redacted                                                |
+      | {"username":"admin","password":"secret"}          | {"username":"admin","password":"[REDACTED]"}            |
+      | {"chap_secret" : "a\"b", "name":"host"}           | {"chap_secret" : "[REDACTED]", "name":"host"}           |
+      | {"auth":{"access_token":"abc"},"count":1}         | {"auth":{"access_token":"[REDACTED]"},"count":1}        |
+      | {"volumeId":"00123","Password":"trunc             | {"volumeId":"00123","Password":"[REDACTED]"             |
+      | {"volumeId":"00123"}                              | {"volumeId":"00123"}                                    |
+
     Scenario Outline: Test cases for CreateVolumeInStorageGroup
       Given a valid connection
       And I have a whitelist of <whitelist>
@@ -226,7 +311,7 @@ Feature: PMAX Client library
       | "IntgA"                                                                        | 1        | "none"                    | "none"                                                 | ""        |
       | "IntgB"                                                                        | 5        | "none"                    | "none"                                                 | ""        |
       | "IntgC"                                                                        | 1        | "UpdateStorageGroupError" | "A job was not returned from UpdateStorageGroup"       | ""        |
-      | "IntgD"                                                                        | 1        | "httpStatus500"           | "A job was not returned from UpdateStorageGroup"       | ""        |
+      | "IntgD"                                                                        | 1        | "httpStatus500"           | "Internal Error"                                       | ""        |
       | "IntgE"                                                                        | 1        | "GetJobError"             | "induced error"                                        | ""        |
       | "IntgF"                                                                        | 1        | "JobFailedError"          | "The UpdateStorageGroup job failed"                    | ""        |
       | "IntgG"                                                                        | 1        | "GetVolumeError"          | "Failed to find newly created volume with name: IntgG" | ""        |
@@ -384,6 +469,55 @@ Feature: PMAX Client library
       | "DeleteMaskingViewError"       | "induced error"              | "CSI-Test-MV"          | ""        |
       | "none"                         | "ignored via a whitelist"    | "CSI-Test-MV"          | "ignored" |
 
+    Scenario Outline: Test RenameMaskingView
+      Given a valid connection
+      And I have a whitelist of <whitelist>
+      And I have a MaskingView <mvname>
+      And I induce error <induced>
+      When I call RenameMaskingView with <newname>
+      Then the error message contains <errormsg>
+      And I get a valid MaskingView with name <newname> if no error
+
+      Examples:
+      | induced                        | errormsg                     | mvname                 | newname            | whitelist |
+      | "none"                         | "none"                       | "CSI-Test-MV"          | "CSI-Test-MV-New"  | ""        |
+      | "none"                         | "already exists"             | "CSI-Test-MV"          | "CSI-Test-MV-1"    | ""        |
+      | "RenameMaskingViewError"       | "induced error"              | "CSI-Test-MV"          | "CSI-Test-MV-New"  | ""        |
+      | "none"                         | "ignored via a whitelist"    | "CSI-Test-MV"          | "CSI-Test-MV-New"  | "ignored" |
+
+    Scenario Outline: Test GetMaskingViewsFor host, storage group and port group
+      Given a valid connection
+      And I have a whitelist of <whitelist>
+      And I have a MaskingView <mvname>
+      And I induce error <induced>
+      When I call GetMaskingViewsFor <resource>
+      Then the error message contains <errormsg>
+      And I get a valid MaskingViewList if no error
+      And the MaskingViewList does not contain "CSI-Test-MV-1" if no error
+
+      Examples:
+      | resource         | induced                        | errormsg                     | mvname                 | whitelist |
+      | "Host"           | "none"                         | "none"                       | "CSI-Test-MV"          | ""        |
+      | "StorageGroup"   | "none"                         | "none"                       | "CSI-Test-MV"          | ""        |
+      | "PortGroup"      | "none"                         | "none"                       | "CSI-Test-MV"          | ""        |
+      | "Host"           | "GetMaskingViewError"          | "induced error"              | "CSI-Test-MV"          | ""        |
+      | "StorageGroup"   | "GetMaskingViewError"          | "induced error"              | "CSI-Test-MV"          | ""        |
+      | "PortGroup"      | "none"                         | "ignored via a whitelist"    | "CSI-Test-MV"          | "ignored" |
+
+    Scenario Outline: Test GetMaskingViewsForHost with a host name needing escaping
+      Given a valid connection
+      And I have a whitelist of <whitelist>
+      And I have a MaskingView "CSI-Test-MV"
+      When I call GetMaskingViewsForHost with <host>
+      Then the error message contains <errormsg>
+      And the MaskingViewList does not contain "CSI-Test-MV" if no error
+
+      Examples:
+      | host             | errormsg                  | whitelist |
+      | "#CSI-Test-Host" | "none"                    | ""        |
+      | "CSI Host & Co"  | "none"                    | ""        |
+      | "#CSI-Test-Host" | "ignored via a whitelist" | "ignored" |
+
     Scenario Outline: Test GetPortGroupList
       Given a valid connection
       And I have a whitelist of <whitelist>
@@ -427,6 +561,52 @@ Feature: PMAX Client library
       | "Test-CreatePG"       | "SE-1E:000,SE-2E:001" | "SE-1E:000,SE-2E:001" | "none"                 | "none"          |
       | "Test-CreatePG-error" | "SE-1E:000,SE-2E:001" | ""                    | "CreatePortGroupError" | "induced error" |
 
+  Scenario Outline: Test RenamePortGroup
+    Given a valid connection
+    And I call CreatePortGroup <groupname> with ports "SE-1E:000,SE-2E:001"
+    And I induce error <induced>
+    When I call RenamePortGroup <groupname> to <newname>
+    Then the error message contains <errormsg>
+    And I get PortGroup <newname> if no error
+    Then I expect PortGroup to have these ports <finalPorts>
+
+    Examples:
+      | groupname             | newname               | finalPorts            | induced                | errormsg         |
+      | "Test-RenamePG"       | "Test-RenamedPG"      | "SE-1E:000,SE-2E:001" | "none"                 | "none"           |
+      | "Test-RenamePG"       | "csi-pg"              | ""                    | "none"                 | "already exists" |
+      | "Test-RenamePG"       | "Test-RenamedPG"      | ""                    | "UpdatePortGroupError" | "induced error"  |
+
+  Scenario Outline: Test AddPortsToPortGroup and RemovePortsFromPortGroup
+    Given a valid connection
+    And I call CreatePortGroup <groupname> with ports <initialPorts>
+    And I induce error <induced>
+    When I call AddPortsToPortGroup <groupname> with ports <addPorts>
+    And I call RemovePortsFromPortGroup <groupname> with ports <removePorts>
+    Then the error message contains <errormsg>
+    Then I expect PortGroup to have these ports <finalPorts>
+
+    Examples:
+      | groupname             | initialPorts          | addPorts              | removePorts           | finalPorts                      | induced                | errormsg        |
+      | "Test-IncrementalPG"  | "SE-1E:000"           | "SE-2E:001,SE-3E:000" | "SE-1E:000"           | "SE-2E:001,SE-3E:000"           | "none"                 | "none"          |
+      | "Test-IncrementalPG"  | "SE-1E:000"           | "SE-2E:001"           | ""                    | "SE-1E:000,SE-2E:001"           | "none"                 | "none"          |
+      | "Test-IncrementalPG"  | "SE-1E:000"           | "SE-2E:001"           | "SE-1E:000"           | ""                              | "UpdatePortGroupError" | "induced error" |
+
+  Scenario Outline: Test GetPortGroupDetails
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have a PortGroup
+    And I induce error <induced>
+    When I call GetPortGroupDetails
+    Then the error message contains <errormsg>
+    And I get valid PortGroupDetails if no error
+
+    Examples:
+      | induced                | errormsg                  | whitelist |
+      | "none"                 | "none"                    | ""        |
+      | "GetPortGroupError"    | "induced error"           | ""        |
+      | "GetPortError"         | "Error retrieving Port"   | ""        |
+      | "none"                 | "ignored via a whitelist" | "ignored" |
+
   Scenario Outline: Test UpdatePortGroup
     Given a valid connection
     And I induce error <induced>
@@ -561,6 +741,63 @@ Feature: PMAX Client library
       |errormsg          | whitelist |
       | "none"           | ""        |
 
+    Scenario Outline: Test GetInitiatorListWithFilter
+      Given a valid connection
+      And I have a whitelist of <whitelist>
+      And I have a logged out FC Initiator
+      And I induce error <induced>
+      When I call GetInitiatorListWithFilter <filter>
+      Then the error message contains <errormsg>
+      And I get <count> initiators in the list if no error
+
+      Examples:
+      | filter               | induced             | errormsg                  | count | whitelist |
+      | "none"               | "none"              | "none"                    | 8     | ""        |
+      | "iscsi"              | "none"              | "none"                    | 3     | ""        |
+      | "in_host"            | "none"              | "none"                    | 7     | ""        |
+      | "logged_in"          | "none"              | "none"                    | 7     | ""        |
+      | "logged_out"         | "none"              | "none"                    | 1     | ""        |
+      | "off_fabric"         | "none"              | "none"                    | 1     | ""        |
+      | "director"           | "none"              | "none"                    | 3     | ""        |
+      | "director_port"      | "none"              | "none"                    | 2     | ""        |
+      | "host_group"         | "none"              | "none"                    | 0     | ""        |
+      | "escaped_host_group" | "none"              | "none"                    | 0     | ""        |
+      | "hba"                | "none"              | "none"                    | 1     | ""        |
+      | "none"               | "GetInitiatorError" | "induced error"           | 0     | ""        |
+      | "none"               | "none"              | "ignored via a whitelist" | 0     | "ignored" |
+
+    Scenario Outline: Test SetInitiatorAlias
+      Given a valid connection
+      And I have a whitelist of <whitelist>
+      And I induce error <induced>
+      When I call SetInitiatorAlias <node> <port>
+      Then the error message contains <errormsg>
+      And the initiator alias is <alias> if no error
+
+      Examples:
+      | node      | port       | induced                | errormsg                  | alias            | whitelist |
+      | "node1"   | "iscsi0"   | "none"                 | "none"                    | "node1/iscsi0"   | ""        |
+      | "node1"   | "iscsi0"   | "UpdateInitiatorError" | "induced error"           | "none"           | ""        |
+      | "node1"   | "iscsi0"   | "none"                 | "ignored via a whitelist" | "none"           | "ignored" |
+
+    Scenario Outline: Test GetInitiatorsForHost
+      Given a valid connection
+      And I have a whitelist of <whitelist>
+      And I induce error <induced>
+      When I call GetInitiatorsForHost <host>
+      Then the error message contains <errormsg>
+      And I get <count> initiators for the host if no error
+
+      Examples:
+      | host                 | induced                 | errormsg                  | count | whitelist |
+      | "CSI-Test-Node-1"    | "none"                  | "none"                    | 1     | ""        |
+      | "CSI-Test-Node-2"    | "none"                  | "none"                    | 2     | ""        |
+      | "CSI-Test-Node-3-FC" | "none"                  | "none"                    | 4     | ""        |
+      | "CSI-Test-Node-3-FC" | "GetHostError"          | "induced error"           | 0     | ""        |
+      | "CSI-Test-Node-3-FC" | "GetInitiatorError"     | "induced error"           | 0     | ""        |
+      | "CSI-Test-Node-3-FC" | "GetInitiatorByIDError" | "induced error"           | 0     | ""        |
+      | "CSI-Test-Node-1"    | "none"                  | "ignored via a whitelist" | 0     | "ignored" |
+
     Scenario Outline: Test GetInitiatorByID
       Given a valid connection
       And I have a whitelist of <whitelist>
@@ -647,6 +884,120 @@ Feature: PMAX Client library
       | 0     | "GetDirectorError"        | "Error retrieving Director"                              | ""        |
       | 0     | "none"                    | "ignored via a whitelist"                                | "ignored" |
 
+    Scenario Outline: Test GetISCSITargets
+      Given a valid connection
+      And I have a whitelist of <whitelist>
+      And I induce error <induced>
+      When I call GetISCSITargets
+      Then the error message contains <errormsg>
+      And I recieve <count> iSCSI targets
+      Examples:
+      | count | induced                   | errormsg                                                 | whitelist |
+      | 8     | "none"                    | "none"                                                   | ""        |
+      | 0     | "GetPortError"            | "Error retrieving Port"                                  | ""        |
+      | 0     | "GetDirectorError"        | "Error retrieving Director"                              | ""        |
+      | 0     | "none"                    | "ignored via a whitelist"                                | "ignored" |
+
+    Scenario Outline: Test GetDirector
+      Given a valid connection
+      And I have a whitelist of <whitelist>
+      And I induce error <induced>
+      When I call GetDirector <director>
+      Then the error message contains <errormsg>
+      And I get a valid Director <director> of type <type> if no error
+      Examples:
+      | director  | type  | induced                   | errormsg                      | whitelist |
+      | "SE-1E"   | "SE"  | "none"                    | "none"                        | ""        |
+      | "FA-2D"   | "FA"  | "none"                    | "none"                        | ""        |
+      | "RF-1F"   | "RF"  | "none"                    | "none"                        | ""        |
+      | "SE-1E"   | "SE"  | "GetDirectorError"        | "Error retrieving Director"   | ""        |
+      | "SE-1E"   | "SE"  | "none"                    | "ignored via a whitelist"     | "ignored" |
+
+    Scenario Outline: Test ListDirectors
+      Given a valid connection
+      And I have a whitelist of <whitelist>
+      And I have a director "FA-2D" with availability "Offline"
+      And I induce error <induced>
+      When I call ListDirectors with types <types> front end only <frontend> online only <online>
+      Then the error message contains <errormsg>
+      And I recieve <count> directors
+      Examples:
+      | types     | frontend | online  | count | induced            | errormsg                      | whitelist |
+      | ""        | "false"  | "false" | 6     | "none"             | "none"                        | ""        |
+      | ""        | "true"   | "false" | 4     | "none"             | "none"                        | ""        |
+      | ""        | "true"   | "true"  | 3     | "none"             | "none"                        | ""        |
+      | "RF"      | "false"  | "false" | 2     | "none"             | "none"                        | ""        |
+      | "FA,SE"   | "false"  | "true"  | 3     | "none"             | "none"                        | ""        |
+      | ""        | "true"   | "true"  | 0     | "GetDirectorError" | "Error retrieving Director"   | ""        |
+      | ""        | "true"   | "true"  | 0     | "none"             | "ignored via a whitelist"     | "ignored" |
+
+    Scenario Outline: Test GetFrontEndPorts
+      Given a valid connection
+      And I have a whitelist of <whitelist>
+      And I have a FC port "FA-1D:1" with status "OFF" and 3 mapped volumes
+      And I induce error <induced>
+      When I call GetFrontEndPorts with type <type> and online only <online>
+      Then the error message contains <errormsg>
+      And I recieve <count> front end ports
+      Examples:
+      | type             | online  | count | induced                   | errormsg                      | whitelist |
+      | ""               | "false" | 8     | "none"                    | "none"                        | ""        |
+      | "FibreChannel"   | "false" | 4     | "none"                    | "none"                        | ""        |
+      | "FibreChannel"   | "true"  | 3     | "none"                    | "none"                        | ""        |
+      | "GigE"           | "true"  | 4     | "none"                    | "none"                        | ""        |
+      | ""               | "false" | 0     | "GetPortError"            | "Error retrieving Port"       | ""        |
+      | ""               | "false" | 0     | "GetDirectorError"        | "Error retrieving Director"   | ""        |
+      | ""               | "false" | 0     | "none"                    | "ignored via a whitelist"     | "ignored" |
+
+    Scenario Outline: Test SelectLeastLoadedFCPorts
+      Given a valid connection
+      And I have a FC port "FA-1D:0" with status <status> and 1 mapped volumes
+      And I have a FC port "FA-1D:1" with status "ON" and 3 mapped volumes
+      And I induce error <induced>
+      When I call SelectLeastLoadedFCPorts <count>
+      Then the error message contains <errormsg>
+      And I get the ports <ports> if no error
+      Examples:
+      | status | count | ports                             | induced            | errormsg                    |
+      | "ON"   | 1     | "FA-1D:0"                         | "none"             | "none"                      |
+      | "ON"   | 2     | "FA-1D:0,FA-2D:0"                 | "none"             | "none"                      |
+      | "ON"   | 3     | "FA-1D:0,FA-2D:0,FA-1D:1"         | "none"             | "none"                      |
+      | "ON"   | 9     | "FA-1D:0,FA-2D:0,FA-1D:1,FA-2D:1" | "none"             | "none"                      |
+      | "OFF"  | 2     | "FA-1D:1,FA-2D:0"                 | "none"             | "none"                      |
+      | "ON"   | 2     | ""                                | "GetPortError"     | "Error retrieving Port"     |
+      | "ON"   | 0     | ""                                | "none"             | "must be positive, got 0"   |
+      | "ON"   | -1    | ""                                | "none"             | "must be positive, got -1"  |
+
+    Scenario Outline: Test GetISCSITargetsForPortGroup
+      Given a valid connection
+      And I have a whitelist of <whitelist>
+      And I have a PortGroup
+      And I induce error <induced>
+      When I call GetISCSITargetsForPortGroup
+      Then the error message contains <errormsg>
+      And I recieve <count> iSCSI targets
+      Examples:
+      | count | induced                   | errormsg                                                 | whitelist |
+      | 1     | "none"                    | "none"                                                   | ""        |
+      | 0     | "GetPortGroupError"       | "induced error"                                          | ""        |
+      | 0     | "GetPortError"            | "Error retrieving Port"                                  | ""        |
+      | 0     | "none"                    | "ignored via a whitelist"                                | "ignored" |
+
+    Scenario Outline: Test CreateISCSIPortGroup
+      Given a valid connection
+      And I induce error <induced>
+      When I call CreateISCSIPortGroup <groupname> with targets <targets>
+      Then the error message contains <errormsg>
+      And I get PortGroup <groupname> if no error
+      And I expect PortGroup to have these ports <finalPorts>
+      Examples:
+      | groupname           | targets             | finalPorts          | induced                | errormsg                    |
+      | "Test-iSCSI-PG"     | "SE-1E:0,SE-2E:1"   | "SE-1E:0,SE-2E:1"   | "none"                 | "none"                      |
+      | "Test-iSCSI-PG"     | "SE-1E:0,SE-9E:1"   | ""                  | "none"                 | "not found on array"        |
+      | "Test-iSCSI-PG"     | ""                  | ""                  | "none"                 | "At least one iSCSI target" |
+      | "Test-iSCSI-PG"     | "SE-1E:0"           | ""                  | "GetDirectorError"     | "Error retrieving Director" |
+      | "Test-iSCSI-PG"     | "SE-1E:0"           | ""                  | "CreatePortGroupError" | "induced error"             |
+
     Scenario Outline: Test Array whitelists
       Given a valid connection
       And I have a whitelist of <whitelist>
@@ -682,3 +1033,49 @@ Feature: PMAX Client library
       | "000000000000"  | "none"                | "ignored via a whitelist"   |
       | "000197900046"  | "000197900046"        | "none"                      |
       | "000197900046"  | "000197802104"        | "ignored via a whitelist"   |
+
+    Scenario Outline: Test GetPerformanceKeys
+      Given a valid connection
+      And I have a whitelist of <whitelist>
+      And I induce error <induced>
+      When I call GetPerformanceKeys of category <category> with director <director>
+      Then the error message contains <errormsg>
+      And I get <count> performance keys including <key> if no error
+      Examples:
+      | category       | director | count | key             | induced                   | errormsg                  | whitelist |
+      | "Array"        | ""       | 1     | "000197900046"  | "none"                    | "none"                    | ""        |
+      | "StorageGroup" | ""       | 6     | "CSI-Test-SG-1" | "none"                    | "none"                    | ""        |
+      | "FEDirector"   | ""       | 4     | "SE-1E"         | "none"                    | "none"                    | ""        |
+      | "FEPort"       | "FA-1D"  | 2     | "1"             | "none"                    | "none"                    | ""        |
+      | "FEPort"       | "RF-1F"  | 0     | ""              | "none"                    | "cannot be found"         | ""        |
+      | "Array"        | ""       | 0     | ""              | "GetPerformanceKeysError" | "induced error"           | ""        |
+      | "Array"        | ""       | 0     | ""              | "none"                    | "ignored via a whitelist" | "ignored" |
+
+    Scenario Outline: Test GetPerformance
+      Given a valid connection
+      And I have 2 volumes
+      And I have a whitelist of <whitelist>
+      And I induce error <induced>
+      When I call GetPerformance of <category> <id> for <minutes> minutes starting <hours> hours ago with interval <interval> and format <format>
+      Then the error message contains <errormsg>
+      And I get <count> performance samples starting with <iops> IOPS if no error
+      Examples:
+      | category       | id              | minutes | hours | interval | format    | count | iops  | induced                      | errormsg                           | whitelist |
+      | "Array"        | ""              | 60      | 2     | "0s"     | ""        | 12    | 100   | "none"                       | "none"                             | ""        |
+      | "Array"        | ""              | 180     | 4     | "0s"     | ""        | 36    | 100   | "none"                       | "none"                             | ""        |
+      | "Array"        | ""              | 180     | 4     | "1h"     | "Maximum" | 3     | 111   | "none"                       | "none"                             | ""        |
+      | "Array"        | ""              | 60      | 2     | "30m"    | "Average" | 2     | 102.5 | "none"                       | "none"                             | ""        |
+      | "Array"        | ""              | 60      | 2     | "30m"    | "Maximum" | 2     | 105   | "none"                       | "none"                             | ""        |
+      | "StorageGroup" | "CSI-Test-SG-1" | 60      | 2     | "1h"     | "Maximum" | 1     | 111   | "none"                       | "none"                             | ""        |
+      | "Volume"       | "00001"         | 30      | 2     | "5m"     | ""        | 6     | 100   | "none"                       | "none"                             | ""        |
+      | "FEDirector"   | "FA-1D"         | 30      | 2     | "0s"     | ""        | 6     | 100   | "none"                       | "none"                             | ""        |
+      | "FEPort"       | "FA-1D:0"       | 30      | 2     | "0s"     | ""        | 6     | 100   | "none"                       | "none"                             | ""        |
+      | "StorageGroup" | "NoSuchSG"      | 60      | 2     | "0s"     | ""        | 0     | 0     | "none"                       | "cannot be found"                  | ""        |
+      | "Volume"       | "00009"         | 60      | 2     | "0s"     | ""        | 0     | 0     | "none"                       | "cannot be found"                  | ""        |
+      | "FEDirector"   | "RF-1F"         | 60      | 2     | "0s"     | ""        | 0     | 0     | "none"                       | "cannot be found"                  | ""        |
+      | "Array"        | ""              | -60     | 2     | "0s"     | ""        | 0     | 0     | "none"                       | "must be before its end time"      | ""        |
+      | "Array"        | ""              | 60      | 2     | "0s"     | "Median"  | 0     | 0     | "none"                       | "not a supported performance data" | ""        |
+      | "Array"        | ""              | 60      | 200   | "0s"     | ""        | 0     | 0     | "none"                       | "first available date"             | ""        |
+      | "Array"        | ""              | 60      | 2     | "0s"     | ""        | 0     | 0     | "GetPerformanceMetricsError" | "induced error"                    | ""        |
+      | "FEDirector"   | "FA-1D"         | 60      | 2     | "0s"     | ""        | 0     | 0     | "GetPerformancePageError"    | "induced error"                    | ""        |
+      | "Array"        | ""              | 60      | 2     | "0s"     | ""        | 0     | 0     | "none"                       | "ignored via a whitelist"          | "ignored" |
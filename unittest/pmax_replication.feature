@@ -199,6 +199,585 @@ Feature: PMAX replication test
       |  "00001"      | "snapshot1" |  "ignored via a whitelist" | "ignored" | "none"           |
       |  "00001"      | "snapshot1" | "Job status not successful"|    ""     | "JobFailedError" |
  
+  Scenario Outline: Link a snapshot with options and wait for the link state
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 4 volumes
+    And I call CreateSnapshot with "00001,00002" and snapshot "snapshot1" on it
+    And I call CreateSnapshot with "00001,00002" and snapshot "snapshot2" on it
+    And volumes "00003,00004" are linked to snapshot <prelinked>
+    And I induce error <induced>
+    When I call LinkSnapshot <snapID> to "00003,00004" with copy <copy> and relink <relink>
+    And I call WaitForLinkState <state> for snapshot <snapID> from "00001" to "00003"
+    Then the error message contains <errormsg>
+    And the link to "00003" is in state <state> and no longer linked to <prelinked> if no error
+
+    Examples:
+      | prelinked   | snapID      | copy    | relink  | state    | errormsg                           | whitelist | induced                       |
+      | ""          | "snapshot1" | "false" | "false" | "Linked" | "none"                             |    ""     | "none"                        |
+      | ""          | "snapshot1" | "true"  | "false" | "Copied" | "none"                             |    ""     | "none"                        |
+      | "snapshot1" | "snapshot2" | "false" | "true"  | "Linked" | "none"                             |    ""     | "none"                        |
+      | "snapshot1" | "snapshot2" | "true"  | "true"  | "Copied" | "none"                             |    ""     | "none"                        |
+      | ""          | "snapshot2" | "false" | "true"  | "Linked" | "is not linked"                    |    ""     | "none"                        |
+      | "snapshot1" | "snapshot1" | "false" | "false" | "Linked" | "devices already in desired state" |    ""     | "none"                        |
+      | ""          | "snapshot1" | "true"  | "false" | "Copied" | "did not reach state Copied"       |    ""     | "SnapshotCopyInProgressError" |
+      | ""          | "snapshot1" | "false" | "false" | "Linked" | "did not reach state Linked"       |    ""     | "TargetNotDefinedError"       |
+      | ""          | "snapshot1" | "false" | "false" | "Linked" | "induced error"                    |    ""     | "LinkSnapshotError"           |
+      | ""          | "snapshot1" | "false" | "false" | "Linked" | "Job status not successful"        |    ""     | "JobFailedError"              |
+      | ""          | "snapshot1" | "false" | "false" | "Linked" | "ignored via a whitelist"          | "ignored" | "none"                        |
+
+  Scenario Outline: Observe the retries of WaitForLinkState
+    Given a valid connection with an observer
+    And I have 4 volumes
+    And I call CreateSnapshot with "00001,00002" and snapshot "snapshot1" on it
+    And I induce error <induced>
+    And the copy of the links completes after <polls> polls
+    When I call LinkSnapshot "snapshot1" to "00003,00004" with copy <copy> and relink "false"
+    And I call WaitForLinkState <state> for snapshot "snapshot1" from "00001" to "00003"
+    Then the error message contains <errormsg>
+    And the observer saw <retries> retries of "WaitForLinkState"
+    And the observer saw a call to "WaitForLinkState"
+
+    Examples:
+      | copy    | state    | polls | retries | errormsg                     | induced                       |
+      | "false" | "Linked" | 0     | 0       | "none"                       | "none"                        |
+      | "true"  | "Copied" | 0     | 0       | "none"                       | "none"                        |
+      | "true"  | "Copied" | 2     | 2       | "none"                       | "none"                        |
+      | "true"  | "Copied" | 4     | 4       | "none"                       | "none"                        |
+      | "true"  | "Copied" | 5     | 4       | "did not reach state Copied" | "none"                        |
+      | "true"  | "Copied" | 0     | 4       | "did not reach state Copied" | "SnapshotCopyInProgressError" |
+
+  Scenario Outline: Create and link a consistent snapshot of several volumes
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 6 volumes
+    And I induce error <induced>
+    When I call CreateConsistentSnapshot "dbsnap" of <volIDs>
+    And I call LinkConsistentSnapshot to <targets>
+    Then the error message contains <errormsg>
+    And the consistent snapshot of <volIDs> is linked to <targets> if no error
+
+    Examples:
+      | volIDs              | targets             | errormsg                                    | whitelist | induced               |
+      | "00001,00002,00003" | "00004,00005,00006" | "none"                                      | ""        | "none"                |
+      | "00003"             | "00006"             | "none"                                      | ""        | "none"                |
+      | ""                  | ""                  | "at least one source volume is required"    | ""        | "none"                |
+      | "00001,00001"       | "00004,00005"       | "is given more than once"                   | ""        | "none"                |
+      | "00001,00009"       | "00004,00005"       | "cannot be found on array"                  | ""        | "none"                |
+      | "00001,00002"       | "00004"             | "has 2 source volumes but 1 target volumes" | ""        | "none"                |
+      | "00001,00002"       | "00004,00005"       | "induced error"                             | ""        | "CreateSnapshotError" |
+      | "00001,00002"       | "00004,00005"       | "induced error"                             | ""        | "LinkSnapshotError"   |
+      | "00001,00002"       | "00004,00005"       | "ignored via a whitelist"                   | "ignored" | "none"                |
+
+  Scenario Outline: Link a consistent snapshot taken again on some of its volumes
+    Given a valid connection
+    And I have 6 volumes
+    When I call CreateConsistentSnapshot "dbsnap" of "00001,00002,00003"
+    And snapshot "dbsnap" on <volID> was taken again
+    And I call LinkConsistentSnapshot to "00004,00005,00006"
+    Then the error message contains "none"
+    And the consistent snapshot of "00001,00002,00003" is linked to "00004,00005,00006" if no error
+
+    Examples:
+      | volID   |
+      | "00001" |
+      | "00003" |
+
+  Scenario: Link a consistent snapshot whose generation was deleted
+    Given a valid connection
+    And I have 6 volumes
+    When I call CreateConsistentSnapshot "dbsnap" of "00001,00002,00003"
+    And snapshot "dbsnap" on "00002" was created 1 hours ago
+    And I call LinkConsistentSnapshot to "00004,00005,00006"
+    Then the error message contains "no longer exists on volume (00002)"
+
+  Scenario Outline: Restore a snapshot to its source volumes
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 3 volumes
+    And I call CreateSnapshot with "00001,00002" and snapshot "snapshot1" on it
+    And I induce error <induced>
+    When I call RestoreSnapshot with <volIDs> and snapshot <snapID>
+    Then the error message contains <errormsg>
+    And the snapshot <snapID> of <volIDs> is established and not restored if no error
+
+    Examples:
+      | volIDs        | snapID      | errormsg                       | whitelist | induced                  |
+      | "00001,00002" | "snapshot1" | "none"                         |    ""     | "none"                   |
+      | "00001"       | "snapshot1" | "none"                         |    ""     | "none"                   |
+      | "00001,00003" | "snapshot1" | "snopshot cannot be found"     |    ""     | "none"                   |
+      | "00001,00004" | "snapshot1" | "devices not available"        |    ""     | "none"                   |
+      | "00001"       | "snapshot2" | "snopshot cannot be found"     |    ""     | "none"                   |
+      | "00001,00002" | "snapshot1" | "induced error"                |    ""     | "RestoreSnapshotError"   |
+      | "00001,00002" | "snapshot1" | "was not restored"             |    ""     | "RestoreInProgressError" |
+      | "00001,00002" | "snapshot1" | "Job status not successful"    |    ""     | "JobFailedError"         |
+      | "00001,00002" | "snapshot1" | "ignored via a whitelist"      | "ignored" | "none"                   |
+
+  Scenario Outline: Create a snapshot with a time to live
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 3 volumes
+    And I induce error <induced>
+    When I call CreateSnapshotWithOptions with "00001,00002" and snapshot "snapshot1" with ttl <ttl> in hours <hours> and secure <secure>
+    And I call GetSnapshotRetention with <volID> and snapshot "snapshot1"
+    Then the error message contains <errormsg>
+    And the snapshot retention is secure <secure> with a time to live of <ttlHours> hours if no error
+
+    Examples:
+      | ttl | hours   | secure  | volID   | ttlHours | errormsg                         | whitelist | induced                  |
+      | 0   | "false" | "false" | "00001" | 0        | "none"                           |    ""     | "none"                   |
+      | 2   | "false" | "false" | "00001" | 48       | "none"                           |    ""     | "none"                   |
+      | 6   | "true"  | "false" | "00002" | 6        | "none"                           |    ""     | "none"                   |
+      | 1   | "false" | "true"  | "00001" | 24       | "none"                           |    ""     | "none"                   |
+      | 12  | "true"  | "true"  | "00002" | 12       | "none"                           |    ""     | "none"                   |
+      | 0   | "false" | "true"  | "00001" | 0        | "a time to live is required"     |    ""     | "none"                   |
+      | 1   | "false" | "false" | "00003" | 24       | "cannot be found"                |    ""     | "none"                   |
+      | 1   | "false" | "false" | "00001" | 24       | "induced error"                  |    ""     | "CreateSnapshotError"    |
+      | 1   | "false" | "false" | "00001" | 24       | "ignored via a whitelist"        | "ignored" | "none"                   |
+
+  Scenario Outline: Set the time to live of a snapshot
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 3 volumes
+    And I call CreateSnapshotWithOptions with "00001,00002" and snapshot "snapshot1" with ttl 1 in hours "false" and secure <secure>
+    And I induce error <induced>
+    When I call SetSnapshotTTL "snapshot1" on <volIDs> with ttl <ttl> in hours <hours> and secure <newSecure>
+    And I call GetSnapshotRetention with "00001" and snapshot "snapshot1"
+    Then the error message contains <errormsg>
+    And the snapshot retention is secure <newSecure> with a time to live of <ttlHours> hours if no error
+
+    Examples:
+      | secure  | volIDs        | ttl | hours   | newSecure | ttlHours | errormsg                             | whitelist | induced               |
+      | "false" | "00001,00002" | 12  | "true"  | "false"   | 12       | "none"                               |    ""     | "none"                |
+      | "false" | "00001,00002" | 0   | "false" | "false"   | 0        | "none"                               |    ""     | "none"                |
+      | "false" | "00001"       | 2   | "false" | "true"    | 48       | "none"                               |    ""     | "none"                |
+      | "true"  | "00001,00002" | 3   | "false" | "true"    | 72       | "none"                               |    ""     | "none"                |
+      | "true"  | "00001,00002" | 12  | "true"  | "true"    | 12       | "can only be extended"               |    ""     | "none"                |
+      | "true"  | "00001,00002" | 2   | "false" | "false"   | 48       | "secure snapshot cannot be unset"    |    ""     | "none"                |
+      | "false" | "00001,00002" | 0   | "false" | "true"    | 0        | "a valid time to live is required"   |    ""     | "none"                |
+      | "false" | "00001,00003" | 1   | "false" | "false"   | 24       | "Snapshot cannot be found"           |    ""     | "none"                |
+      | "false" | "00001,00002" | 1   | "false" | "false"   | 24       | "induced error"                      |    ""     | "SetSnapshotTTLError" |
+      | "false" | "00001,00002" | 1   | "false" | "false"   | 24       | "Job status not successful"          |    ""     | "JobFailedError"      |
+      | "false" | "00001,00002" | 1   | "false" | "false"   | 24       | "ignored via a whitelist"            | "ignored" | "none"                |
+
+  Scenario Outline: Delete a secure snapshot
+    Given a valid connection
+    And I have 2 volumes
+    And I call CreateSnapshotWithOptions with "00001" and snapshot "snapshot1" with ttl 1 in hours "true" and secure <secure>
+    And I induce error <induced>
+    When I call DeleteSnapshot with "00001", snapshot "snapshot1" and 0  on it
+    Then the error message contains <errormsg>
+
+    Examples:
+      | secure  | errormsg                     | induced           |
+      | "false" | "none"                       | "none"            |
+      | "true"  | "secure and has not expired" | "none"            |
+      | "true"  | "none"                       | "SnapshotExpired" |
+
+  Scenario Outline: Clone a volume
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 1 volumes
+    And I induce error <induced>
+    And the copy of the links completes after <polls> polls
+    When I call CloneVolume <srcVolID> to "CSI-Test-SG-1" named "clone1" with copy <copy>
+    Then the error message contains <errormsg>
+    And the clone "clone1" of <srcVolID> is linked <linked> if no error
+    And the clone "clone1" of <srcVolID> is cleaned up if error
+
+    Examples:
+      | srcVolID | copy    | polls | linked  | errormsg                      | whitelist | induced                       |
+      | "00001"  | "false" | 0     | "true"  | "none"                        |    ""     | "none"                        |
+      | "00001"  | "true"  | 0     | "false" | "none"                        |    ""     | "none"                        |
+      | "00001"  | "true"  | 3     | "false" | "none"                        |    ""     | "none"                        |
+      | "00001"  | "true"  | 5     | "false" | "did not reach state Copied"  |    ""     | "none"                        |
+      | "00009"  | "false" | 0     | "true"  | "Volume cannot be found"      |    ""     | "none"                        |
+      | "00001"  | "false" | 0     | "true"  | "induced error"               |    ""     | "CreateSnapshotError"         |
+      | "00001"  | "false" | 0     | "true"  | "induced error"               |    ""     | "LinkSnapshotError"           |
+      | "00001"  | "false" | 0     | "true"  | "did not reach state Linked"  |    ""     | "TargetNotDefinedError"       |
+      | "00001"  | "true"  | 0     | "false" | "did not reach state Copied"  |    ""     | "SnapshotCopyInProgressError" |
+      | "00001"  | "false" | 0     | "true"  | "ignored via a whitelist"     | "ignored" | "none"                        |
+
+  Scenario: Clone a volume whose snapshot cannot be deleted after the copy
+    Given a valid connection
+    And I have 1 volumes
+    And I induce error "DeleteSnapshotError"
+    When I call CloneVolume "00001" to "CSI-Test-SG-1" named "clone1" with copy "true"
+    Then the error message contains "none"
+    And the clone "clone1" of "00001" is unlinked from its leftover snapshot
+    When I call CollectSnapshots with prefix "clone-" expired "false" max age 0 hours orphaned "true" dry run "true" and max deletions 0
+    Then the error message contains "none"
+    And the snapshot collection found "00001:clone-clone1" and deleted "" if no error
+
+  Scenario Outline: Collect expired, old and orphaned snapshots
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 3 volumes
+    And I call CreateSnapshotWithOptions with "00001" and snapshot "gc-expired" with ttl 1 in hours "true" and secure "false"
+    And I call CreateSnapshot with "00002" and snapshot "gc-old" on it
+    And I call CreateSnapshot with "00003" and snapshot "keep-old" on it
+    And snapshot "gc-expired" on "00001" was created 2 hours ago
+    And snapshot "gc-old" on "00002" was created 48 hours ago
+    And snapshot "keep-old" on "00003" was created 48 hours ago
+    And I induce error <induced>
+    When I call CollectSnapshots with prefix <prefix> expired <expired> max age <maxAge> hours orphaned <orphaned> dry run <dryRun> and max deletions <max>
+    Then the error message contains <errormsg>
+    And the snapshot collection found <found> and deleted <deleted> if no error
+
+    Examples:
+      | prefix  | expired | maxAge | orphaned | dryRun  | max | found                           | deleted                         | errormsg                       | whitelist | induced             |
+      | ""      | "true"  | 0      | "false"  | "false" | 0   | "00001:gc-expired"              | "00001:gc-expired"              | "none"                         | ""        | "none"              |
+      | ""      | "false" | 24     | "false"  | "false" | 0   | "00002:gc-old,00003:keep-old"   | "00002:gc-old,00003:keep-old"   | "none"                         | ""        | "none"              |
+      | "gc-"   | "true"  | 24     | "false"  | "false" | 0   | "00001:gc-expired,00002:gc-old" | "00001:gc-expired,00002:gc-old" | "none"                         | ""        | "none"              |
+      | "gc-"   | "true"  | 24     | "false"  | "true"  | 0   | "00001:gc-expired,00002:gc-old" | ""                              | "none"                         | ""        | "none"              |
+      | "gc-"   | "true"  | 24     | "false"  | "false" | 1   | "00001:gc-expired,00002:gc-old" | "00001:gc-expired"              | "none"                         | ""        | "none"              |
+      | "keep-" | "false" | 0      | "true"   | "false" | 0   | "00003:keep-old"                | "00003:keep-old"                | "none"                         | ""        | "none"              |
+      | "gc-"   | "false" | 72     | "false"  | "false" | 0   | ""                              | ""                              | "none"                         | ""        | "none"              |
+      | ""      | "false" | 0      | "false"  | "false" | 0   | ""                              | ""                              | "policy selecting"             | ""        | "none"              |
+      | ""      | "false" | 0      | "true"   | "false" | 0   | ""                              | ""                              | "name prefix or a minimum age" | ""        | "none"              |
+      | ""      | "true"  | 0      | "false"  | "false" | 0   | ""                              | ""                              | "induced error"                | ""        | "GetSymVolumeError" |
+      | ""      | "true"  | 0      | "false"  | "false" | 0   | ""                              | ""                              | "induced error"                | ""        | "GetVolSnapsError"  |
+      | ""      | "true"  | 0      | "false"  | "false" | 0   | ""                              | ""                              | "ignored via a whitelist"      | "ignored" | "none"              |
+
+  Scenario Outline: Collect orphaned snapshots
+    Given a valid connection with API version <version>
+    And I have a whitelist of <whitelist>
+    And I have 4 volumes
+    And I have a snapshot policy "gc-policy"
+    And I call CreateSnapshot with "00001" and snapshot "gc-linked" on it
+    And I call CreateSnapshot with "00002" and snapshot "gc-orphan" on it
+    And I call CreateSnapshot with "00003" and snapshot "gc-policy" on it
+    And I call LinkSnapshot "gc-linked" to "00004" with copy "false" and relink "false"
+    And I induce error <induced>
+    When I call CollectSnapshots with prefix "gc-" expired "false" max age 0 hours orphaned "true" dry run "true" and max deletions 0
+    Then the error message contains <errormsg>
+    And the snapshot collection found <found> and deleted "" if no error
+
+    Examples:
+    | version | found                             | errormsg                  | whitelist | induced                  |
+    | "92"    | "00002:gc-orphan"                 | "none"                    | ""        | "none"                   |
+    | "91"    | "00002:gc-orphan,00003:gc-policy" | "none"                    | ""        | "none"                   |
+    | "92"    | ""                                | "induced error"           | ""        | "GetSnapshotPolicyError" |
+    | "92"    | ""                                | "ignored via a whitelist" | "ignored" | "none"                   |
+
+  Scenario Outline: Collect the snapshots of deleted volumes
+    Given a valid connection
+    And I have 3 volumes
+    And I call CreateSnapshot with "00001" and snapshot "snap-kept" on it
+    And I call CreateSnapshot with "00002" and snapshot "snap-deleted" on it
+    And volume "00002" is renamed "_DEL-vol2"
+    And I have a whitelist of <whitelist>
+    And I induce error <induced>
+    When I call CollectSnapshots of the volumes renamed with prefix <prefix>
+    Then the error message contains <errormsg>
+    And the snapshot collection found <found> and deleted <found> if no error
+
+    Examples:
+    | prefix  | found                                     | errormsg                  | whitelist | induced          |
+    | "_DEL-" | "00002:DEL-snapshot-2,00002:snap-deleted" | "none"                    | ""        | "none"           |
+    | "_GONE" | ""                                        | "none"                    | ""        | "none"           |
+    | "_DEL-" | ""                                        | "induced error"           | ""        | "GetVolumeError" |
+    | "_DEL-" | ""                                        | "ignored via a whitelist" | "ignored" | "none"           |
+
+  Scenario Outline: Collect linked and secure snapshots
+    Given a valid connection
+    And I have 2 volumes
+    And I call CreateSnapshotWithOptions with "00001" and snapshot "gc-snap" with ttl 72 in hours "true" and secure <secure>
+    And snapshot "gc-snap" on "00001" was created 48 hours ago
+    And I call LinkSnapshot "gc-snap" to "00002" with copy <copy> and relink "false"
+    When I call CollectSnapshots with prefix "gc-" expired "false" max age 24 hours orphaned "false" dry run "false" and max deletions 0
+    Then the error message contains "none"
+    And the snapshot collection found "00001:gc-snap" and deleted <deleted> if no error
+    And the snapshot collection skipped <skipped> if no error
+
+    Examples:
+      | copy    | secure  | deleted         | skipped   |
+      | "true"  | "false" | "00001:gc-snap" | ""        |
+      | "false" | "false" | ""              | "Linked"  |
+      | "true"  | "true"  | ""              | "Secured" |
+
+  Scenario Outline: Get the snapshot generations of a volume
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 2 volumes
+    And I call CreateSnapshot with "00001" and snapshot "snapshot1" on it
+    And I call CreateSnapshotWithOptions with "00001" and snapshot "snapshot2" with ttl 3 in hours "false" and secure "false"
+    And snapshot "snapshot1" on "00001" was created 48 hours ago
+    And snapshot "DEL-snapshot-1" on "00001" was created 1 hours ago
+    And I call LinkSnapshot "snapshot1" to "00002" with copy "false" and relink "false"
+    And I induce error <induced>
+    When I call GetVolumeSnapshotGenerations with <volID>
+    Then the error message contains <errormsg>
+    And I get 3 snapshot generations with latest "snapshot2" and "snapshot1" older than 24 hours linked to "00002" if no error
+    And the snapshot generations of <volID> match their private volume headers if no error
+
+    Examples:
+      | volID   | errormsg                  | whitelist | induced            |
+      | "00001" | "none"                    |    ""     | "none"             |
+      | "00009" | "cannot be found"         |    ""     | "none"             |
+      | "00001" | "induced error"           |    ""     | "GetVolSnapsError" |
+      | "00001" | "ignored via a whitelist" | "ignored" | "none"             |
+
+  Scenario Outline: Create a snapshot of a storage group
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 3 volumes
+    And I induce error <induced>
+    When I call CreateStorageGroupSnapshot <snapID> with ttl <ttl> in hours <hours> and secure <secure>
+    Then the error message contains <errormsg>
+    And I get a valid storage group snapshot <snapID> with 3 source volumes if no error
+    And the storage group snapshot has a time to live of <ttlHours> hours and is secured <secure> if no error
+
+    Examples:
+    | snapID    | ttl | hours   | secure  | ttlHours | errormsg                     | whitelist | induced                 |
+    | "sgsnap"  | 0   | "false" | "false" | 0        | "none"                       | ""        | "none"                  |
+    | "sgsnap"  | 2   | "false" | "false" | 48       | "none"                       | ""        | "none"                  |
+    | "sgsnap"  | 2   | "true"  | "false" | 2        | "none"                       | ""        | "none"                  |
+    | "sgsnap"  | 3   | "true"  | "true"  | 3        | "none"                       | ""        | "none"                  |
+    | "sgsnap"  | 0   | "false" | "true"  | 0        | "a time to live is required" | ""        | "none"                  |
+    | "sg:snap" | 0   | "false" | "false" | 0        | "invalid snapshot name"      | ""        | "none"                  |
+    | "sgsnap"  | 0   | "false" | "false" | 0        | "induced error"              | ""        | "CreateSGSnapshotError" |
+    | "sgsnap"  | 0   | "false" | "false" | 0        | "induced error"              | ""        | "GetSGSnapshotError"    |
+    | "sgsnap"  | 0   | "false" | "false" | 0        | "Job status not successful"  | ""        | "JobFailedError"        |
+    | "sgsnap"  | 0   | "false" | "false" | 0        | "ignored via a whitelist"    | "ignored" | "none"                  |
+
+  Scenario Outline: List the snapshots of a storage group and their generations
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 3 volumes
+    And I have a storage group snapshot "sgsnap1" taken 3 times
+    And I have a storage group snapshot "sgsnap2" taken 1 times
+    And I induce error <induced>
+    When I call ListStorageGroupSnapshots
+    And I call GetStorageGroupSnapshotGenerations <snapID>
+    Then the error message contains <errormsg>
+    And I get storage group snapshots "sgsnap1,sgsnap2" if no error
+    And I get <count> storage group snapshot generations if no error
+
+    Examples:
+    | snapID    | count | errormsg                  | whitelist | induced              |
+    | "sgsnap1" | 3     | "none"                    |   ""      | "none"               |
+    | "sgsnap2" | 1     | "none"                    |   ""      | "none"               |
+    | "sgsnap3" | 0     | "cannot be found"         |   ""      | "none"               |
+    | "sgsnap1" | 3     | "induced error"           |   ""      | "GetSGSnapshotError" |
+    | "sgsnap1" | 3     | "ignored via a whitelist" | "ignored" | "none"               |
+
+  Scenario Outline: Perform actions on a storage group snapshot
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 3 volumes
+    And I have a link storage group with <linkvols> volumes
+    And I have a storage group snapshot "sgsnap" taken 1 times
+    And I induce error <induced>
+    When I call <actions> on storage group snapshot "sgsnap" generation 0
+    Then the error message contains <errormsg>
+    And the storage group snapshot <snapID> is linked <linked> and restored <restored> if no error
+
+    Examples:
+    | actions       | linkvols | snapID           | linked  | restored | errormsg                          | whitelist | induced                 |
+    | "Link"        | 3        | "sgsnap"         | "true"  | "false"  | "none"                            |   ""      | "none"                  |
+    | "Link,Unlink" | 3        | "sgsnap"         | "false" | "false"  | "none"                            |   ""      | "none"                  |
+    | "Restore"     | 3        | "sgsnap"         | "false" | "true"   | "none"                            |   ""      | "none"                  |
+    | "Rename"      | 3        | "sgsnap-renamed" | "false" | "false"  | "none"                            |   ""      | "none"                  |
+    | "Link,Link"   | 3        | "sgsnap"         | "true"  | "false"  | "devices already in desired state" |   ""      | "none"                  |
+    | "Unlink"      | 3        | "sgsnap"         | "false" | "false"  | "devices already in desired state" |   ""      | "none"                  |
+    | "Link"        | 2        | "sgsnap"         | "false" | "false"  | "number of source and link"       |   ""      | "none"                  |
+    | "Link"        | 3        | "sgsnap"         | "false" | "false"  | "induced error"                   |   ""      | "ModifySGSnapshotError" |
+    | "Link"        | 3        | "sgsnap"         | "false" | "false"  | "Job status not successful"       |   ""      | "JobFailedError"        |
+    | "Link"        | 3        | "sgsnap"         | "false" | "false"  | "ignored via a whitelist"         | "ignored" | "none"                  |
+
+  Scenario Outline: Delete a generation of a storage group snapshot
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 3 volumes
+    And I have a link storage group with 3 volumes
+    And I have a storage group snapshot "sgsnap" taken 2 times
+    And I call <actions> on storage group snapshot "sgsnap" generation 0
+    And I induce error <induced>
+    When I call "Delete" on storage group snapshot "sgsnap" generation 0
+    Then the error message contains <errormsg>
+    And the storage group snapshot "sgsnap" has 1 generations if no error
+
+    Examples:
+    | actions       | errormsg                          | whitelist | induced                 |
+    | ""            | "none"                            |   ""      | "none"                  |
+    | "Link,Unlink" | "none"                            |   ""      | "none"                  |
+    | "Link"        | "snapshot has a link"             |   ""      | "none"                  |
+    | ""            | "induced error"                   |   ""      | "DeleteSGSnapshotError" |
+    | ""            | "Job status not successful"       |   ""      | "JobFailedError"        |
+    | ""            | "ignored via a whitelist"         | "ignored" | "none"                  |
+
+  Scenario Outline: Create a snapshot policy
+    Given a valid connection with API version <version>
+    And I have a whitelist of <whitelist>
+    And I have a snapshot policy "existing"
+    And I induce error <induced>
+    When I call CreateSnapshotPolicy <policy> every <interval> keeping <count> snapshots
+    Then the error message contains <errormsg>
+    And I get a valid snapshot policy <policy> taking a snapshot every <minutes> minutes if no error
+
+    Examples:
+    | version | policy     | interval     | count | minutes | errormsg                             | whitelist | induced                     |
+    | "92"    | "hourly"   | "1 Hour"     | 24    | 60      | "none"                               |   ""      | "none"                      |
+    | "92"    | "weekly"   | "7 Days"     | 4     | 10080   | "none"                               |   ""      | "none"                      |
+    | "92"    | "hourly"   | "5 Minutes"  | 24    | 0       | "not a supported snapshot policy"    |   ""      | "none"                      |
+    | "92"    | "hourly"   | "1 Hour"     | 0     | 0       | "must be between 1 and 1024"         |   ""      | "none"                      |
+    | "92"    | "existing" | "1 Hour"     | 24    | 0       | "already exists"                     |   ""      | "none"                      |
+    | "92"    | "hourly"   | "1 Hour"     | 24    | 0       | "induced error"                      |   ""      | "CreateSnapshotPolicyError" |
+    | "90"    | "hourly"   | "1 Hour"     | 24    | 0       | "require API version 92"             |   ""      | "none"                      |
+    | "92"    | "hourly"   | "1 Hour"     | 24    | 0       | "ignored via a whitelist"            | "ignored" | "none"                      |
+
+  Scenario Outline: List and get the snapshot policies
+    Given a valid connection with API version <version>
+    And I have a whitelist of <whitelist>
+    And I have a snapshot policy "daily"
+    And I have a snapshot policy "hourly"
+    And I induce error <induced>
+    When I call GetSnapshotPolicyList
+    And I call GetSnapshotPolicy <policy>
+    Then the error message contains <errormsg>
+    And I get snapshot policies "daily,hourly" if no error
+    And I get a valid snapshot policy <policy> taking a snapshot every 60 minutes if no error
+
+    Examples:
+    | version | policy    | errormsg                  | whitelist | induced                  |
+    | "92"    | "hourly"  | "none"                    |   ""      | "none"                   |
+    | "92"    | "monthly" | "cannot be found"         |   ""      | "none"                   |
+    | "92"    | "hourly"  | "induced error"           |   ""      | "GetSnapshotPolicyError" |
+    | "91"    | "hourly"  | "require API version 92"  |   ""      | "none"                   |
+    | "92"    | "hourly"  | "ignored via a whitelist" | "ignored" | "none"                   |
+
+  Scenario Outline: Modify, suspend and resume a snapshot policy
+    Given a valid connection with API version <version>
+    And I have a whitelist of <whitelist>
+    And I have a snapshot policy "hourly"
+    And I induce error <induced>
+    When I call <actions> on snapshot policy "hourly"
+    Then the error message contains <errormsg>
+    And the snapshot policy <policy> keeps <count> snapshots and is suspended <suspended> if no error
+
+    Examples:
+    | version | actions           | policy           | count | suspended | errormsg                  | whitelist | induced                     |
+    | "92"    | "Modify"          | "hourly"         | 7     | "false"   | "none"                    |   ""      | "none"                      |
+    | "92"    | "Rename"          | "hourly-renamed" | 24    | "false"   | "none"                    |   ""      | "none"                      |
+    | "92"    | "Suspend"         | "hourly"         | 24    | "true"    | "none"                    |   ""      | "none"                      |
+    | "92"    | "Suspend,Resume"  | "hourly"         | 24    | "false"   | "none"                    |   ""      | "none"                      |
+    | "92"    | "Suspend,Suspend" | "hourly"         | 24    | "true"    | "already suspended"       |   ""      | "none"                      |
+    | "92"    | "Resume"          | "hourly"         | 24    | "false"   | "is not suspended"        |   ""      | "none"                      |
+    | "92"    | "Suspend"         | "hourly"         | 24    | "false"   | "induced error"           |   ""      | "ModifySnapshotPolicyError" |
+    | "90"    | "Suspend"         | "hourly"         | 24    | "false"   | "require API version 92"  |   ""      | "none"                      |
+    | "92"    | "Suspend"         | "hourly"         | 24    | "false"   | "ignored via a whitelist" | "ignored" | "none"                      |
+
+  Scenario Outline: Associate storage groups to a snapshot policy
+    Given a valid connection with API version <version>
+    And I have a whitelist of <whitelist>
+    And I have 3 volumes
+    And I have a snapshot policy "hourly"
+    And I induce error <induced>
+    When I call <actions> on snapshot policy "hourly"
+    Then the error message contains <errormsg>
+    And the snapshot policy "hourly" has <count> storage groups if no error
+
+    Examples:
+    | version | actions                  | count | errormsg                        | whitelist | induced                     |
+    | "92"    | "Associate"              | 1     | "none"                          |   ""      | "none"                      |
+    | "92"    | "Associate,Disassociate" | 0     | "none"                          |   ""      | "none"                      |
+    | "92"    | "Associate,Associate"    | 1     | "is already associated"         |   ""      | "none"                      |
+    | "92"    | "Disassociate"           | 0     | "is not associated"             |   ""      | "none"                      |
+    | "92"    | "AssociateUnknown"       | 0     | "Storage Group cannot be found" |   ""      | "none"                      |
+    | "92"    | "Associate"              | 0     | "induced error"                 |   ""      | "ModifySnapshotPolicyError" |
+    | "90"    | "Associate"              | 0     | "require API version 92"        |   ""      | "none"                      |
+    | "92"    | "Associate"              | 0     | "ignored via a whitelist"       | "ignored" | "none"                      |
+
+  Scenario Outline: Delete a snapshot policy
+    Given a valid connection with API version <version>
+    And I have a whitelist of <whitelist>
+    And I have 3 volumes
+    And I have a snapshot policy "hourly"
+    And I call <actions> on snapshot policy "hourly"
+    And I induce error <induced>
+    When I call DeleteSnapshotPolicy <policy>
+    Then the error message contains <errormsg>
+    And the snapshot policy <policy> is deleted if no error
+
+    Examples:
+    | version | actions     | policy    | errormsg                              | whitelist | induced                     |
+    | "92"    | ""          | "hourly"  | "none"                                |   ""      | "none"                      |
+    | "92"    | "Associate" | "hourly"  | "is associated with storage groups"   |   ""      | "none"                      |
+    | "92"    | ""          | "monthly" | "cannot be found"                     |   ""      | "none"                      |
+    | "92"    | ""          | "hourly"  | "induced error"                       |   ""      | "DeleteSnapshotPolicyError" |
+    | "90"    | ""          | "hourly"  | "require API version 92"              |   ""      | "none"                      |
+    | "92"    | ""          | "hourly"  | "ignored via a whitelist"             | "ignored" | "none"                      |
+
+  Scenario Outline: Get the snapshot compliance of a storage group
+    Given a valid connection with API version <version>
+    And I have a whitelist of <whitelist>
+    And I have 3 volumes
+    And I have a snapshot policy "hourly"
+    And I have a storage group snapshot "hourly" taken <taken> times
+    And I call <actions> on snapshot policy "hourly"
+    And I induce error <induced>
+    When I call GetStorageGroupSnapshotCompliance
+    Then the error message contains <errormsg>
+    And the snapshot compliance is <compliance> if no error
+
+    Examples:
+    | version | actions     | taken | compliance | errormsg                  | whitelist | induced                  |
+    | "92"    | "Associate" | 3     | "GREEN"    | "none"                    |   ""      | "none"                   |
+    | "92"    | "Associate" | 2     | "YELLOW"   | "none"                    |   ""      | "none"                   |
+    | "92"    | "Associate" | 1     | "RED"      | "none"                    |   ""      | "none"                   |
+    | "92"    | "Associate" | 0     | "RED"      | "none"                    |   ""      | "none"                   |
+    | "92"    | ""          | 3     | "NONE"     | "none"                    |   ""      | "none"                   |
+    | "92"    | "Associate" | 3     | "GREEN"    | "induced error"           |   ""      | "GetSnapshotPolicyError" |
+    | "90"    | "Associate" | 3     | "GREEN"    | "require API version 92"  |   ""      | "none"                   |
+    | "92"    | "Associate" | 3     | "GREEN"    | "ignored via a whitelist" | "ignored" | "none"                   |
+
+  Scenario Outline: Build the replication graph of volumes
+    Given a valid connection
+    And I have 6 volumes
+    And volume "00001" has a snapshot "snap1" linked to "00002"
+    And volume "00002" has a snapshot "snap2" linked to "00003"
+    And volume "00003" is cloned to "00004"
+    And volume "00004" is mirrored to "00006"
+    And I have a whitelist of <whitelist>
+    And I induce error <induced>
+    When I call GetReplicationGraph of <volIDs>
+    Then the error message contains <errormsg>
+    And the replication graph has volumes <graph> if no error
+
+    Examples:
+      | volIDs        | graph                                 | errormsg                  | whitelist | induced                  |
+      | "00001"       | "00001,00002,00003,00004,00006"       | "none"                    | ""        | "none"                   |
+      | "00006"       | "00001,00002,00003,00004,00006"       | "none"                    | ""        | "none"                   |
+      | "00005"       | "00005"                               | "none"                    | ""        | "none"                   |
+      | "00003,00005" | "00001,00002,00003,00004,00005,00006" | "none"                    | ""        | "none"                   |
+      | ""            | ""                                    | "at least one volume"     | ""        | "none"                   |
+      | "00009"       | ""                                    | "cannot be found"         | ""        | "none"                   |
+      | "00001"       | ""                                    | "induced error"           | ""        | "GetPrivVolumeByIDError" |
+      | "00001"       | ""                                    | "ignored via a whitelist" | "ignored" | "none"                   |
+
+  Scenario Outline: Find the dependencies of volumes and snapshots in the replication graph
+    Given a valid connection
+    And I have 6 volumes
+    And volume "00001" has a snapshot "snap1" linked to "00002"
+    And volume "00002" has a snapshot "snap2" linked to "00003"
+    And volume "00003" is cloned to "00004"
+    And volume "00004" is mirrored to "00006"
+    When I call GetReplicationGraph of "00001,00005"
+    Then the error message contains "none"
+    And volume <volID> can be deleted <deletable> if no error
+    And the snapshot <snapID> of <volID> has dependents <dependents> if no error
+
+    Examples:
+      | volID   | deletable | snapID  | dependents                |
+      | "00001" | "false"   | "snap1" | "00002,00003,00004,00006" |
+      | "00002" | "false"   | "snap2" | "00003,00004,00006"       |
+      | "00002" | "false"   | "snap1" | ""                        |
+      | "00004" | "false"   | "snap1" | ""                        |
+      | "00006" | "false"   | "snap1" | ""                        |
+      | "00005" | "true"    | "snap1" | ""                        |
+      | "00009" | "false"   | "snap1" | ""                        |
+
   Scenario Outline: Testing GetPrivVolumeByID
     Given a valid connection
     And I have 4 volumes
@@ -219,4 +798,321 @@ Feature: PMAX replication test
     | "00004" | "none"                    |   ""      | "none"                   |
     | "00007" | "cannot be found"         |   ""      | "none"                   |
     | "00001" | "ignored via a whitelist" | "ignored" | "none"                   |
-    | "00001" | "induced error"           |   ""      | "GetPrivVolumeByIDError" |
\ No newline at end of file
+    | "00001" | "induced error"           |   ""      | "GetPrivVolumeByIDError" |
+
+  Scenario Outline: List the RDF groups
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I induce error <induced>
+    When I call GetRDFGroupList
+    Then the error message contains <errormsg>
+    And I get <count> RDF groups if no error
+
+    Examples:
+    | count | errormsg                  | whitelist | induced            |
+    | 3     | "none"                    |   ""      | "none"             |
+    | 0     | "induced error"           |   ""      | "GetRDFGroupError" |
+    | 0     | "ignored via a whitelist" | "ignored" | "none"             |
+
+  Scenario Outline: Get an RDF group
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I induce error <induced>
+    And RDF group <rdfg> has <online> online local ports
+    When I call GetRDFGroup <rdfg>
+    Then the error message contains <errormsg>
+    And I get a valid RDF group with mode <mode> and link state <state> if no error
+
+    Examples:
+    | rdfg | online | mode           | state      | errormsg                  | whitelist | induced            |
+    | 10   | 2      | "Synchronous"  | "Online"   | "none"                    |   ""      | "none"             |
+    | 11   | 2      | "Asynchronous" | "Online"   | "none"                    |   ""      | "none"             |
+    | 12   | 2      | "Active"       | "Online"   | "none"                    |   ""      | "none"             |
+    | 10   | 1      | "Synchronous"  | "Degraded" | "none"                    |   ""      | "none"             |
+    | 10   | 0      | "Synchronous"  | "Offline"  | "none"                    |   ""      | "none"             |
+    | 13   | 2      | "none"         | "none"     | "cannot be found"         |   ""      | "none"             |
+    | 10   | 2      | "none"         | "none"     | "induced error"           |   ""      | "GetRDFGroupError" |
+    | 10   | 2      | "none"         | "none"     | "ignored via a whitelist" | "ignored" | "none"             |
+
+  Scenario Outline: List the volumes of an RDF group
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have RDF device pairs "00001,00002" in RDF group 10
+    And I induce error <induced>
+    When I call GetRDFGroupVolumes <rdfg>
+    Then the error message contains <errormsg>
+    And I get <count> RDF group volumes if no error
+
+    Examples:
+    | rdfg | count | errormsg                  | whitelist | induced            |
+    | 10   | 2     | "none"                    |   ""      | "none"             |
+    | 11   | 0     | "none"                    |   ""      | "none"             |
+    | 13   | 0     | "cannot be found"         |   ""      | "none"             |
+    | 10   | 0     | "induced error"           |   ""      | "GetRDFGroupError" |
+    | 10   | 0     | "ignored via a whitelist" | "ignored" | "none"             |
+
+  Scenario Outline: Get the SRDF relationship of a volume
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have RDF device pairs "00001,00002" in RDF group 10
+    And I induce error <induced>
+    When I call GetRDFDevicePair 10 with volume <volID>
+    Then the error message contains <errormsg>
+    And the remote volume is <remoteVolID> if no error
+
+    Examples:
+    | volID   | remoteVolID | errormsg                  | whitelist | induced            |
+    | "00001" | "00101"     | "none"                    |   ""      | "none"             |
+    | "00002" | "00102"     | "none"                    |   ""      | "none"             |
+    | "00003" | "none"      | "cannot be found"         |   ""      | "none"             |
+    | "00001" | "none"      | "induced error"           |   ""      | "GetRDFGroupError" |
+    | "00001" | "none"      | "ignored via a whitelist" | "ignored" | "none"             |
+
+  Scenario Outline: Protect a storage group with SRDF
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 3 volumes
+    And I induce error <induced>
+    When I call CreateSGReplica in RDF group <rdfg> with mode <mode> and establish <establish>
+    Then the error message contains <errormsg>
+    And the SRDF state of the storage group in RDF group <rdfg> is <state> if no error
+    And the volumes of the storage group in RDF group <rdfg> are "R1" if no error
+    And the storage group is protected in RDF groups <rdfgs> if no error
+
+    Examples:
+    | rdfg | mode           | establish | state          | rdfgs | errormsg                                  | whitelist | induced                |
+    | 10   | "Synchronous"  | "true"    | "Synchronized" | "10"  | "none"                                    |   ""      | "none"                 |
+    | 11   | "Asynchronous" | "true"    | "Consistent"   | "11"  | "none"                                    |   ""      | "none"                 |
+    | 12   | "Active"       | "true"    | "ActiveBias"   | "12"  | "none"                                    |   ""      | "none"                 |
+    | 10   | "Synchronous"  | "false"   | "Suspended"    | "10"  | "none"                                    |   ""      | "none"                 |
+    | 10   | "Asynchronous" | "true"    | "none"         | ""    | "does not support the replication mode"   |   ""      | "none"                 |
+    | 10   | "Adaptive"     | "true"    | "none"         | ""    | "not a supported SRDF mode"               |   ""      | "none"                 |
+    | 13   | "Synchronous"  | "true"    | "none"         | ""    | "cannot be found"                         |   ""      | "none"                 |
+    | 10   | "Synchronous"  | "true"    | "none"         | ""    | "induced error"                           |   ""      | "CreateSGReplicaError" |
+    | 10   | "Synchronous"  | "true"    | "none"         | ""    | "Job status not successful"               |   ""      | "JobFailedError"       |
+    | 10   | "Synchronous"  | "true"    | "none"         | ""    | "ignored via a whitelist"                 | "ignored" | "none"                 |
+
+  Scenario Outline: Perform SRDF actions on a storage group
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 3 volumes
+    And I have an SRDF protected storage group in RDF group <rdfg>
+    And I induce error <induced>
+    When I call ExecuteSRDFAction <actions> on RDF group <rdfg>
+    Then the error message contains <errormsg>
+    And the SRDF state of the storage group in RDF group <rdfg> is <state> if no error
+    And the volumes of the storage group in RDF group <rdfg> are <type> if no error
+
+    Examples:
+    | rdfg | actions             | state          | type | errormsg                          | whitelist | induced                |
+    | 10   | "Split"             | "Split"        | "R1" | "none"                            |   ""      | "none"                 |
+    | 10   | "Suspend"           | "Suspended"    | "R1" | "none"                            |   ""      | "none"                 |
+    | 10   | "Suspend,Resume"    | "Synchronized" | "R1" | "none"                            |   ""      | "none"                 |
+    | 10   | "Split,Establish"   | "Synchronized" | "R1" | "none"                            |   ""      | "none"                 |
+    | 10   | "Failover"          | "Failed Over"  | "R1" | "none"                            |   ""      | "none"                 |
+    | 10   | "Failover,Failback" | "Synchronized" | "R1" | "none"                            |   ""      | "none"                 |
+    | 10   | "Failover,Swap"     | "Suspended"    | "R2" | "none"                            |   ""      | "none"                 |
+    | 11   | "Suspend,Resume"    | "Consistent"   | "R1" | "none"                            |   ""      | "none"                 |
+    | 12   | "Split,Establish"   | "ActiveBias"   | "R1" | "none"                            |   ""      | "none"                 |
+    | 10   | "Resume"            | "none"         | "R1" | "not valid in state Synchronized" |   ""      | "none"                 |
+    | 10   | "Split,Failback"    | "none"         | "R1" | "not valid in state Split"        |   ""      | "none"                 |
+    | 10   | "Restore"           | "none"         | "R1" | "not a supported SRDF action"     |   ""      | "none"                 |
+    | 10   | "Split"             | "none"         | "R1" | "induced error"                   |   ""      | "ModifySGReplicaError" |
+    | 10   | "Split"             | "none"         | "R1" | "Job status not successful"       |   ""      | "JobFailedError"       |
+    | 10   | "Split"             | "none"         | "R1" | "ignored via a whitelist"         | "ignored" | "none"                 |
+
+  Scenario Outline: Delete the SRDF pairs of a storage group
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 3 volumes
+    And I have an SRDF protected storage group in RDF group 10
+    And I call ExecuteSRDFAction <actions> on RDF group 10
+    And I induce error <induced>
+    When I call DeleteSGReplica on RDF group 10 with force <force>
+    Then the error message contains <errormsg>
+    And the storage group is protected in RDF groups "" if no error
+
+    Examples:
+    | actions   | force   | errormsg                              | whitelist | induced                |
+    | "Split"   | "false" | "none"                                |   ""      | "none"                 |
+    | "Suspend" | "false" | "none"                                |   ""      | "none"                 |
+    | ""        | "true"  | "none"                                |   ""      | "none"                 |
+    | ""        | "false" | "must be split or suspended"          |   ""      | "none"                 |
+    | "Split"   | "false" | "induced error"                       |   ""      | "DeleteSGReplicaError" |
+    | "Split"   | "false" | "Job status not successful"           |   ""      | "JobFailedError"       |
+    | "Split"   | "false" | "ignored via a whitelist"             | "ignored" | "none"                 |
+
+  Scenario Outline: Expand an SRDF protected volume
+    Given a valid connection
+    And I have 2 volumes
+    And I have an SRDF protected storage group in RDF group <rdfg>
+    And I induce error <induced>
+    When I call ExpandRDFVolume <id> in RDF group <group> to <gb> in GB
+    Then the error message contains <errormsg>
+    And I validate that volume <id> has has size <size> in GB
+    And the remote volume of <id> has size <gb> in GB if no error
+
+    Examples:
+    | rdfg | group | id      | size | gb | errormsg          | induced          |
+    | 10   | 10    | "00001" | "10" | 10 | "none"            | "none"           |
+    | 12   | 12    | "00002" | "20" | 20 | "none"            | "none"           |
+    | 10   | 10    | "00001" | "10" | 10 | "induced error"   | "GetVolumeError" |
+    | 10   | 12    | "00001" | "10" | 10 | "cannot be found" | "none"           |
+    | 12   | 0     | "00001" | "10" | 10 | "none"            | "none"           |
+
+  Scenario Outline: Expand an SRDF protected volume without its RDF group
+    Given a valid connection
+    And I have 2 volumes
+    And I have an SRDF protected storage group in RDF group 10
+    And I have RDF device pairs <pairs> in RDF group 12
+    When I expand volume "00001" to "30" in GB
+    Then the error message contains <errormsg>
+    And the remote volume of "00001" has size 30 in GB if no error
+
+    Examples:
+    | pairs   | errormsg                    |
+    | ""      | "none"                      |
+    | "00001" | "is paired in 2 RDF groups" |
+
+  Scenario Outline: Get the SRDF relationship of a volume
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 2 volumes
+    And I have an SRDF protected storage group in RDF group 10
+    And I have RDF device pairs <pairs> in RDF group 12
+    When I call GetVolumeRDFPair <id>
+    Then the error message contains <errormsg>
+
+    Examples:
+    | pairs   | id      | errormsg                    | whitelist |
+    | "00002" | "00001" | "none"                      | ""        |
+    | "00001" | "00001" | "is paired in 2 RDF groups" | ""        |
+    | "00002" | "00003" | "cannot be found"           | ""        |
+    | "00002" | "00001" | "ignored via a whitelist"   | "ignored" |
+
+  Scenario Outline: Expand an SRDF protected volume and get its remote volume
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 2 volumes
+    And I have an SRDF protected storage group in RDF group 10
+    And I induce error <induced>
+    When I call ExpandRDFVolume <id> in RDF group 10 to 30 in GB
+    Then the error message contains <errormsg>
+    And the remote volume of <id> has size 30 in GB if no error
+
+    Examples:
+    | id      | errormsg                  | whitelist | induced            |
+    | "00001" | "none"                    |   ""      | "none"             |
+    | "00003" | "cannot be found"         |   ""      | "none"             |
+    | "00001" | "induced error"           |   ""      | "GetRDFGroupError" |
+    | "00001" | "ignored via a whitelist" | "ignored" | "none"             |
+
+  Scenario: Expand an unprotected volume as an SRDF volume
+    Given a valid connection
+    And I have 2 volumes
+    When I call ExpandRDFVolume "00001" in RDF group 10 to 30 in GB
+    Then the error message contains "cannot be found in the RDF group"
+
+  Scenario Outline: Create a volume in an SRDF protected storage group
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 2 volumes
+    And I have an SRDF protected storage group in RDF group <rdfg>
+    And I induce error <induced>
+    When I call CreateRDFVolumeInStorageGroup with name "rdfVol" and size 10
+    Then the error message contains <errormsg>
+    And I get a valid RDF device pair in state <state> if no error
+
+    Examples:
+    | rdfg | state          | errormsg                  | whitelist | induced                   |
+    | 10   | "Synchronized" | "none"                    |   ""      | "none"                    |
+    | 11   | "Consistent"   | "none"                    |   ""      | "none"                    |
+    | 10   | "none"         | "induced error"           |   ""      | "GetRDFGroupError"        |
+    | 10   | "none"         | "induced error"           |   ""      | "UpdateStorageGroupError" |
+    | 10   | "none"         | "job failed"              |   ""      | "JobFailedError"          |
+    | 10   | "none"         | "ignored via a whitelist" | "ignored" | "none"                    |
+
+  Scenario Outline: Create a volume in an SRDF protected storage group without its remote storage group
+    Given a valid connection
+    And I have 2 volumes
+    And I have an SRDF protected storage group in RDF group 10
+    And I induce error <induced>
+    When I call CreateVolumeInStorageGroup with name "rdfVol" and size 10
+    Then the error message contains <errormsg>
+    And the created volume is paired in state "Synchronized" if no error
+
+    Examples:
+    | errormsg        | induced            |
+    | "none"          | "none"             |
+    | "induced error" | "GetRDFGroupError" |
+
+  Scenario: Create a volume in a storage group protected in several RDF groups
+    Given a valid connection
+    And I have 2 volumes
+    And I have an SRDF protected storage group in RDF group 10
+    And I have an SRDF protected storage group in RDF group 11
+    When I call CreateVolumeInStorageGroup with name "rdfVol" and size 10
+    Then the error message contains "SRDF protected in 2 RDF groups"
+
+  Scenario: Create an SRDF volume in a storage group protected in several RDF groups
+    Given a valid connection
+    And I have 2 volumes
+    And I have an SRDF protected storage group in RDF group 10
+    And I have an SRDF protected storage group in RDF group 11
+    When I call CreateRDFVolumeInStorageGroup with name "rdfVol" and size 10
+    Then the error message contains "SRDF protected in 2 RDF groups"
+
+  Scenario: Create an SRDF volume in an unprotected storage group
+    Given a valid connection
+    And I have 2 volumes
+    When I call CreateRDFVolumeInStorageGroup with name "rdfVol" and size 10
+    Then the error message contains "is not SRDF protected"
+
+  Scenario Outline: Get the SRDF/Metro status of a storage group
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 3 volumes
+    And I have an SRDF protected storage group in RDF group <rdfg>
+    And RDF group <rdfg> has witness <witness>
+    And the SRDF pair of volume <r2> in RDF group <rdfg> is an R2
+    And I induce error <induced>
+    When I call GetMetroStatus on RDF group <rdfg>
+    Then the error message contains <errormsg>
+    And I get a Metro status in state <state> with bias on the <array> array and witness <witness> if no error
+
+    Examples:
+    | rdfg | witness    | state          | errormsg                     | whitelist | induced            | array     | r2      |
+    | 12   | "none"     | "ActiveBias"   | "none"                       | ""        | "none"             | "local"   | ""      |
+    | 12   | "Physical" | "ActiveActive" | "none"                       | ""        | "none"             | "local"   | ""      |
+    | 12   | "Virtual"  | "ActiveActive" | "none"                       | ""        | "none"             | "local"   | ""      |
+    | 12   | "degraded" | "ActiveBias"   | "none"                       | ""        | "none"             | "local"   | ""      |
+    | 12   | "none"     | "ActiveBias"   | "none"                       | ""        | "none"             | "unknown" | "00002" |
+    | 10   | "none"     | "none"         | "is not an SRDF/Metro group" | ""        | "none"             | "local"   | ""      |
+    | 12   | "none"     | "none"         | "induced error"              | ""        | "GetRDFGroupError" | "local"   | ""      |
+    | 12   | "none"     | "none"         | "ignored via a whitelist"    | "ignored" | "none"             | "local"   | ""      |
+
+  Scenario Outline: Move the bias of SRDF/Metro pairs
+    Given a valid connection
+    And I have a whitelist of <whitelist>
+    And I have 3 volumes
+    And I have an SRDF protected storage group in RDF group <rdfg>
+    And RDF group <rdfg> has witness <witness>
+    And I call ExecuteSRDFAction <actions> on RDF group <rdfg>
+    And I induce error <induced>
+    When I call SetMetroBias <bias> on RDF group <rdfg>
+    Then the error message contains <errormsg>
+    And the Metro bias of the storage group in RDF group <rdfg> is on the <array> array if no error
+
+    Examples:
+    | rdfg | witness    | actions   | bias | errormsg                           | whitelist | induced                | array    |
+    | 12   | "none"     | ""        | "R2" | "none"                             | ""        | "none"                 | "remote" |
+    | 12   | "none"     | ""        | "R1" | "none"                             | ""        | "none"                 | "local"  |
+    | 12   | "none"     | "Suspend" | "R2" | "none"                             | ""        | "none"                 | "remote" |
+    | 12   | "degraded" | ""        | "R2" | "none"                             | ""        | "none"                 | "remote" |
+    | 12   | "Physical" | ""        | "R2" | "witness is in effect"             | ""        | "none"                 | "remote" |
+    | 12   | "none"     | "Split"   | "R2" | "not valid in state Split"         | ""        | "none"                 | "remote" |
+    | 12   | "none"     | ""        | "R3" | "not a valid SRDF/Metro bias side" | ""        | "none"                 | "local"  |
+    | 10   | "none"     | ""        | "R2" | "only be set on SRDF/Metro pairs"  | ""        | "none"                 | "remote" |
+    | 12   | "none"     | ""        | "R2" | "induced error"                    | ""        | "ModifySGReplicaError" | "remote" |
+    | 12   | "none"     | ""        | "R2" | "Job status not successful"        | ""        | "JobFailedError"       | "remote" |
+    | 12   | "none"     | ""        | "R2" | "ignored via a whitelist"          | "ignored" | "none"                 | "remote" |